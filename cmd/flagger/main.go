@@ -30,8 +30,14 @@ import (
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/leaderelection"
@@ -39,6 +45,7 @@ import (
 	"k8s.io/client-go/transport"
 	_ "k8s.io/code-generator/cmd/client-gen/generators"
 	"k8s.io/klog/v2"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"github.com/fluxcd/flagger/pkg/canary"
 	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
@@ -86,6 +93,8 @@ var (
 	kubeconfigServiceMesh    string
 	clusterName              string
 	noCrossNamespaceRefs     bool
+	maxConcurrentCanaries    int
+	maxConcurrentCanariesNs  int
 )
 
 func init() {
@@ -93,7 +102,7 @@ func init() {
 	flag.IntVar(&kubeconfigQPS, "kubeconfig-qps", 100, "Set QPS for kubeconfig.")
 	flag.IntVar(&kubeconfigBurst, "kubeconfig-burst", 250, "Set Burst for kubeconfig.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
-	flag.StringVar(&metricsServer, "metrics-server", "http://prometheus:9090", "Prometheus URL.")
+	flag.StringVar(&metricsServer, "metrics-server", "http://prometheus:9090", "Prometheus URL, or cloudwatch://<region> for AWS CloudWatch.")
 	flag.DurationVar(&controlLoopInterval, "control-loop-interval", 10*time.Second, "Kubernetes API sync interval.")
 	flag.StringVar(&logLevel, "log-level", "debug", "Log level can be: debug, info, warning, error.")
 	flag.StringVar(&port, "port", "8080", "Port to listen on.")
@@ -121,6 +130,8 @@ func init() {
 	flag.StringVar(&kubeconfigServiceMesh, "kubeconfig-service-mesh", "", "Path to a kubeconfig for the service mesh control plane cluster.")
 	flag.StringVar(&clusterName, "cluster-name", "", "Cluster name to be included in alert msgs.")
 	flag.BoolVar(&noCrossNamespaceRefs, "no-cross-namespace-refs", false, "When set to true, Flagger can only refer to resources in the same namespace.")
+	flag.IntVar(&maxConcurrentCanaries, "max-concurrent-canaries", 0, "Max number of canaries under analysis at the same time, zero means no limit.")
+	flag.IntVar(&maxConcurrentCanariesNs, "max-concurrent-canaries-per-namespace", 0, "Max number of canaries under analysis at the same time in a namespace, zero means no limit.")
 }
 
 func main() {
@@ -161,6 +172,17 @@ func main() {
 		logger.Fatalf("Error building kubernetes clientset: %v", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatalf("Error building dynamic client: %v", err)
+	}
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery()))
+
+	kubeMetricsClient, err := metricsclientset.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatalf("Error building kubernetes metrics clientset: %v", err)
+	}
+
 	flaggerClient, err := clientset.NewForConfig(cfg)
 	if err != nil {
 		logger.Fatalf("Error building flagger clientset: %s", err.Error())
@@ -186,6 +208,7 @@ func main() {
 	verifyCRDs(flaggerClient, logger)
 	verifyKubernetesVersion(kubeClient, logger)
 	infos := startInformers(flaggerClient, logger, stopCh)
+	pdbLister, vpaLister := startCompanionInformers(kubeClient, dynamicClient, logger, stopCh)
 
 	labels := strings.Split(selectorLabels, ",")
 	if len(labels) < 1 {
@@ -229,6 +252,8 @@ func main() {
 			Logger:        logger,
 			KubeClient:    kubeClient,
 			FlaggerClient: flaggerClient,
+			DynamicClient: dynamicClient,
+			RESTMapper:    restMapper,
 		}
 	} else {
 		configTracker = &canary.NopTracker{}
@@ -236,10 +261,11 @@ func main() {
 
 	includeLabelPrefixArray := strings.Split(includeLabelPrefix, ",")
 
-	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, configTracker, labels, includeLabelPrefixArray, logger)
+	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, dynamicClient, restMapper, pdbLister, vpaLister, configTracker, labels, includeLabelPrefixArray, logger)
 
 	c := controller.NewController(
 		kubeClient,
+		kubeMetricsClient,
 		flaggerClient,
 		infos,
 		controlLoopInterval,
@@ -253,6 +279,8 @@ func main() {
 		fromEnv("EVENT_WEBHOOK_URL", eventWebhook),
 		clusterName,
 		noCrossNamespaceRefs,
+		maxConcurrentCanaries,
+		maxConcurrentCanariesNs,
 	)
 
 	// leader election context
@@ -318,6 +346,35 @@ func startInformers(flaggerClient clientset.Interface, logger *zap.SugaredLogger
 	}
 }
 
+// startCompanionInformers starts the informers of the PodDisruptionBudgets and VerticalPodAutoscalers
+// reconciled for the primary workloads, the VerticalPodAutoscaler lister is nil if the CRD is not installed
+func startCompanionInformers(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+	logger *zap.SugaredLogger, stopCh <-chan struct{}) (policylisters.PodDisruptionBudgetLister, cache.GenericLister) {
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, time.Second*30, kubeinformers.WithNamespace(namespace))
+
+	logger.Info("Waiting for pod disruption budget informer cache to sync")
+	pdbInformer := kubeInformerFactory.Policy().V1().PodDisruptionBudgets()
+	go pdbInformer.Informer().Run(stopCh)
+	if ok := cache.WaitForNamedCacheSync("flagger", stopCh, pdbInformer.Informer().HasSynced); !ok {
+		logger.Fatalf("failed to wait for cache to sync")
+	}
+
+	if _, err := kubeClient.Discovery().ServerResourcesForGroupVersion(canary.VPAResource.GroupVersion().String()); err != nil {
+		logger.Infof("VerticalPodAutoscaler API is not available, skipping VPA reconciliation: %v", err)
+		return pdbInformer.Lister(), nil
+	}
+
+	logger.Info("Waiting for vertical pod autoscaler informer cache to sync")
+	dynamicInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, time.Second*30, namespace, nil)
+	vpaInformer := dynamicInformerFactory.ForResource(canary.VPAResource)
+	go vpaInformer.Informer().Run(stopCh)
+	if ok := cache.WaitForNamedCacheSync("flagger", stopCh, vpaInformer.Informer().HasSynced); !ok {
+		logger.Fatalf("failed to wait for cache to sync")
+	}
+
+	return pdbInformer.Lister(), vpaInformer.Lister()
+}
+
 func startLeaderElection(ctx context.Context, run func(), ns string, kubeClient kubernetes.Interface, logger *zap.SugaredLogger) {
 	configMapName := "flagger-leader-election"
 	id, err := os.Hostname()
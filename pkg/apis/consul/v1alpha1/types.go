@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceSplitter is the Schema for the service-splitter config entries API
+type ServiceSplitter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceSplitterSpec `json:"spec,omitempty"`
+	Status Status              `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceSplitterList is a list of ServiceSplitter resources
+type ServiceSplitterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceSplitter `json:"items"`
+}
+
+// ServiceSplitterSpec defines how to split incoming requests across
+// different subsets of a single service or across different services
+type ServiceSplitterSpec struct {
+	// Splits is the list of weighted destinations, the weights must add up to 100.
+	Splits []ServiceSplit `json:"splits,omitempty"`
+}
+
+// ServiceSplit defines how much traffic to send to which set of service instances during a traffic split
+type ServiceSplit struct {
+	// Weight is a value between 0 and 100 reflecting what portion of traffic should be directed to this split.
+	Weight float32 `json:"weight"`
+
+	// Service is the service to resolve instead of the default.
+	Service string `json:"service,omitempty"`
+
+	// ServiceSubset is a named subset of the given service to resolve instead of one defined
+	// as that service's DefaultSubset.
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Namespace is the Consul namespace to resolve the service from instead of the current namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceResolver is the Schema for the service-resolver config entries API
+type ServiceResolver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceResolverSpec `json:"spec,omitempty"`
+	Status Status              `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceResolverList is a list of ServiceResolver resources
+type ServiceResolverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceResolver `json:"items"`
+}
+
+// ServiceResolverSpec defines which instances of a service
+// should satisfy discovery requests for a given named service
+type ServiceResolverSpec struct {
+	// DefaultSubset is the subset to use when no explicit subset is requested.
+	DefaultSubset string `json:"defaultSubset,omitempty"`
+
+	// Subsets is map of subset name to subset definition for all usable named subsets of this service.
+	Subsets map[string]ServiceResolverSubset `json:"subsets,omitempty"`
+}
+
+// ServiceResolverSubset defines a named subset of service instances
+type ServiceResolverSubset struct {
+	// Filter is the filter expression to be used for selecting instances of the requested service.
+	Filter string `json:"filter,omitempty"`
+
+	// OnlyPassing specifies the behavior of the resolver's health check filtering.
+	OnlyPassing bool `json:"onlyPassing,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceRouter is the Schema for the service-router config entries API
+type ServiceRouter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceRouterSpec `json:"spec,omitempty"`
+	Status Status            `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceRouterList is a list of ServiceRouter resources
+type ServiceRouterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceRouter `json:"items"`
+}
+
+// ServiceRouterSpec defines the HTTP routes of a service
+type ServiceRouterSpec struct {
+	// Routes are the list of routes to consider when processing L7 requests.
+	// The first route to match in the list is terminal and stops further evaluation.
+	// Traffic that fails to match any of the provided routes will be routed to the default service.
+	Routes []ServiceRoute `json:"routes,omitempty"`
+}
+
+// ServiceRoute defines a single HTTP route
+type ServiceRoute struct {
+	// Match is a set of criteria that can match incoming L7 requests.
+	Match *ServiceRouteMatch `json:"match,omitempty"`
+
+	// Destination controls how to proxy the matching request(s) to a service.
+	Destination *ServiceRouteDestination `json:"destination,omitempty"`
+}
+
+// ServiceRouteMatch defines the criteria of a route
+type ServiceRouteMatch struct {
+	// HTTP is a set of http-specific match criteria.
+	HTTP *ServiceRouteHTTPMatch `json:"http,omitempty"`
+}
+
+// ServiceRouteHTTPMatch defines the HTTP criteria of a route
+type ServiceRouteHTTPMatch struct {
+	// PathExact is an exact path to match on the HTTP request path.
+	PathExact string `json:"pathExact,omitempty"`
+
+	// PathPrefix is a path prefix to match on the HTTP request path.
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// PathRegex is a regular expression to match on the HTTP request path.
+	PathRegex string `json:"pathRegex,omitempty"`
+
+	// Header is a set of criteria that can match on HTTP request headers.
+	// If more than one is configured all must match for the overall match to apply.
+	Header []ServiceRouteHTTPMatchHeader `json:"header,omitempty"`
+
+	// QueryParam is a set of criteria that can match on HTTP query parameters.
+	// If more than one is configured all must match for the overall match to apply.
+	QueryParam []ServiceRouteHTTPMatchQueryParam `json:"queryParam,omitempty"`
+
+	// Methods is a list of HTTP methods for which this match applies.
+	Methods []string `json:"methods,omitempty"`
+}
+
+// ServiceRouteHTTPMatchHeader defines a HTTP header match
+type ServiceRouteHTTPMatchHeader struct {
+	// Name is the name of the header to match.
+	Name string `json:"name"`
+
+	// Present will match if the header with the given name is present with any value.
+	Present bool `json:"present,omitempty"`
+
+	// Exact will match if the header with the given name is this value.
+	Exact string `json:"exact,omitempty"`
+
+	// Prefix will match if the header with the given name has this prefix.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Suffix will match if the header with the given name has this suffix.
+	Suffix string `json:"suffix,omitempty"`
+
+	// Regex will match if the header with the given name matches this pattern.
+	Regex string `json:"regex,omitempty"`
+
+	// Invert inverts the logic of the match.
+	Invert bool `json:"invert,omitempty"`
+}
+
+// ServiceRouteHTTPMatchQueryParam defines a HTTP query parameter match
+type ServiceRouteHTTPMatchQueryParam struct {
+	// Name is the name of the query parameter to match on.
+	Name string `json:"name"`
+
+	// Present will match if the query parameter with the given name is present with any value.
+	Present bool `json:"present,omitempty"`
+
+	// Exact will match if the query parameter with the given name is this value.
+	Exact string `json:"exact,omitempty"`
+
+	// Regex will match if the query parameter with the given name matches this pattern.
+	Regex string `json:"regex,omitempty"`
+}
+
+// ServiceRouteDestination defines the target of a route
+type ServiceRouteDestination struct {
+	// Service is the service to resolve instead of the default service.
+	Service string `json:"service,omitempty"`
+
+	// ServiceSubset is a named subset of the given service to resolve instead
+	// of the one defined as that service's DefaultSubset.
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Namespace is the Consul namespace to resolve the service from instead of the current one.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Status defines the observed state of a config entry
+type Status struct {
+	// Conditions indicate the latest available observations of a resource's current state.
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// LastSyncedTime is the last time the resource successfully synced with Consul.
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+}
+
+// ConditionType is a camel-cased condition type
+type ConditionType string
+
+const (
+	// ConditionSynced specifies that the resource has been synced with Consul.
+	ConditionSynced ConditionType = "Synced"
+)
+
+// Condition defines an observation of a config entry operational state
+type Condition struct {
+	// Type of condition.
+	Type ConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
@@ -33,6 +34,20 @@ const (
 	PrimaryReadyThreshold   = 100
 	CanaryReadyThreshold    = 100
 	MetricInterval          = "1m"
+
+	// KnativePrimaryRevisionAnnotation holds the name of the promoted revision of a Knative service
+	KnativePrimaryRevisionAnnotation = "flagger.app/primary-revision"
+
+	// MaxConcurrentCanariesAnnotation set on a namespace limits the number of canaries under analysis in that namespace
+	MaxConcurrentCanariesAnnotation = "flagger.app/max-concurrent-canaries"
+
+	// ScalerBoundsAnnotation holds the min and max replicas of the canary autoscaler
+	// while its bounds are set by the replica sizing
+	ScalerBoundsAnnotation = "flagger.app/scaler-bounds"
+
+	// ReplicasAnnotation holds the replicas of the canary workload
+	// while its replicas are set by the replica sizing
+	ReplicasAnnotation = "flagger.app/replicas"
 )
 
 // +genclient
@@ -65,6 +80,11 @@ type CanarySpec struct {
 	// +optional
 	Provider string `json:"provider,omitempty"`
 
+	// Providers overwrites the -mesh-provider flag and Provider with a list of providers,
+	// the traffic is shifted on all of them with the same weights
+	// +optional
+	Providers []string `json:"providers,omitempty"`
+
 	// MetricsServer overwrites the -metrics-server flag for this particular canary
 	// +optional
 	MetricsServer string `json:"metricsServer,omitempty"`
@@ -72,14 +92,35 @@ type CanarySpec struct {
 	// TargetRef references a target resource
 	TargetRef LocalObjectReference `json:"targetRef"`
 
+	// Workload holds the field paths of a target kind other than Deployment, DaemonSet and Service
+	// +optional
+	Workload *CanaryWorkload `json:"workload,omitempty"`
+
 	// AutoscalerRef references an autoscaling resource
 	// +optional
 	AutoscalerRef *AutoscalerRefernce `json:"autoscalerRef,omitempty"`
 
+	// ReplicaSizing scales the canary workload proportionally to its traffic weight
+	// +optional
+	ReplicaSizing *CanaryReplicaSizing `json:"replicaSizing,omitempty"`
+
+	// PrimaryPreScaling raises the primary autoscaler min replicas before
+	// the traffic is routed back to the primary on promotion
+	// +optional
+	PrimaryPreScaling PrimaryPreScalingMode `json:"primaryPreScaling,omitempty"`
+
+	// PrimaryOverrides patches the primary pod template on initialization and promotion
+	// +optional
+	PrimaryOverrides *CanaryPrimaryOverrides `json:"primaryOverrides,omitempty"`
+
 	// Reference to NGINX ingress resource
 	// +optional
 	IngressRef *LocalObjectReference `json:"ingressRef,omitempty"`
 
+	// References to NGINX ingress resources, the canary traffic is shifted on all of them
+	// +optional
+	IngressRefs []LocalObjectReference `json:"ingressRefs,omitempty"`
+
 	// Reference to APISIX route resource
 	// +optional
 	RouteRef *LocalObjectReference `json:"routeRef,omitempty"`
@@ -103,6 +144,22 @@ type CanarySpec struct {
 	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 
+	// Bundle is the name of the release bundle the canary belongs to,
+	// the canaries of a bundle in the same namespace are advanced in lockstep
+	// and are promoted or rolled back as a unit
+	// +optional
+	Bundle string `json:"bundle,omitempty"`
+
+	// Priority of the canary in the rollout queue, canaries with a higher
+	// priority start their analysis first when the concurrency limit is reached
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// DependsOn lists the canaries that must be promoted
+	// before the analysis of this canary can start
+	// +optional
+	DependsOn []CanaryDependency `json:"dependsOn,omitempty"`
+
 	// SkipAnalysis promotes the canary without analysing it
 	// +optional
 	SkipAnalysis bool `json:"skipAnalysis,omitempty"`
@@ -154,6 +211,12 @@ type CanaryService struct {
 	// +optional
 	GatewayRefs []v1beta1.ParentReference `json:"gatewayRefs,omitempty"`
 
+	// RouteKind is the kind of the route generated by the Gateway API v1 provider,
+	// can be HTTPRoute, GRPCRoute, TCPRoute or TLSRoute.
+	// Defaults to HTTPRoute
+	// +optional
+	RouteKind string `json:"routeKind,omitempty"`
+
 	// Hosts attached to the generated Istio virtual service or Gateway API HTTPRoute.
 	// Defaults to the service name
 	// +optional
@@ -270,6 +333,81 @@ type CanaryAnalysis struct {
 	// SessionAffinity represents the session affinity settings for a canary run.
 	// +optional
 	SessionAffinity *SessionAffinity `json:"sessionAffinity,omitempty"`
+
+	// Health checks of the canary pods run during the analysis
+	// +optional
+	PodHealth *CanaryPodHealth `json:"podHealth,omitempty"`
+
+	// NodeRollout rolls out a DaemonSet canary to a growing subset of nodes
+	// +optional
+	NodeRollout *NodeRollout `json:"nodeRollout,omitempty"`
+}
+
+// CanaryWorkload holds the field paths of a custom workload kind
+// that has a pod template and a scale subresource, e.g. an OpenKruise CloneSet
+type CanaryWorkload struct {
+	// TemplatePath is the JSONPath of the pod template, defaults to .spec.template
+	// +optional
+	TemplatePath string `json:"templatePath,omitempty"`
+
+	// SelectorPath is the JSONPath of the pod label selector, defaults to .spec.selector
+	// +optional
+	SelectorPath string `json:"selectorPath,omitempty"`
+
+	// ReplicasPath is the JSONPath of the desired replicas, defaults to .spec.replicas
+	// +optional
+	ReplicasPath string `json:"replicasPath,omitempty"`
+
+	// StatusPath is the JSONPath of the status holding the replicas counters
+	// and the conditions in the Deployment status format, defaults to .status
+	// +optional
+	StatusPath string `json:"statusPath,omitempty"`
+
+	// ReadyCondition is the type of the status condition that must be true
+	// for the workload to be considered ready
+	// +optional
+	ReadyCondition string `json:"readyCondition,omitempty"`
+}
+
+// NodeRollout holds the selection of the nodes a DaemonSet canary is rolled out to,
+// the analysis weights are mapped to the percentage of the selected nodes running the canary
+type NodeRollout struct {
+	// NodeSelector restricts the rollout to the nodes matching these labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// PoolLabel is the node label holding the node pool name
+	// +optional
+	PoolLabel string `json:"poolLabel,omitempty"`
+
+	// NodePools restricts the rollout to these node pools,
+	// the nodes are rolled out pool by pool in the given order
+	// +optional
+	NodePools []string `json:"nodePools,omitempty"`
+}
+
+// CanaryPodHealth holds the thresholds of the canary pods health checks
+type CanaryPodHealth struct {
+	// Max number of container restarts accepted for the canary pods
+	// +optional
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// Max number of warning events accepted for the canary pods
+	// since the analysis started
+	// +optional
+	MaxWarningEvents *int32 `json:"maxWarningEvents,omitempty"`
+
+	// Fail the check when a canary container is terminated with OOMKilled
+	// +optional
+	OOMKilled bool `json:"oomKilled,omitempty"`
+
+	// Fail the check when a canary container is in CrashLoopBackOff
+	// +optional
+	CrashLoopBackOff bool `json:"crashLoopBackOff,omitempty"`
+
+	// Rollback the canary when a check fails instead of counting a failed check
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
 }
 
 type SessionAffinity struct {
@@ -431,6 +569,71 @@ type LocalObjectReference struct {
 	Name string `json:"name"`
 }
 
+// IsKnativeService returns true if the object is a Knative Serving service
+func (r LocalObjectReference) IsKnativeService() bool {
+	return r.Kind == "Service" && strings.HasPrefix(r.APIVersion, "serving.knative.dev/")
+}
+
+// CanaryDependency references a canary that must be promoted
+// before the analysis of the dependent canary starts
+type CanaryDependency struct {
+	// Name of the canary
+	Name string `json:"name"`
+
+	// Namespace of the canary, defaults to the namespace of the dependent canary
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// RevisionLabel is the name of a pod template label, e.g. app.kubernetes.io/version,
+	// that must have the same value on the promoted dependency and the dependent target
+	// +optional
+	RevisionLabel string `json:"revisionLabel,omitempty"`
+}
+
+// CanaryReplicaSizing sizes the canary replicas to the traffic weight it receives
+// instead of the capacity of the primary
+type CanaryReplicaSizing struct {
+	// MinReplicas is the minimum number of canary replicas (default 1)
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Headroom is the percentage of replicas added to the weight proportional count
+	// +optional
+	Headroom int `json:"headroom,omitempty"`
+}
+
+// PrimaryPreScalingMode sets how the primary replicas are computed before promotion
+type PrimaryPreScalingMode string
+
+const (
+	// PrimaryPreScalingCanary scales the primary to at least the canary replicas
+	PrimaryPreScalingCanary PrimaryPreScalingMode = "canary"
+	// PrimaryPreScalingCapacity scales the primary to the replicas needed to serve
+	// all the traffic, extrapolated from the replicas and weights of the primary and canary
+	PrimaryPreScalingCapacity PrimaryPreScalingMode = "capacity"
+)
+
+// CanaryPrimaryOverrides holds a patch applied to the primary pod template
+type CanaryPrimaryOverrides struct {
+	// Type of the patch, can be strategic or json, defaults to strategic
+	// +optional
+	Type PrimaryOverridesPatchType `json:"type,omitempty"`
+
+	// Patch in YAML or JSON format, a strategic merge patch
+	// or a list of JSON patch operations
+	Patch string `json:"patch"`
+}
+
+// PrimaryOverridesPatchType sets how the primary overrides are applied
+type PrimaryOverridesPatchType string
+
+const (
+	// PrimaryOverridesStrategicMergePatch applies the overrides as a strategic merge patch
+	PrimaryOverridesStrategicMergePatch PrimaryOverridesPatchType = "strategic"
+	// PrimaryOverridesJSONPatch applies the overrides as a list of JSON patch operations
+	PrimaryOverridesJSONPatch PrimaryOverridesPatchType = "json"
+)
+
 type AutoscalerRefernce struct {
 	// API version of the scaler
 	// +optional
@@ -475,6 +678,70 @@ func (c *Canary) GetServiceNames() (apexName, primaryName, canaryName string) {
 	return
 }
 
+// GetProviders returns the providers of this canary, defaults to the given provider
+func (c *Canary) GetProviders(defaultProvider string) []string {
+	if len(c.Spec.Providers) > 0 {
+		return c.Spec.Providers
+	}
+	if c.Spec.Provider != "" {
+		return []string{c.Spec.Provider}
+	}
+	return []string{defaultProvider}
+}
+
+// GetIngressRefs returns the ingress references from ingressRef and ingressRefs without duplicates
+func (c *Canary) GetIngressRefs() []LocalObjectReference {
+	var refs []LocalObjectReference
+	seen := make(map[string]bool)
+	add := func(ref LocalObjectReference) {
+		if ref.Name == "" || seen[ref.Name] {
+			return
+		}
+		seen[ref.Name] = true
+		refs = append(refs, ref)
+	}
+
+	if c.Spec.IngressRef != nil {
+		add(*c.Spec.IngressRef)
+	}
+	for _, ref := range c.Spec.IngressRefs {
+		add(ref)
+	}
+	return refs
+}
+
+// GetWorkload returns the workload field paths with the defaults applied
+func (c *Canary) GetWorkload() CanaryWorkload {
+	workload := CanaryWorkload{}
+	if c.Spec.Workload != nil {
+		workload = *c.Spec.Workload
+	}
+	if workload.TemplatePath == "" {
+		workload.TemplatePath = ".spec.template"
+	}
+	if workload.SelectorPath == "" {
+		workload.SelectorPath = ".spec.selector"
+	}
+	if workload.ReplicasPath == "" {
+		workload.ReplicasPath = ".spec.replicas"
+	}
+	if workload.StatusPath == "" {
+		workload.StatusPath = ".status"
+	}
+	return workload
+}
+
+// HasNodeRollout returns true if the DaemonSet canary is rolled out to a subset of nodes
+func (c *Canary) HasNodeRollout() bool {
+	return c.Spec.TargetRef.Kind == "DaemonSet" &&
+		c.GetAnalysis() != nil && c.GetAnalysis().NodeRollout != nil
+}
+
+// GetNodeRolloutLabel returns the label set on the nodes running the DaemonSet canary
+func (c *Canary) GetNodeRolloutLabel() string {
+	return fmt.Sprintf("%s.canary.flagger.app/%s", c.Namespace, c.Name)
+}
+
 // GetProgressDeadlineSeconds returns the progress deadline (default 600s)
 func (c *Canary) GetProgressDeadlineSeconds() int {
 	if c.Spec.ProgressDeadlineSeconds != nil {
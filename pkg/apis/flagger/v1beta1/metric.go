@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"regexp"
+	"strings"
 	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
@@ -89,6 +91,21 @@ type MetricTemplateModel struct {
 	Ingress   string `json:"ingress"`
 	Route     string `json:"route"`
 	Interval  string `json:"interval"`
+
+	// Image of the canary workload main container
+	Image string `json:"image"`
+
+	// ImageTag is the tag of the canary workload main container image
+	ImageTag string `json:"imageTag"`
+
+	// Labels of the canary workload pod template
+	Labels map[string]string `json:"labels"`
+
+	// Ingresses are the names of all the ingresses referenced by the canary
+	Ingresses []string `json:"ingresses"`
+
+	// Revision is the name of the canary Knative revision
+	Revision string `json:"revision"`
 }
 
 // TemplateFunctions returns a map of functions, one for each model field
@@ -101,7 +118,26 @@ func (mtm *MetricTemplateModel) TemplateFunctions() template.FuncMap {
 		"ingress":   func() string { return mtm.Ingress },
 		"route":     func() string { return mtm.Route },
 		"interval":  func() string { return mtm.Interval },
+		"image":     func() string { return mtm.Image },
+		"imageTag":  func() string { return mtm.ImageTag },
+		"labels":    func() map[string]string { return mtm.Labels },
+		"ingresses": mtm.ingresses,
+		"revision":  func() string { return mtm.Revision },
+	}
+}
+
+// ingresses returns the ingress names joined with | to be used in regex matchers,
+// the names are escaped for a double-quoted PromQL string
+func (mtm *MetricTemplateModel) ingresses() string {
+	names := mtm.Ingresses
+	if len(names) == 0 {
+		names = []string{mtm.Ingress}
+	}
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, strings.ReplaceAll(regexp.QuoteMeta(name), `\`, `\\`))
 	}
+	return strings.Join(quoted, "|")
 }
 
 type MetricTemplateStatus struct {
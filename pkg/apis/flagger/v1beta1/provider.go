@@ -15,4 +15,9 @@ const (
 	OsmProvider        string = "osm"
 	KumaProvider       string = "kuma"
 	GatewayAPIProvider string = "gatewayapi"
+	ConsulProvider     string = "consul"
+	ALBProvider        string = "alb"
+	KongProvider       string = "kong"
+	HAProxyProvider    string = "haproxy"
+	KnativeProvider    string = "knative"
 )
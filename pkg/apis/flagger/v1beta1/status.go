@@ -45,6 +45,9 @@ const (
 	CanaryPhaseInitialized CanaryPhase = "Initialized"
 	// CanaryPhaseWaiting means the canary rollout is paused (waiting for confirmation to proceed)
 	CanaryPhaseWaiting CanaryPhase = "Waiting"
+	// CanaryPhaseQueued means the canary analysis is waiting for a free slot
+	// in the rollout queue (concurrency limit reached)
+	CanaryPhaseQueued CanaryPhase = "Queued"
 	// CanaryPhaseProgressing means the canary analysis is underway
 	CanaryPhaseProgressing CanaryPhase = "Progressing"
 	// CanaryPhaseWaitingPromotion means the canary promotion is paused (waiting for confirmation to proceed)
@@ -67,6 +70,19 @@ const (
 	CanaryPhaseTerminated CanaryPhase = "Terminated"
 )
 
+// CanaryPodHealthStatus holds the baseline of the pod health checks
+// recorded when the canary analysis started
+type CanaryPodHealthStatus struct {
+	// Revision is the last applied spec the baseline was recorded for
+	Revision string `json:"revision"`
+	// StartTime is the time the analysis started
+	StartTime metav1.Time `json:"startTime"`
+	// Restarts is the container restart count of each canary pod
+	// when the analysis started
+	// +optional
+	Restarts map[string]int32 `json:"restarts,omitempty"`
+}
+
 // CanaryStatus is used for state persistence (read-only)
 type CanaryStatus struct {
 	Phase        CanaryPhase `json:"phase"`
@@ -80,6 +96,10 @@ type CanaryStatus struct {
 	// +optional
 	TrackedConfigs *map[string]string `json:"trackedConfigs,omitempty"`
 	// +optional
+	QueuePosition int `json:"queuePosition,omitempty"`
+	// +optional
+	PodHealth *CanaryPodHealthStatus `json:"podHealth,omitempty"`
+	// +optional
 	LastAppliedSpec string `json:"lastAppliedSpec,omitempty"`
 	// +optional
 	LastPromotedSpec string `json:"lastPromotedSpec,omitempty"`
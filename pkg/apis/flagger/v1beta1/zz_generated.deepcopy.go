@@ -268,6 +268,16 @@ func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
 		*out = new(SessionAffinity)
 		**out = **in
 	}
+	if in.PodHealth != nil {
+		in, out := &in.PodHealth, &out.PodHealth
+		*out = new(CanaryPodHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeRollout != nil {
+		in, out := &in.NodeRollout, &out.NodeRollout
+		*out = new(NodeRollout)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -299,6 +309,22 @@ func (in *CanaryCondition) DeepCopy() *CanaryCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryDependency) DeepCopyInto(out *CanaryDependency) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryDependency.
+func (in *CanaryDependency) DeepCopy() *CanaryDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryList) DeepCopyInto(out *CanaryList) {
 	*out = *in
@@ -358,6 +384,93 @@ func (in *CanaryMetric) DeepCopy() *CanaryMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPodHealth) DeepCopyInto(out *CanaryPodHealth) {
+	*out = *in
+	if in.MaxRestarts != nil {
+		in, out := &in.MaxRestarts, &out.MaxRestarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxWarningEvents != nil {
+		in, out := &in.MaxWarningEvents, &out.MaxWarningEvents
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPodHealth.
+func (in *CanaryPodHealth) DeepCopy() *CanaryPodHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPodHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPodHealthStatus) DeepCopyInto(out *CanaryPodHealthStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Restarts != nil {
+		in, out := &in.Restarts, &out.Restarts
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPodHealthStatus.
+func (in *CanaryPodHealthStatus) DeepCopy() *CanaryPodHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPodHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPrimaryOverrides) DeepCopyInto(out *CanaryPrimaryOverrides) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPrimaryOverrides.
+func (in *CanaryPrimaryOverrides) DeepCopy() *CanaryPrimaryOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPrimaryOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryReplicaSizing) DeepCopyInto(out *CanaryReplicaSizing) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryReplicaSizing.
+func (in *CanaryReplicaSizing) DeepCopy() *CanaryReplicaSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryReplicaSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryService) DeepCopyInto(out *CanaryService) {
 	*out = *in
@@ -447,17 +560,42 @@ func (in *CanaryService) DeepCopy() *CanaryService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.TargetRef = in.TargetRef
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(CanaryWorkload)
+		**out = **in
+	}
 	if in.AutoscalerRef != nil {
 		in, out := &in.AutoscalerRef, &out.AutoscalerRef
 		*out = new(AutoscalerRefernce)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicaSizing != nil {
+		in, out := &in.ReplicaSizing, &out.ReplicaSizing
+		*out = new(CanaryReplicaSizing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrimaryOverrides != nil {
+		in, out := &in.PrimaryOverrides, &out.PrimaryOverrides
+		*out = new(CanaryPrimaryOverrides)
+		**out = **in
+	}
 	if in.IngressRef != nil {
 		in, out := &in.IngressRef, &out.IngressRef
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.IngressRefs != nil {
+		in, out := &in.IngressRefs, &out.IngressRefs
+		*out = make([]LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.RouteRef != nil {
 		in, out := &in.RouteRef, &out.RouteRef
 		*out = new(LocalObjectReference)
@@ -484,6 +622,11 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]CanaryDependency, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -511,6 +654,11 @@ func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 			}
 		}
 	}
+	if in.PodHealth != nil {
+		in, out := &in.PodHealth, &out.PodHealth
+		*out = new(CanaryPodHealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -608,6 +756,22 @@ func (in *CanaryWebhookPayload) DeepCopy() *CanaryWebhookPayload {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWorkload) DeepCopyInto(out *CanaryWorkload) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWorkload.
+func (in *CanaryWorkload) DeepCopy() *CanaryWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CrossNamespaceObjectReference) DeepCopyInto(out *CrossNamespaceObjectReference) {
 	*out = *in
@@ -752,6 +916,18 @@ func (in *MetricTemplateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricTemplateModel) DeepCopyInto(out *MetricTemplateModel) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -826,6 +1002,34 @@ func (in *MetricTemplateStatus) DeepCopy() *MetricTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRollout) DeepCopyInto(out *NodeRollout) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRollout.
+func (in *NodeRollout) DeepCopy() *NodeRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SessionAffinity) DeepCopyInto(out *SessionAffinity) {
 	*out = *in
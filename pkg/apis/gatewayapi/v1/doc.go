@@ -0,0 +1,6 @@
+// +k8s:deepcopy-gen=package
+
+// Package v1 contains API Schema definitions for the
+// gateway.networking.k8s.io API group.
+
+package v1
@@ -33,6 +33,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&HTTPRoute{},
 		&HTTPRouteList{},
+		&TCPRoute{},
+		&TCPRouteList{},
+		&TLSRoute{},
+		&TLSRouteList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,5 @@
+package knative
+
+const (
+	GroupName = "serving.knative.dev"
+)
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Service is the Schema for the Knative services API
+type Service struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceSpec   `json:"spec,omitempty"`
+	Status ServiceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceList is a list of Service resources
+type ServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Service `json:"items"`
+}
+
+// ServiceSpec holds the revision template and the traffic split of a Knative service
+type ServiceSpec struct {
+	// Template holds the latest specification for the Revision to be stamped out.
+	Template RevisionTemplateSpec `json:"template,omitempty"`
+
+	// Traffic specifies how to distribute traffic over a collection of revisions.
+	Traffic []TrafficTarget `json:"traffic,omitempty"`
+}
+
+// RevisionTemplateSpec describes the data a revision should have when created from a template
+type RevisionTemplateSpec struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RevisionSpec `json:"spec,omitempty"`
+}
+
+// RevisionSpec holds the desired state of a Revision
+type RevisionSpec struct {
+	corev1.PodSpec `json:",inline"`
+
+	// ContainerConcurrency specifies the maximum allowed in-flight requests per container of the Revision.
+	ContainerConcurrency *int64 `json:"containerConcurrency,omitempty"`
+
+	// TimeoutSeconds is the maximum duration in seconds that the request routing layer
+	// will wait for a request delivered to a container to begin replying.
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+}
+
+// TrafficTarget holds a single entry of the routing table
+type TrafficTarget struct {
+	// Tag is optionally used to expose a dedicated url for referencing this target exclusively.
+	Tag string `json:"tag,omitempty"`
+
+	// RevisionName of a specific revision to which to send this portion of traffic.
+	RevisionName string `json:"revisionName,omitempty"`
+
+	// ConfigurationName of a configuration to whose latest revision we will send this portion of traffic.
+	ConfigurationName string `json:"configurationName,omitempty"`
+
+	// LatestRevision may be optionally provided to indicate that the latest
+	// ready Revision of the Configuration should be used for this traffic target.
+	LatestRevision *bool `json:"latestRevision,omitempty"`
+
+	// Percent indicates that percentage based routing should be used and
+	// the value indicates the percent of traffic that is be routed to this Revision.
+	Percent *int64 `json:"percent,omitempty"`
+
+	// URL displays the URL for accessing named traffic targets.
+	URL string `json:"url,omitempty"`
+}
+
+// ServiceStatus defines the observed state of a Knative service
+type ServiceStatus struct {
+	Status `json:",inline"`
+
+	// LatestReadyRevisionName holds the name of the latest Revision stamped out
+	// from this Service's Configuration that has had its "Ready" condition become "True".
+	LatestReadyRevisionName string `json:"latestReadyRevisionName,omitempty"`
+
+	// LatestCreatedRevisionName is the last revision that was created from this Service's Configuration.
+	LatestCreatedRevisionName string `json:"latestCreatedRevisionName,omitempty"`
+
+	// URL holds the url that will distribute traffic over the provided traffic targets.
+	URL string `json:"url,omitempty"`
+
+	// Traffic holds the configured traffic distribution.
+	Traffic []TrafficTarget `json:"traffic,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Revision is an immutable snapshot of code and configuration
+type Revision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RevisionSpec   `json:"spec,omitempty"`
+	Status RevisionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RevisionList is a list of Revision resources
+type RevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Revision `json:"items"`
+}
+
+// RevisionStatus communicates the observed state of a Revision
+type RevisionStatus struct {
+	Status `json:",inline"`
+
+	// ActualReplicas reflects the amount of ready pods running this revision.
+	ActualReplicas *int32 `json:"actualReplicas,omitempty"`
+
+	// DesiredReplicas reflects the desired amount of pods running this revision.
+	DesiredReplicas *int32 `json:"desiredReplicas,omitempty"`
+}
+
+// Status is the minimally expected status subresource of a Knative resource
+type Status struct {
+	// ObservedGeneration is the 'Generation' of the resource that was last processed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions the latest available observations of a resource's current state.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionType is a camel-cased condition type
+type ConditionType string
+
+const (
+	// ConditionReady specifies that the resource is ready.
+	ConditionReady ConditionType = "Ready"
+
+	// ConditionRoutesReady specifies that the service route is ready to serve traffic.
+	ConditionRoutesReady ConditionType = "RoutesReady"
+)
+
+// Condition defines a readiness condition for a Knative resource
+type Condition struct {
+	// Type of condition.
+	Type ConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Severity with which to treat failures of this type of condition.
+	Severity string `json:"severity,omitempty"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
+
+// GetCondition returns the condition of the given type or nil if it's missing
+func (s *Status) GetCondition(t ConditionType) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
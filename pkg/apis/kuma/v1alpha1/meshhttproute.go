@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Kuma authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+
+// MeshHTTPRoute is the Schema for the targetRef based HTTP routing policy API.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type MeshHTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MeshHTTPRouteSpec `json:"spec,omitempty"`
+}
+
+// MeshHTTPRouteList defines a list of MeshHTTPRoute objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type MeshHTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeshHTTPRoute `json:"items"`
+}
+
+// MeshHTTPRouteSpec defines the spec for a MeshHTTPRoute.
+type MeshHTTPRouteSpec struct {
+	// TargetRef is a reference to the resource the policy takes an effect on.
+	// The resource could be either a real store object or virtual resource
+	// defined in place.
+	TargetRef TargetRef `json:"targetRef"`
+
+	// To matches destination services of requests and holds configuration.
+	To []MeshHTTPRouteTo `json:"to,omitempty"`
+}
+
+// TargetRefKind is the kind of the resource referenced by a TargetRef.
+type TargetRefKind string
+
+const (
+	TargetRefKindMesh          TargetRefKind = "Mesh"
+	TargetRefKindMeshSubset    TargetRefKind = "MeshSubset"
+	TargetRefKindMeshService   TargetRefKind = "MeshService"
+	TargetRefKindMeshGateway   TargetRefKind = "MeshGateway"
+	TargetRefKindMeshHTTPRoute TargetRefKind = "MeshHTTPRoute"
+)
+
+// TargetRef defines structure that allows attaching policy to various objects.
+type TargetRef struct {
+	// Kind of the referenced resource.
+	Kind TargetRefKind `json:"kind,omitempty"`
+
+	// Name of the referenced resource. Can only be used with kinds: `MeshService`,
+	// `MeshServiceSubset` and `MeshGatewayRoute`.
+	Name string `json:"name,omitempty"`
+
+	// Tags used to select a subset of proxies by tags. Can only be used with kinds
+	// `MeshSubset` and `MeshServiceSubset`.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// MeshHTTPRouteTo holds the routing rules for a destination service.
+type MeshHTTPRouteTo struct {
+	// TargetRef is a reference to the resource that represents a group of
+	// request destinations.
+	TargetRef TargetRef `json:"targetRef,omitempty"`
+
+	// Rules contains the routing rules applies to a combination of top-level
+	// targetRef and the targetRef in this entry.
+	Rules []MeshHTTPRouteRule `json:"rules,omitempty"`
+}
+
+// MeshHTTPRouteRule defines the matches and the backends of a route.
+type MeshHTTPRouteRule struct {
+	// Matches describes how to match HTTP requests this rule should be applied to.
+	Matches []MeshHTTPRouteMatch `json:"matches"`
+
+	// Default holds routing rules that can be merged with rules from other
+	// policies.
+	Default MeshHTTPRouteRuleConf `json:"default"`
+}
+
+// MeshHTTPRouteMatch defines the conditions a request must satisfy to match a rule.
+type MeshHTTPRouteMatch struct {
+	Path        *PathMatch         `json:"path,omitempty"`
+	Method      *string            `json:"method,omitempty"`
+	QueryParams []QueryParamsMatch `json:"queryParams,omitempty"`
+	Headers     []HeaderMatch      `json:"headers,omitempty"`
+}
+
+// PathMatchType is the type of match for the request path.
+type PathMatchType string
+
+const (
+	PathMatchExact             PathMatchType = "Exact"
+	PathMatchPathPrefix        PathMatchType = "PathPrefix"
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// PathMatch defines a request path matcher.
+type PathMatch struct {
+	Value string        `json:"value"`
+	Type  PathMatchType `json:"type"`
+}
+
+// QueryParamsMatchType is the type of match for a query parameter.
+type QueryParamsMatchType string
+
+const (
+	QueryParamsMatchExact             QueryParamsMatchType = "Exact"
+	QueryParamsMatchRegularExpression QueryParamsMatchType = "RegularExpression"
+)
+
+// QueryParamsMatch defines a query parameter matcher.
+type QueryParamsMatch struct {
+	Type  QueryParamsMatchType `json:"type"`
+	Name  string               `json:"name"`
+	Value string               `json:"value"`
+}
+
+// HeaderMatchType is the type of match for a request header.
+type HeaderMatchType string
+
+const (
+	HeaderMatchExact             HeaderMatchType = "Exact"
+	HeaderMatchPresent           HeaderMatchType = "Present"
+	HeaderMatchRegularExpression HeaderMatchType = "RegularExpression"
+	HeaderMatchAbsent            HeaderMatchType = "Absent"
+	HeaderMatchPrefix            HeaderMatchType = "Prefix"
+)
+
+// HeaderMatch defines a request header matcher.
+type HeaderMatch struct {
+	Type  *HeaderMatchType `json:"type,omitempty"`
+	Name  string           `json:"name"`
+	Value string           `json:"value,omitempty"`
+}
+
+// MeshHTTPRouteRuleConf defines the configuration of a rule.
+type MeshHTTPRouteRuleConf struct {
+	BackendRefs []BackendRef `json:"backendRefs,omitempty"`
+}
+
+// BackendRef defines a weighted destination of a rule.
+type BackendRef struct {
+	TargetRef `json:",inline"`
+
+	// Weight assigned to the destination, 0 means that the destination will be ignored.
+	Weight *uint `json:"weight,omitempty"`
+
+	// Port is only supported when this ref refers to a real MeshService object.
+	Port *uint32 `json:"port,omitempty"`
+}
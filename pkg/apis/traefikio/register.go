@@ -0,0 +1,5 @@
+package traefikio
+
+const (
+	GroupName = "traefik.io"
+)
@@ -0,0 +1,6 @@
+// +k8s:deepcopy-gen=package
+
+// Package v1alpha1 is the v1alpha1 version of the Traefik v3 API.
+// +groupName=traefik.io
+// +groupGoName=TraefikIO
+package v1alpha1
@@ -0,0 +1,90 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TraefikService is the specification for a service (that an IngressRoute refers
+// to) that is usually not a terminal service (i.e. not a pod of servers), as
+// opposed to a Kubernetes Service. That is to say, it usually refers to other
+// (children) services, which themselves can be TraefikServices or Services.
+type TraefikService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec ServiceSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TraefikServiceList is a list of TraefikService resources.
+type TraefikServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TraefikService `json:"items"`
+}
+
+// ServiceSpec defines whether a TraefikService is a load-balancer of services or a
+// mirroring service.
+type ServiceSpec struct {
+	Weighted  *WeightedRoundRobin `json:"weighted,omitempty"`
+	Mirroring *Mirroring          `json:"mirroring,omitempty"`
+}
+
+// WeightedRoundRobin defines a load-balancer of services.
+type WeightedRoundRobin struct {
+	Services []Service `json:"services,omitempty"`
+	// Sticky defines whether sticky sessions are enabled.
+	Sticky *Sticky `json:"sticky,omitempty"`
+}
+
+// Service defines an upstream to proxy traffic.
+type Service struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port,omitempty"`
+	Weight    *int   `json:"weight,omitempty"`
+}
+
+// Mirroring defines a mirroring service, which is composed of a main
+// load-balancer, and a list of mirrors.
+type Mirroring struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Port      int32    `json:"port,omitempty"`
+	Mirrors   []Mirror `json:"mirrors,omitempty"`
+}
+
+// Mirror holds the mirror configuration.
+type Mirror struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port,omitempty"`
+	// Percent defines the part of the traffic to mirror.
+	// Supported values: 0 to 100.
+	Percent int `json:"percent,omitempty"`
+}
+
+// Sticky holds the sticky configuration.
+type Sticky struct {
+	// Cookie defines the sticky cookie configuration.
+	Cookie *Cookie `json:"cookie,omitempty"`
+}
+
+// Cookie holds the sticky configuration based on cookie.
+type Cookie struct {
+	// Name defines the Cookie name.
+	Name string `json:"name,omitempty"`
+	// Secure defines whether the cookie can only be transmitted over an encrypted connection (i.e. HTTPS).
+	Secure bool `json:"secure,omitempty"`
+	// HTTPOnly defines whether the cookie can be accessed by client-side APIs, such as JavaScript.
+	HTTPOnly bool `json:"httpOnly,omitempty"`
+	// SameSite defines the same site policy.
+	SameSite string `json:"sameSite,omitempty"`
+	// MaxAge indicates the number of seconds until the cookie expires.
+	MaxAge int `json:"maxAge,omitempty"`
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// CompanionReconciler represents a reconciler that maintains primary copies
+// of the resources accompanying the target workload, e.g. PodDisruptionBudgets.
+type CompanionReconciler interface {
+	ReconcilePrimaryCompanion(cd *flaggerv1.Canary, init bool) error
+	FinalizePrimaryCompanion(cd *flaggerv1.Canary) error
+}
+
+// isOwnedByCanary returns true if the object is controlled by the given canary
+func isOwnedByCanary(meta metav1.Object, cd *flaggerv1.Canary) bool {
+	ref := metav1.GetControllerOf(meta)
+	return ref != nil && ref.Kind == flaggerv1.CanaryKind && ref.Name == cd.Name
+}
+
+// selectsLabelValue returns true if the selector requires the label to have the given value
+func selectsLabelValue(selector *metav1.LabelSelector, label, value string) bool {
+	if selector == nil {
+		return false
+	}
+	if selector.MatchLabels[label] == value {
+		return true
+	}
+	for _, req := range selector.MatchExpressions {
+		if req.Key == label && req.Operator == metav1.LabelSelectorOpIn &&
+			len(req.Values) == 1 && req.Values[0] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// makePrimarySelector returns a copy of the selector with the label value
+// replaced by the primary label value
+func makePrimarySelector(selector *metav1.LabelSelector, label, value string) *metav1.LabelSelector {
+	primarySelector := selector.DeepCopy()
+	primaryValue := value + "-primary"
+	if primarySelector.MatchLabels[label] == value {
+		primarySelector.MatchLabels[label] = primaryValue
+	}
+	for i, req := range primarySelector.MatchExpressions {
+		if req.Key == label && req.Operator == metav1.LabelSelectorOpIn {
+			for j, v := range req.Values {
+				if v == value {
+					primarySelector.MatchExpressions[i].Values[j] = primaryValue
+				}
+			}
+		}
+	}
+	return primarySelector
+}
@@ -26,8 +26,10 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -38,6 +40,8 @@ import (
 type ConfigTracker struct {
 	KubeClient    kubernetes.Interface
 	FlaggerClient clientset.Interface
+	DynamicClient dynamic.Interface
+	RESTMapper    meta.RESTMapper
 	Logger        *zap.SugaredLogger
 }
 
@@ -145,7 +149,21 @@ func (ct *ConfigTracker) GetTargetConfigs(cd *flaggerv1.Canary) (map[string]Conf
 		cs = targetDae.Spec.Template.Spec.Containers
 		cs = append(cs, targetDae.Spec.Template.Spec.InitContainers...)
 	default:
-		return nil, fmt.Errorf("TargetRef.Kind invalid: %s", cd.Spec.TargetRef.Kind)
+		resource, err := workloadResource(ct.DynamicClient, ct.RESTMapper, cd)
+		if err != nil {
+			return nil, err
+		}
+		target, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+		}
+		template, err := getWorkloadPodTemplate(target, cd.GetWorkload().TemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		vs = template.Spec.Volumes
+		cs = template.Spec.Containers
+		cs = append(cs, template.Spec.InitContainers...)
 	}
 
 	secretNames := make(map[string]bool)
@@ -58,6 +58,9 @@ func (c *DaemonSetController) ScaleToZero(cd *flaggerv1.Canary) error {
 	daeCopy.Spec.Template.Spec.NodeSelector = make(map[string]string,
 		len(dae.Spec.Template.Spec.NodeSelector)+len(daemonSetScaleDownNodeSelector))
 	for k, v := range dae.Spec.Template.Spec.NodeSelector {
+		if k == cd.GetNodeRolloutLabel() {
+			continue
+		}
 		daeCopy.Spec.Template.Spec.NodeSelector[k] = v
 	}
 
@@ -84,6 +87,14 @@ func (c *DaemonSetController) ScaleFromZero(cd *flaggerv1.Canary) error {
 		delete(depCopy.Spec.Template.Spec.NodeSelector, k)
 	}
 
+	// restrict the canary to the nodes selected for the rollout
+	if cd.HasNodeRollout() {
+		if depCopy.Spec.Template.Spec.NodeSelector == nil {
+			depCopy.Spec.Template.Spec.NodeSelector = make(map[string]string)
+		}
+		depCopy.Spec.Template.Spec.NodeSelector[cd.GetNodeRolloutLabel()] = "true"
+	}
+
 	_, err = c.kubeClient.AppsV1().DaemonSets(dep.Namespace).Update(context.TODO(), depCopy, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("scaling up daemonset %s.%s failed: %w", depCopy.GetName(), depCopy.Namespace, err)
@@ -154,9 +165,12 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 		// update spec with primary secrets and config maps
 		primaryCopy.Spec.Template.Spec = c.configTracker.ApplyPrimaryConfigs(canary.Spec.Template.Spec, configRefs)
 
-		// ignore `daemonSetScaleDownNodeSelector` node selector
-		for key := range daemonSetScaleDownNodeSelector {
-			delete(primaryCopy.Spec.Template.Spec.NodeSelector, key)
+		// ignore the node selectors set by Flagger
+		removeFlaggerNodeSelectors(cd, &primaryCopy.Spec.Template.Spec)
+
+		// exclude the primary from the nodes running the canary
+		if cd.HasNodeRollout() {
+			excludeNodes(&primaryCopy.Spec.Template.Spec, cd.GetNodeRolloutLabel())
 		}
 
 		// update pod annotations to ensure a rolling update
@@ -168,6 +182,12 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 		primaryCopy.Spec.Template.Annotations = annotations
 		primaryCopy.Spec.Template.Labels = makePrimaryLabels(canary.Spec.Template.Labels, primaryLabelValue, label)
 
+		// apply the primary pod template overrides
+		primaryCopy.Spec.Template, err = applyPrimaryOverrides(cd, primaryCopy.Spec.Template)
+		if err != nil {
+			return err
+		}
+
 		// update ds annotations
 		primaryCopy.ObjectMeta.Annotations = make(map[string]string)
 		filteredAnnotations := includeLabelsByPrefix(canary.ObjectMeta.Annotations, c.includeLabelPrefix)
@@ -201,10 +221,8 @@ func (c *DaemonSetController) HasTargetChanged(cd *flaggerv1.Canary) (bool, erro
 		return false, fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
 	}
 
-	// ignore `daemonSetScaleDownNodeSelector` node selector
-	for key := range daemonSetScaleDownNodeSelector {
-		delete(canary.Spec.Template.Spec.NodeSelector, key)
-	}
+	// ignore the node selectors set by Flagger
+	removeFlaggerNodeSelectors(cd, &canary.Spec.Template.Spec)
 
 	// since nil and capacity zero map would have different hash, we have to initialize here
 	if canary.Spec.Template.Spec.NodeSelector == nil {
@@ -274,6 +292,14 @@ func (c *DaemonSetController) createPrimaryDaemonSet(cd *flaggerv1.Canary, inclu
 			return fmt.Errorf("makeAnnotations failed: %w", err)
 		}
 
+		// update spec with the primary secrets and config maps
+		primarySpec := c.configTracker.ApplyPrimaryConfigs(canaryDae.Spec.Template.Spec, configRefs)
+
+		// exclude the primary from the nodes running the canary
+		if cd.HasNodeRollout() {
+			excludeNodes(&primarySpec, cd.GetNodeRolloutLabel())
+		}
+
 		// create primary daemonset
 		primaryDae = &appsv1.DaemonSet{
 			ObjectMeta: metav1.ObjectMeta{
@@ -303,18 +329,34 @@ func (c *DaemonSetController) createPrimaryDaemonSet(cd *flaggerv1.Canary, inclu
 						Labels:      makePrimaryLabels(canaryDae.Spec.Template.Labels, primaryLabelValue, label),
 						Annotations: annotations,
 					},
-					// update spec with the primary secrets and config maps
-					Spec: c.configTracker.ApplyPrimaryConfigs(canaryDae.Spec.Template.Spec, configRefs),
+					Spec: primarySpec,
 				},
 			},
 		}
 
+		// apply the primary pod template overrides
+		primaryDae.Spec.Template, err = applyPrimaryOverrides(cd, primaryDae.Spec.Template)
+		if err != nil {
+			return fmt.Errorf("creating daemonset %s.%s failed: %w", primaryDae.Name, cd.Namespace, err)
+		}
+
 		_, err = c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Create(context.TODO(), primaryDae, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("creating daemonset %s.%s failed: %w", primaryDae.Name, cd.Namespace, err)
 		}
 
 		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Infof("DaemonSet %s.%s created", primaryDae.GetName(), cd.Namespace)
+		return nil
+	}
+
+	// exclude the existing primary from the nodes running the canary when the node rollout is enabled
+	if err == nil && cd.HasNodeRollout() && !hasNodeExclusion(primaryDae.Spec.Template.Spec, cd.GetNodeRolloutLabel()) {
+		primaryCopy := primaryDae.DeepCopy()
+		excludeNodes(&primaryCopy.Spec.Template.Spec, cd.GetNodeRolloutLabel())
+		_, err = c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("updating daemonset %s.%s node affinity failed: %w", primaryName, cd.Namespace, err)
+		}
 	}
 	return nil
 }
@@ -344,3 +386,68 @@ func (c *DaemonSetController) Finalize(cd *flaggerv1.Canary) error {
 	}
 	return nil
 }
+
+// removeFlaggerNodeSelectors removes the scale down and the node rollout selectors from the pod spec
+func removeFlaggerNodeSelectors(cd *flaggerv1.Canary, spec *corev1.PodSpec) {
+	for key := range daemonSetScaleDownNodeSelector {
+		delete(spec.NodeSelector, key)
+	}
+	delete(spec.NodeSelector, cd.GetNodeRolloutLabel())
+}
+
+// excludeNodes adds a node affinity requirement to each node selector term
+// so that the pods are not scheduled on the nodes having the label
+func excludeNodes(spec *corev1.PodSpec, label string) {
+	if hasNodeExclusion(*spec, label) {
+		return
+	}
+
+	spec.Affinity = spec.Affinity.DeepCopy()
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	selector := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions,
+			corev1.NodeSelectorRequirement{
+				Key:      label,
+				Operator: corev1.NodeSelectorOpDoesNotExist,
+			})
+	}
+}
+
+// hasNodeExclusion returns true if every node selector term excludes the nodes having the label
+func hasNodeExclusion(spec corev1.PodSpec, label string) bool {
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil ||
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return false
+	}
+	for _, term := range terms {
+		found := false
+		for _, req := range term.MatchExpressions {
+			if req.Key == label && req.Operator == corev1.NodeSelectorOpDoesNotExist {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
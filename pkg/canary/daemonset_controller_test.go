@@ -268,3 +268,61 @@ func TestDaemonSetController_Finalize(t *testing.T) {
 	_, ok := dep.Spec.Template.Spec.NodeSelector["flagger.app/scale-to-zero"]
 	assert.False(t, ok)
 }
+
+func TestDaemonSetController_NodeRollout(t *testing.T) {
+	dc := daemonsetConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDaemonSetFixture(dc)
+	mocks.canary.Spec.Analysis.NodeRollout = &flaggerv1.NodeRollout{}
+	label := mocks.canary.GetNodeRolloutLabel()
+
+	err := mocks.controller.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	// the primary pods are excluded from the nodes running the canary
+	primary, err := mocks.kubeClient.AppsV1().DaemonSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, hasNodeExclusion(primary.Spec.Template.Spec, label))
+
+	// the canary pods are restricted to the nodes selected for the rollout
+	err = mocks.controller.ScaleFromZero(mocks.canary)
+	require.NoError(t, err)
+
+	canary, err := mocks.kubeClient.AppsV1().DaemonSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "true", canary.Spec.Template.Spec.NodeSelector[label])
+
+	// the node rollout selector is ignored when hashing the canary spec
+	err = mocks.controller.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing})
+	require.NoError(t, err)
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	cd.Spec.Analysis.NodeRollout = mocks.canary.Spec.Analysis.NodeRollout
+
+	canary.Spec.Template.Spec.NodeSelector = nil
+	_, err = mocks.kubeClient.AppsV1().DaemonSets("default").Update(context.TODO(), canary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	err = mocks.controller.ScaleFromZero(mocks.canary)
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err = mocks.kubeClient.AppsV1().DaemonSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, hasNodeExclusion(primary.Spec.Template.Spec, label))
+	_, ok := primary.Spec.Template.Spec.NodeSelector[label]
+	assert.False(t, ok)
+
+	err = mocks.controller.ScaleToZero(mocks.canary)
+	require.NoError(t, err)
+
+	canary, err = mocks.kubeClient.AppsV1().DaemonSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, ok = canary.Spec.Template.Spec.NodeSelector[label]
+	assert.False(t, ok)
+}
@@ -32,10 +32,8 @@ func (c *DaemonSetController) SyncStatus(cd *flaggerv1.Canary, status flaggerv1.
 		return fmt.Errorf("daemonset %s.%s get query error: %w", cd.Spec.TargetRef.Name, cd.Namespace, err)
 	}
 
-	// ignore `daemonSetScaleDownNodeSelector` node selector
-	for key := range daemonSetScaleDownNodeSelector {
-		delete(dae.Spec.Template.Spec.NodeSelector, key)
-	}
+	// ignore the node selectors set by Flagger
+	removeFlaggerNodeSelectors(cd, &dae.Spec.Template.Spec)
 
 	// since nil and capacity zero map would have different hash, we have to initialize here
 	if dae.Spec.Template.Spec.NodeSelector == nil {
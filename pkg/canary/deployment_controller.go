@@ -105,9 +105,17 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 		primaryCopy.Spec.MinReadySeconds = canary.Spec.MinReadySeconds
 		primaryCopy.Spec.RevisionHistoryLimit = canary.Spec.RevisionHistoryLimit
 		primaryCopy.Spec.Strategy = canary.Spec.Strategy
-		// update replica if hpa isn't set
+		// update replica if hpa isn't set, while the canary is sized to the traffic weight
+		// the primary gets the replicas declared in the canary deployment
 		if cd.Spec.AutoscalerRef == nil {
-			primaryCopy.Spec.Replicas = canary.Spec.Replicas
+			replicas, saved, err := loadReplicas(canary.Annotations)
+			if err != nil {
+				return err
+			}
+			if !saved {
+				replicas = canary.Spec.Replicas
+			}
+			primaryCopy.Spec.Replicas = replicas
 		}
 
 		// update spec with primary secrets and config maps
@@ -122,9 +130,16 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 		primaryCopy.Spec.Template.Annotations = podAnnotations
 		primaryCopy.Spec.Template.Labels = makePrimaryLabels(canary.Spec.Template.Labels, primaryLabelValue, label)
 
+		// apply the primary pod template overrides
+		primaryCopy.Spec.Template, err = applyPrimaryOverrides(cd, primaryCopy.Spec.Template)
+		if err != nil {
+			return err
+		}
+
 		// update deploy annotations
 		primaryCopy.ObjectMeta.Annotations = make(map[string]string)
 		filteredAnnotations := includeLabelsByPrefix(canary.ObjectMeta.Annotations, c.includeLabelPrefix)
+		delete(filteredAnnotations, flaggerv1.ReplicasAnnotation)
 		for k, v := range filteredAnnotations {
 			primaryCopy.ObjectMeta.Annotations[k] = v
 		}
@@ -232,6 +247,77 @@ func (c *DeploymentController) ScaleFromZero(cd *flaggerv1.Canary) error {
 	return nil
 }
 
+// ScaleToWeight sets the canary deployment replicas proportionally to the traffic weight,
+// the primary replicas are the capacity needed to serve the total weight
+func (c *DeploymentController) ScaleToWeight(cd *flaggerv1.Canary, weight int, totalWeight int) (int32, error) {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	primary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("deployment %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	capacity := int32(1)
+	if primary.Spec.Replicas != nil && *primary.Spec.Replicas > 0 {
+		capacity = *primary.Spec.Replicas
+	}
+	replicas := canaryReplicas(cd.Spec.ReplicaSizing, capacity, weight, totalWeight)
+
+	dep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+	_, saved := dep.Annotations[flaggerv1.ReplicasAnnotation]
+	if dep.Spec.Replicas != nil && *dep.Spec.Replicas == replicas && (saved || cd.Spec.AutoscalerRef != nil) {
+		return replicas, nil
+	}
+
+	depCopy := dep.DeepCopy()
+	// the declared replicas are restored before promotion, the autoscaler replicas are restored with its bounds,
+	// a canary scaled to zero gets the primary replicas as ScaleFromZero does
+	if cd.Spec.AutoscalerRef == nil {
+		declared := dep.Spec.Replicas
+		if declared == nil || *declared == 0 {
+			declared = int32p(capacity)
+		}
+		depCopy.Annotations = saveReplicas(depCopy.Annotations, declared)
+	}
+	depCopy.Spec.Replicas = int32p(replicas)
+
+	_, err = c.kubeClient.AppsV1().Deployments(dep.Namespace).Update(context.TODO(), depCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("scaling %s.%s to %v failed: %w", depCopy.GetName(), depCopy.Namespace, replicas, err)
+	}
+	c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+		Infof("Deployment %s.%s scaled to %v replicas for weight %v", targetName, cd.Namespace, replicas, weight)
+	return replicas, nil
+}
+
+// RestoreReplicas sets the canary deployment replicas to the value saved by ScaleToWeight
+func (c *DeploymentController) RestoreReplicas(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+		}
+		replicas, ok, err := loadReplicas(dep.Annotations)
+		if !ok || err != nil {
+			return err
+		}
+
+		depCopy := dep.DeepCopy()
+		delete(depCopy.Annotations, flaggerv1.ReplicasAnnotation)
+		depCopy.Spec.Replicas = replicas
+		_, err = c.kubeClient.AppsV1().Deployments(dep.Namespace).Update(context.TODO(), depCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("restoring deployment %s.%s replicas failed: %w", targetName, cd.Namespace, err)
+	}
+	return nil
+}
+
 // GetMetadata returns the pod label selector and svc ports
 func (c *DeploymentController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
 	targetName := cd.Spec.TargetRef.Name
@@ -328,6 +414,12 @@ func (c *DeploymentController) createPrimaryDeployment(cd *flaggerv1.Canary, inc
 			},
 		}
 
+		// apply the primary pod template overrides
+		primaryDep.Spec.Template, err = applyPrimaryOverrides(cd, primaryDep.Spec.Template)
+		if err != nil {
+			return fmt.Errorf("creating deployment %s.%s failed: %w", primaryDep.Name, cd.Namespace, err)
+		}
+
 		_, err = c.kubeClient.AppsV1().Deployments(cd.Namespace).Create(context.TODO(), primaryDep, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("creating deployment %s.%s failed: %w", primaryDep.Name, cd.Namespace, err)
@@ -124,6 +124,47 @@ func TestDeploymentController_ScaleToZero(t *testing.T) {
 	assert.Equal(t, int32(0), *c.Spec.Replicas)
 }
 
+func TestDeploymentController_ScaleToWeight(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Spec.Replicas = int32p(20)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.canary.Spec.ReplicaSizing = &flaggerv1.CanaryReplicaSizing{MinReplicas: int32p(2), Headroom: 20}
+
+	tests := []struct {
+		weight   int
+		replicas int32
+	}{
+		{weight: 0, replicas: 2},
+		{weight: 5, replicas: 2},
+		{weight: 50, replicas: 12},
+		{weight: 100, replicas: 20},
+	}
+	for _, tt := range tests {
+		replicas, err := mocks.controller.ScaleToWeight(mocks.canary, tt.weight, 100)
+		require.NoError(t, err)
+		assert.Equal(t, tt.replicas, replicas, "weight %d", tt.weight)
+
+		c, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.replicas, *c.Spec.Replicas, "weight %d", tt.weight)
+	}
+
+	// the canary was scaled to zero, the primary replicas are restored
+	err = mocks.controller.RestoreReplicas(mocks.canary)
+	require.NoError(t, err)
+	c, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), *c.Spec.Replicas)
+	assert.NotContains(t, c.Annotations, flaggerv1.ReplicasAnnotation)
+}
+
 func TestDeploymentController_NoConfigTracking(t *testing.T) {
 	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
 	mocks := newDeploymentFixture(dc)
@@ -284,3 +325,55 @@ func TestDeploymentController_AntiAffinityAndTopologySpreadConstraints(t *testin
 		assert.False(t, strings.HasSuffix(value, "-primary"))
 	})
 }
+
+func TestDeploymentController_PrimaryOverrides(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.canary.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+		Patch: `
+spec:
+  priorityClassName: high
+  containers:
+    - name: podinfo
+      env:
+        - name: PRIMARY
+          value: "true"
+`,
+	}
+	mocks.initializeCanary(t)
+
+	depPrimary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "high", depPrimary.Spec.Template.Spec.PriorityClassName)
+	assert.Contains(t, depPrimary.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "PRIMARY", Value: "true"})
+	assert.Equal(t, "podinfo-primary", depPrimary.Spec.Template.Labels[dc.label])
+
+	// the overrides are not applied to the canary
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, dep.Spec.Template.Spec.PriorityClassName)
+
+	// changing the overrides is not a new revision
+	mocks.canary.Status.LastAppliedSpec = computeHash(dep.Spec.Template)
+	mocks.canary.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+		Type:  flaggerv1.PrimaryOverridesJSONPatch,
+		Patch: `[{"op": "add", "path": "/spec/priorityClassName", "value": "critical"}]`,
+	}
+	isNew, err := mocks.controller.HasTargetChanged(mocks.canary)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	// the overrides are applied on promotion
+	dep2 := newDeploymentControllerTestV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	depPrimary, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "critical", depPrimary.Spec.Template.Spec.PriorityClassName)
+	assert.Equal(t, dep2.Spec.Template.Spec.Containers[0].Image, depPrimary.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, depPrimary.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "PRIMARY", Value: "true"})
+}
@@ -72,13 +72,19 @@ func (c *DeploymentController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error)
 // isDeploymentReady determines if a deployment is ready by checking the status conditions
 // if a deployment has exceeded the progress deadline it returns a non retriable error
 func (c *DeploymentController) isDeploymentReady(deployment *appsv1.Deployment, deadline int, readyThreshold int) (bool, error) {
+	return isDeploymentStatusReady(deployment, deadline, readyThreshold)
+}
+
+// isDeploymentStatusReady checks the deployment replicas and status conditions,
+// it is shared with the workload kinds that report their status in the Deployment format
+func isDeploymentStatusReady(deployment *appsv1.Deployment, deadline int, readyThreshold int) (bool, error) {
 	retriable := true
 	if deployment.Generation <= deployment.Status.ObservedGeneration {
-		progress := c.getDeploymentCondition(deployment.Status, appsv1.DeploymentProgressing)
+		progress := getDeploymentCondition(deployment.Status, appsv1.DeploymentProgressing)
 		if progress != nil {
 			// Determine if the deployment is stuck by checking if there is a minimum replicas unavailable condition
 			// and if the last update time exceeds the deadline
-			available := c.getDeploymentCondition(deployment.Status, appsv1.DeploymentAvailable)
+			available := getDeploymentCondition(deployment.Status, appsv1.DeploymentAvailable)
 			if available != nil && available.Status == "False" && available.Reason == "MinimumReplicasUnavailable" {
 				from := available.LastUpdateTime
 				delta := time.Duration(deadline) * time.Second
@@ -108,7 +114,7 @@ func (c *DeploymentController) isDeploymentReady(deployment *appsv1.Deployment,
 	return true, nil
 }
 
-func (c *DeploymentController) getDeploymentCondition(
+func getDeploymentCondition(
 	status appsv1.DeploymentStatus,
 	conditionType appsv1.DeploymentConditionType,
 ) *appsv1.DeploymentCondition {
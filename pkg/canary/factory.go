@@ -18,14 +18,23 @@ package canary
 
 import (
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
 
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
 )
 
 type Factory struct {
 	kubeClient         kubernetes.Interface
 	flaggerClient      clientset.Interface
+	dynamicClient      dynamic.Interface
+	restMapper         meta.RESTMapper
+	pdbLister          policylisters.PodDisruptionBudgetLister
+	vpaLister          cache.GenericLister
 	logger             *zap.SugaredLogger
 	configTracker      Tracker
 	labels             []string
@@ -34,6 +43,10 @@ type Factory struct {
 
 func NewFactory(kubeClient kubernetes.Interface,
 	flaggerClient clientset.Interface,
+	dynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
+	pdbLister policylisters.PodDisruptionBudgetLister,
+	vpaLister cache.GenericLister,
 	configTracker Tracker,
 	labels []string,
 	includeLabelPrefix []string,
@@ -41,6 +54,10 @@ func NewFactory(kubeClient kubernetes.Interface,
 	return &Factory{
 		kubeClient:         kubeClient,
 		flaggerClient:      flaggerClient,
+		dynamicClient:      dynamicClient,
+		restMapper:         restMapper,
+		pdbLister:          pdbLister,
+		vpaLister:          vpaLister,
 		logger:             logger,
 		configTracker:      configTracker,
 		labels:             labels,
@@ -48,7 +65,7 @@ func NewFactory(kubeClient kubernetes.Interface,
 	}
 }
 
-func (factory *Factory) Controller(kind string) Controller {
+func (factory *Factory) Controller(targetRef flaggerv1.LocalObjectReference) Controller {
 	deploymentCtrl := &DeploymentController{
 		logger:             factory.logger,
 		kubeClient:         factory.kubeClient,
@@ -65,6 +82,20 @@ func (factory *Factory) Controller(kind string) Controller {
 		configTracker:      factory.configTracker,
 		includeLabelPrefix: factory.includeLabelPrefix,
 	}
+	genericCtrl := &GenericController{
+		logger:             factory.logger,
+		kubeClient:         factory.kubeClient,
+		flaggerClient:      factory.flaggerClient,
+		dynamicClient:      factory.dynamicClient,
+		restMapper:         factory.restMapper,
+		labels:             factory.labels,
+		configTracker:      factory.configTracker,
+		includeLabelPrefix: factory.includeLabelPrefix,
+	}
+	knativeCtrl := &KnativeController{
+		logger:        factory.logger,
+		flaggerClient: factory.flaggerClient,
+	}
 	serviceCtrl := &ServiceController{
 		logger:             factory.logger,
 		kubeClient:         factory.kubeClient,
@@ -72,15 +103,17 @@ func (factory *Factory) Controller(kind string) Controller {
 		includeLabelPrefix: factory.includeLabelPrefix,
 	}
 
-	switch kind {
-	case "DaemonSet":
+	switch {
+	case targetRef.Kind == "DaemonSet":
 		return daemonSetCtrl
-	case "Deployment":
+	case targetRef.Kind == "Deployment":
 		return deploymentCtrl
-	case "Service":
+	case targetRef.IsKnativeService():
+		return knativeCtrl
+	case targetRef.Kind == "Service":
 		return serviceCtrl
 	default:
-		return deploymentCtrl
+		return genericCtrl
 	}
 }
 
@@ -108,3 +141,30 @@ func (factory *Factory) ScalerReconciler(kind string) ScalerReconciler {
 		return nil
 	}
 }
+
+// CompanionReconcilers returns the reconcilers of the resources accompanying the target workload,
+// the label and value are used to select the target pods.
+func (factory *Factory) CompanionReconcilers(targetRef flaggerv1.LocalObjectReference, label string, labelValue string) []CompanionReconciler {
+	// services and Knative services have no primary workload
+	if targetRef.Kind == "Service" || targetRef.IsKnativeService() {
+		return nil
+	}
+
+	pdbReconciler := &PDBReconciler{
+		logger:             factory.logger,
+		kubeClient:         factory.kubeClient,
+		pdbLister:          factory.pdbLister,
+		includeLabelPrefix: factory.includeLabelPrefix,
+		label:              label,
+		labelValue:         labelValue,
+	}
+
+	vpaReconciler := &VPAReconciler{
+		logger:             factory.logger,
+		dynamicClient:      factory.dynamicClient,
+		vpaLister:          factory.vpaLister,
+		includeLabelPrefix: factory.includeLabelPrefix,
+	}
+
+	return []CompanionReconciler{pdbReconciler, vpaReconciler}
+}
@@ -0,0 +1,438 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+// GenericController is managing the operations for the workload kinds
+// that have a pod template and a scale subresource, e.g. OpenKruise CloneSets
+type GenericController struct {
+	kubeClient         kubernetes.Interface
+	flaggerClient      clientset.Interface
+	dynamicClient      dynamic.Interface
+	restMapper         meta.RESTMapper
+	logger             *zap.SugaredLogger
+	configTracker      Tracker
+	labels             []string
+	includeLabelPrefix []string
+}
+
+// Initialize creates the primary workload, scales to zero the canary workload
+func (c *GenericController) Initialize(cd *flaggerv1.Canary) (err error) {
+	if err := c.createPrimaryWorkload(cd, c.includeLabelPrefix); err != nil {
+		return fmt.Errorf("createPrimaryWorkload failed: %w", err)
+	}
+
+	if cd.Status.Phase == "" || cd.Status.Phase == flaggerv1.CanaryPhaseInitializing {
+		if !cd.SkipAnalysis() {
+			if err := c.IsPrimaryReady(cd); err != nil {
+				return fmt.Errorf("%w", err)
+			}
+		}
+
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+			Infof("Scaling down %s %s.%s", cd.Spec.TargetRef.Kind, cd.Spec.TargetRef.Name, cd.Namespace)
+		if err := c.ScaleToZero(cd); err != nil {
+			return fmt.Errorf("scaling down canary %s %s.%s failed: %w", cd.Spec.TargetRef.Kind, cd.Spec.TargetRef.Name, cd.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+// Promote copies the spec, secrets and config maps from canary to primary
+func (c *GenericController) Promote(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	workload := cd.GetWorkload()
+
+	resource, err := workloadResource(c.dynamicClient, c.restMapper, cd)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		canary, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+		}
+
+		label, labelValue, err := c.getSelectorLabel(cd, canary)
+		primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+		if err != nil {
+			return fmt.Errorf("getSelectorLabel failed: %w", err)
+		}
+
+		primary, err := resource.Get(context.TODO(), primaryName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+
+		// promote secrets and config maps
+		configRefs, err := c.configTracker.GetTargetConfigs(cd)
+		if err != nil {
+			return fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+		}
+
+		primarySelector, err := getWorkloadSelector(primary, workload.SelectorPath)
+		if err != nil {
+			return err
+		}
+		primaryReplicas, _ := getWorkloadReplicas(primary, workload.ReplicasPath)
+		template, err := c.makePrimaryTemplate(cd, canary, configRefs, label, primaryLabelValue)
+		if err != nil {
+			return err
+		}
+
+		// copy the canary spec and keep the primary selector
+		primaryCopy := primary.DeepCopy()
+		spec, _, _ := unstructured.NestedFieldCopy(canary.Object, "spec")
+		if err := unstructured.SetNestedField(primaryCopy.Object, spec, "spec"); err != nil {
+			return fmt.Errorf("%s %s.%s spec copy error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+		if err := setWorkloadField(primaryCopy, workload.SelectorPath, primarySelector); err != nil {
+			return err
+		}
+		if err := setWorkloadField(primaryCopy, workload.TemplatePath, template); err != nil {
+			return err
+		}
+		// keep the primary replicas if an autoscaler is set
+		if cd.Spec.AutoscalerRef != nil {
+			if err := unstructured.SetNestedField(primaryCopy.Object, int64(primaryReplicas), fieldPath(workload.ReplicasPath)...); err != nil {
+				return fmt.Errorf("%s %s.%s replicas error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+			}
+		}
+
+		// update workload annotations and labels
+		primaryCopy.SetAnnotations(includeLabelsByPrefix(canary.GetAnnotations(), c.includeLabelPrefix))
+		primaryCopy.SetLabels(includeLabelsByPrefix(canary.GetLabels(), c.includeLabelPrefix))
+
+		// apply update
+		_, err = resource.Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating %s %s.%s template spec failed: %w",
+			cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+	}
+
+	return nil
+}
+
+// HasTargetChanged returns true if the canary workload pod spec has changed
+func (c *GenericController) HasTargetChanged(cd *flaggerv1.Canary) (bool, error) {
+	canary, err := c.getWorkload(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return false, err
+	}
+
+	template, err := getWorkloadPodTemplate(canary, cd.GetWorkload().TemplatePath)
+	if err != nil {
+		return false, err
+	}
+
+	return hasSpecChanged(cd, *template)
+}
+
+// ScaleToZero sets the canary workload replicas to zero
+func (c *GenericController) ScaleToZero(cd *flaggerv1.Canary) error {
+	return c.scale(cd, 0)
+}
+
+// ScaleFromZero sets the canary workload replicas to the desired replicas,
+// to the primary replicas if the canary was scaled to zero or to one
+func (c *GenericController) ScaleFromZero(cd *flaggerv1.Canary) error {
+	canary, err := c.getWorkload(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return err
+	}
+
+	replicas := int32(1)
+	workload := cd.GetWorkload()
+	if r, ok := getWorkloadReplicas(canary, workload.ReplicasPath); ok && r > 0 {
+		replicas = r
+	} else {
+		primary, err := c.getWorkload(cd, fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name))
+		if err != nil {
+			return err
+		}
+		if r, ok := getWorkloadReplicas(primary, workload.ReplicasPath); ok && r > 0 {
+			replicas = r
+		}
+	}
+
+	return c.scale(cd, replicas)
+}
+
+// GetMetadata returns the pod label selector and svc ports
+func (c *GenericController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
+	canary, err := c.getWorkload(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	label, labelValue, err := c.getSelectorLabel(cd, canary)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	var ports map[string]int32
+	if cd.Spec.Service.PortDiscovery {
+		template, err := getWorkloadPodTemplate(canary, cd.GetWorkload().TemplatePath)
+		if err != nil {
+			return "", "", nil, err
+		}
+		ports = getPorts(cd, template.Spec.Containers)
+	}
+
+	return label, labelValue, ports, nil
+}
+
+func (c *GenericController) HaveDependenciesChanged(cd *flaggerv1.Canary) (bool, error) {
+	return c.configTracker.HasConfigChanged(cd)
+}
+
+// Finalize sets the canary workload replicas to the primary replicas,
+// or scales the canary from zero if the primary doesn't exist
+func (c *GenericController) Finalize(cd *flaggerv1.Canary) error {
+	workload := cd.GetWorkload()
+	canary, err := c.getWorkload(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return err
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primary, err := c.getWorkload(cd, primaryName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := c.ScaleFromZero(cd); err != nil {
+				return fmt.Errorf("ScaleFromZero failed: %w", err)
+			}
+			return nil
+		}
+		return err
+	}
+
+	canaryReplicas, _ := getWorkloadReplicas(canary, workload.ReplicasPath)
+	primaryReplicas, _ := getWorkloadReplicas(primary, workload.ReplicasPath)
+	if canaryReplicas != primaryReplicas {
+		if err := c.scale(cd, primaryReplicas); err != nil {
+			return fmt.Errorf("scale failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *GenericController) createPrimaryWorkload(cd *flaggerv1.Canary, includeLabelPrefix []string) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	workload := cd.GetWorkload()
+
+	resource, err := workloadResource(c.dynamicClient, c.restMapper, cd)
+	if err != nil {
+		return err
+	}
+
+	canary, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+	}
+
+	// Create the labels map but filter unwanted labels
+	labels := includeLabelsByPrefix(canary.GetLabels(), includeLabelPrefix)
+
+	label, labelValue, err := c.getSelectorLabel(cd, canary)
+	primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+	if err != nil {
+		return fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	_, err = resource.Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// create primary secrets and config maps
+		configRefs, err := c.configTracker.GetTargetConfigs(cd)
+		if err != nil {
+			return fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+		}
+
+		template, err := c.makePrimaryTemplate(cd, canary, configRefs, label, primaryLabelValue)
+		if err != nil {
+			return err
+		}
+
+		replicas := int32(1)
+		if r, ok := getWorkloadReplicas(canary, workload.ReplicasPath); ok && r > 0 {
+			replicas = r
+		}
+
+		// create primary workload from the canary spec
+		primary := &unstructured.Unstructured{}
+		primary.SetAPIVersion(canary.GetAPIVersion())
+		primary.SetKind(canary.GetKind())
+		primary.SetName(primaryName)
+		primary.SetNamespace(cd.Namespace)
+		primary.SetLabels(makePrimaryLabels(labels, primaryLabelValue, label))
+		primary.SetAnnotations(filterMetadata(canary.GetAnnotations()))
+		primary.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+				Group:   flaggerv1.SchemeGroupVersion.Group,
+				Version: flaggerv1.SchemeGroupVersion.Version,
+				Kind:    flaggerv1.CanaryKind,
+			}),
+		})
+
+		spec, _, _ := unstructured.NestedFieldCopy(canary.Object, "spec")
+		if err := unstructured.SetNestedField(primary.Object, spec, "spec"); err != nil {
+			return fmt.Errorf("%s %s.%s spec copy error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+		if err := setWorkloadField(primary, workload.SelectorPath, &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				label: primaryLabelValue,
+			},
+		}); err != nil {
+			return err
+		}
+		if err := setWorkloadField(primary, workload.TemplatePath, template); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(primary.Object, int64(replicas), fieldPath(workload.ReplicasPath)...); err != nil {
+			return fmt.Errorf("%s %s.%s replicas error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+
+		_, err = resource.Create(context.TODO(), primary, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating %s %s.%s failed: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+			Infof("%s %s.%s created", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace)
+	}
+
+	return nil
+}
+
+// makePrimaryTemplate returns the canary pod template with the primary labels, secrets, config maps and overrides
+func (c *GenericController) makePrimaryTemplate(cd *flaggerv1.Canary, canary *unstructured.Unstructured,
+	configRefs map[string]ConfigRef, label string, primaryLabelValue string) (*corev1.PodTemplateSpec, error) {
+	template, err := getWorkloadPodTemplate(canary, cd.GetWorkload().TemplatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// update pod annotations to ensure a rolling update
+	annotations, err := makeAnnotations(template.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("makeAnnotations failed: %w", err)
+	}
+
+	primaryTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      makePrimaryLabels(template.Labels, primaryLabelValue, label),
+			Annotations: annotations,
+		},
+		// update spec with the primary secrets and config maps
+		Spec: c.configTracker.ApplyPrimaryConfigs(template.Spec, configRefs),
+	}
+
+	// apply the primary pod template overrides
+	primaryTemplate, err = applyPrimaryOverrides(cd, primaryTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &primaryTemplate, nil
+}
+
+// scale sets the canary workload replicas through the scale subresource
+func (c *GenericController) scale(cd *flaggerv1.Canary, replicas int32) error {
+	targetName := cd.Spec.TargetRef.Name
+	resource, err := workloadResource(c.dynamicClient, c.restMapper, cd)
+	if err != nil {
+		return err
+	}
+
+	scale, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return fmt.Errorf("%s %s.%s scale query error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+	}
+
+	if err := unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return fmt.Errorf("%s %s.%s scale error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+	}
+
+	_, err = resource.Update(context.TODO(), scale, metav1.UpdateOptions{}, "scale")
+	if err != nil {
+		return fmt.Errorf("scaling %s %s.%s to %v failed: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, replicas, err)
+	}
+	return nil
+}
+
+// getWorkload returns the workload with the given name
+func (c *GenericController) getWorkload(cd *flaggerv1.Canary, name string) (*unstructured.Unstructured, error) {
+	resource, err := workloadResource(c.dynamicClient, c.restMapper, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := resource.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, name, cd.Namespace, err)
+	}
+	return obj, nil
+}
+
+// getSelectorLabel returns the selector match label
+func (c *GenericController) getSelectorLabel(cd *flaggerv1.Canary, obj *unstructured.Unstructured) (string, string, error) {
+	selector, err := getWorkloadSelector(obj, cd.GetWorkload().SelectorPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, l := range c.labels {
+		if _, ok := selector.MatchLabels[l]; ok {
+			return l, selector.MatchLabels[l], nil
+		}
+	}
+
+	return "", "", fmt.Errorf(
+		"%s %s.%s selector matchLabels must contain one of %v",
+		obj.GetKind(), obj.GetName(), obj.GetNamespace(), c.labels,
+	)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestGenericController_Initialize(t *testing.T) {
+	mocks := newGenericFixture()
+	err := mocks.controller.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	selector, err := getWorkloadSelector(primary, ".spec.selector")
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-primary", selector.MatchLabels["app"])
+
+	template, err := getWorkloadPodTemplate(primary, ".spec.template")
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-primary", template.Labels["app"])
+	assert.Equal(t, "podinfo-config-env-primary", template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+
+	replicas, _ := getWorkloadReplicas(primary, ".spec.replicas")
+	assert.Equal(t, int32(2), replicas)
+	strategy, _, _ := unstructured.NestedString(primary.Object, "spec", "updateStrategy", "type")
+	assert.Equal(t, "InPlaceIfPossible", strategy)
+
+	_, err = mocks.kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), "podinfo-config-env-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the canary is scaled down through the scale subresource
+	canary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	replicas, _ = getWorkloadReplicas(canary, ".spec.replicas")
+	assert.Equal(t, int32(0), replicas)
+
+	// the canary is scaled up to the primary replicas
+	err = mocks.controller.ScaleFromZero(mocks.canary)
+	require.NoError(t, err)
+	canary, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	replicas, _ = getWorkloadReplicas(canary, ".spec.replicas")
+	assert.Equal(t, int32(2), replicas)
+
+	label, labelValue, ports, err := mocks.controller.GetMetadata(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, "app", label)
+	assert.Equal(t, "podinfo", labelValue)
+	assert.Empty(t, ports)
+}
+
+func TestGenericController_Promote(t *testing.T) {
+	mocks := newGenericFixture()
+	err := mocks.controller.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	err = mocks.controller.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseInitialized})
+	require.NoError(t, err)
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	canary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	containers, _, _ := unstructured.NestedSlice(canary.Object, "spec", "template", "spec", "containers")
+	containers[0].(map[string]interface{})["image"] = "quay.io/stefanprodan/podinfo:1.2.1"
+	require.NoError(t, unstructured.SetNestedSlice(canary.Object, containers, "spec", "template", "spec", "containers"))
+	_, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Update(context.TODO(), canary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err = mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+
+	err = mocks.controller.Promote(cd)
+	require.NoError(t, err)
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	template, err := getWorkloadPodTemplate(primary, ".spec.template")
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", template.Spec.Containers[0].Image)
+	assert.Equal(t, "podinfo-primary", template.Labels["app"])
+
+	selector, err := getWorkloadSelector(primary, ".spec.selector")
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-primary", selector.MatchLabels["app"])
+}
+
+func TestGenericController_PrimaryOverrides(t *testing.T) {
+	mocks := newGenericFixture()
+	mocks.canary.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+		Patch: `
+spec:
+  priorityClassName: high
+`,
+	}
+	err := mocks.controller.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	template, err := getWorkloadPodTemplate(primary, ".spec.template")
+	require.NoError(t, err)
+	assert.Equal(t, "high", template.Spec.PriorityClassName)
+	assert.Equal(t, "podinfo-primary", template.Labels["app"])
+
+	// the overrides are not applied to the canary
+	canary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	template, err = getWorkloadPodTemplate(canary, ".spec.template")
+	require.NoError(t, err)
+	assert.Empty(t, template.Spec.PriorityClassName)
+
+	// the overrides are applied on promotion
+	mocks.canary.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+		Type:  flaggerv1.PrimaryOverridesJSONPatch,
+		Patch: `[{"op": "add", "path": "/spec/priorityClassName", "value": "critical"}]`,
+	}
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	template, err = getWorkloadPodTemplate(primary, ".spec.template")
+	require.NoError(t, err)
+	assert.Equal(t, "critical", template.Spec.PriorityClassName)
+	assert.Equal(t, "podinfo-primary", template.Labels["app"])
+}
+
+func TestGenericController_IsReady(t *testing.T) {
+	mocks := newGenericFixture()
+	mocks.canary.Spec.Analysis = &flaggerv1.CanaryAnalysis{}
+	err := mocks.controller.Initialize(mocks.canary)
+	require.Error(t, err)
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	status := map[string]interface{}{
+		"replicas":          int64(2),
+		"updatedReplicas":   int64(2),
+		"availableReplicas": int64(2),
+	}
+	require.NoError(t, unstructured.SetNestedMap(primary.Object, status, "status"))
+	primary, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the ready condition is missing
+	err = mocks.controller.IsPrimaryReady(mocks.canary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "condition Ready")
+
+	status["conditions"] = []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"},
+	}
+	require.NoError(t, unstructured.SetNestedMap(primary.Object, status, "status"))
+	_, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.IsPrimaryReady(mocks.canary)
+	require.NoError(t, err)
+
+	// the canary is waiting for its replicas to be updated
+	retryable, err := mocks.controller.IsCanaryReady(mocks.canary)
+	require.Error(t, err)
+	assert.True(t, retryable)
+}
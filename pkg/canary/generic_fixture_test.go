@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+	fakeFlagger "github.com/fluxcd/flagger/pkg/client/clientset/versioned/fake"
+	"github.com/fluxcd/flagger/pkg/logger"
+)
+
+var cloneSetGVR = schema.GroupVersionResource{Group: "apps.kruise.io", Version: "v1alpha1", Resource: "clonesets"}
+
+type genericControllerFixture struct {
+	canary        *flaggerv1.Canary
+	kubeClient    kubernetes.Interface
+	flaggerClient clientset.Interface
+	dynamicClient *dynamicfake.FakeDynamicClient
+	controller    GenericController
+	logger        *zap.SugaredLogger
+}
+
+func newGenericFixture() genericControllerFixture {
+	canary := newGenericControllerTestCanary()
+	flaggerClient := fakeFlagger.NewSimpleClientset(canary)
+	kubeClient := fake.NewSimpleClientset(newDaemonSetControllerTestConfigMap())
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cloneSetGVR: "CloneSetList"},
+		newGenericControllerTestCloneSet())
+	dynamicClient.PrependReactor("*", "clonesets", scaleReactor(dynamicClient.Tracker()))
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps.kruise.io", Version: "v1alpha1", Kind: "CloneSet"}, meta.RESTScopeNamespace)
+
+	logger, _ := logger.NewLogger("debug")
+
+	ctrl := GenericController{
+		flaggerClient: flaggerClient,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		restMapper:    restMapper,
+		logger:        logger,
+		labels:        []string{"app", "name"},
+		configTracker: &ConfigTracker{
+			Logger:        logger,
+			KubeClient:    kubeClient,
+			FlaggerClient: flaggerClient,
+			DynamicClient: dynamicClient,
+			RESTMapper:    restMapper,
+		},
+	}
+
+	return genericControllerFixture{
+		canary:        canary,
+		kubeClient:    kubeClient,
+		flaggerClient: flaggerClient,
+		dynamicClient: dynamicClient,
+		controller:    ctrl,
+		logger:        logger,
+	}
+}
+
+// scaleReactor serves the scale subresource from the replicas of the tracked objects
+func scaleReactor(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+
+		var name string
+		switch a := action.(type) {
+		case k8stesting.GetAction:
+			name = a.GetName()
+		case k8stesting.UpdateAction:
+			name = a.GetObject().(*unstructured.Unstructured).GetName()
+		default:
+			return false, nil, nil
+		}
+
+		obj, err := tracker.Get(action.GetResource(), action.GetNamespace(), name)
+		if err != nil {
+			return true, nil, err
+		}
+		workload := obj.(*unstructured.Unstructured)
+
+		if update, ok := action.(k8stesting.UpdateAction); ok {
+			replicas, _, _ := unstructured.NestedInt64(update.GetObject().(*unstructured.Unstructured).Object, "spec", "replicas")
+			_ = unstructured.SetNestedField(workload.Object, replicas, "spec", "replicas")
+			if err := tracker.Update(action.GetResource(), workload, action.GetNamespace()); err != nil {
+				return true, nil, err
+			}
+		}
+
+		replicas, _, _ := unstructured.NestedInt64(workload.Object, "spec", "replicas")
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "autoscaling/v1",
+			"kind":       "Scale",
+			"metadata":   map[string]interface{}{"name": name, "namespace": action.GetNamespace()},
+			"spec":       map[string]interface{}{"replicas": replicas},
+		}}, nil
+	}
+}
+
+func newGenericControllerTestCanary() *flaggerv1.Canary {
+	return &flaggerv1.Canary{
+		TypeMeta: metav1.TypeMeta{APIVersion: flaggerv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "podinfo",
+		},
+		Spec: flaggerv1.CanarySpec{
+			TargetRef: flaggerv1.LocalObjectReference{
+				Name:       "podinfo",
+				APIVersion: "apps.kruise.io/v1alpha1",
+				Kind:       "CloneSet",
+			},
+			Workload: &flaggerv1.CanaryWorkload{
+				ReadyCondition: "Ready",
+			},
+		},
+	}
+}
+
+func newGenericControllerTestCloneSet() *unstructured.Unstructured {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "podinfo"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "podinfo",
+					Image: "quay.io/stefanprodan/podinfo:1.2.0",
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 9898, Protocol: corev1.ProtocolTCP},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "podinfo-config-env"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	templateObj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps.kruise.io/v1alpha1",
+		"kind":       "CloneSet",
+		"metadata": map[string]interface{}{
+			"name":      "podinfo",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "podinfo"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "podinfo"},
+			},
+			"template": templateObj,
+			"updateStrategy": map[string]interface{}{
+				"type": "InPlaceIfPossible",
+			},
+		},
+	}}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// IsPrimaryReady checks the primary workload status and returns an error if
+// the workload is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retryable error if the rolling update is stuck
+func (c *GenericController) IsPrimaryReady(cd *flaggerv1.Canary) error {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primary, err := c.getWorkload(cd, primaryName)
+	if err != nil {
+		return err
+	}
+
+	view, _, err := c.isWorkloadReady(cd, primary, cd.GetAnalysisPrimaryReadyThreshold())
+	if err != nil {
+		return fmt.Errorf("%s.%s not ready: %w", primaryName, cd.Namespace, err)
+	}
+
+	if view.Spec.Replicas != nil && *view.Spec.Replicas == 0 {
+		return fmt.Errorf("halt %s.%s advancement: primary %s is scaled to zero",
+			cd.Name, cd.Namespace, cd.Spec.TargetRef.Kind)
+	}
+	return nil
+}
+
+// IsCanaryReady checks the canary workload status and returns an error if
+// the workload is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retriable error if the rolling update is stuck
+func (c *GenericController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.getWorkload(cd, targetName)
+	if err != nil {
+		return true, err
+	}
+
+	_, retryable, err := c.isWorkloadReady(cd, canary, cd.GetAnalysisCanaryReadyThreshold())
+	if err != nil {
+		return retryable, fmt.Errorf(
+			"canary %s %s.%s not ready: %w",
+			cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err,
+		)
+	}
+	return true, nil
+}
+
+// isWorkloadReady maps the workload replicas and status to a Deployment and checks its readiness
+// the same way as for Deployments, along with the ready condition if one is set
+func (c *GenericController) isWorkloadReady(cd *flaggerv1.Canary, obj *unstructured.Unstructured, readyThreshold int) (*appsv1.Deployment, bool, error) {
+	workload := cd.GetWorkload()
+	view := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+			Generation: obj.GetGeneration(),
+		},
+	}
+	if replicas, ok := getWorkloadReplicas(obj, workload.ReplicasPath); ok {
+		view.Spec.Replicas = &replicas
+	}
+	if status, found, _ := unstructured.NestedMap(obj.Object, fieldPath(workload.StatusPath)...); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(status, &view.Status); err != nil {
+			return view, true, fmt.Errorf("status conversion error: %w", err)
+		}
+	}
+
+	retriable, err := isDeploymentStatusReady(view, cd.GetProgressDeadlineSeconds(), readyThreshold)
+	if err != nil {
+		return view, retriable, err
+	}
+
+	if workload.ReadyCondition != "" {
+		condition := getDeploymentCondition(view.Status, appsv1.DeploymentConditionType(workload.ReadyCondition))
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			return view, true, fmt.Errorf("waiting for rollout to finish: condition %s is not true", workload.ReadyCondition)
+		}
+	}
+	return view, true, nil
+}
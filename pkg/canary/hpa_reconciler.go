@@ -238,6 +238,125 @@ func (hr *HPAReconciler) ResumeTargetScaler(cd *flaggerv1.Canary) error {
 	return nil
 }
 
+func (hr *HPAReconciler) PreScalePrimaryScaler(cd *flaggerv1.Canary, replicas int32) (int32, error) {
+	primaryHpaName := fmt.Sprintf("%s-primary", cd.Spec.AutoscalerRef.Name)
+	var min int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hpa, err := hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), primaryHpaName, metav1.GetOptions{})
+		if err != nil {
+			// fallback to v2beta2
+			betaHpa, betaErr := hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), primaryHpaName, metav1.GetOptions{})
+			if betaErr != nil {
+				return fmt.Errorf("HorizontalPodAutoscaler %s.%s get query error for both v2beta2: %s and v2: %s",
+					primaryHpaName, cd.Namespace, betaErr, err)
+			}
+			var update bool
+			min, update = preScaledMinReplicas(betaHpa.Spec.MinReplicas, betaHpa.Spec.MaxReplicas, replicas)
+			if !update {
+				return nil
+			}
+			betaHpaClone := betaHpa.DeepCopy()
+			betaHpaClone.Spec.MinReplicas = &min
+			_, err = hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), betaHpaClone, metav1.UpdateOptions{})
+			return err
+		}
+
+		var update bool
+		min, update = preScaledMinReplicas(hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas, replicas)
+		if !update {
+			return nil
+		}
+		hpaClone := hpa.DeepCopy()
+		hpaClone.Spec.MinReplicas = &min
+		_, err = hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), hpaClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pre-scaling HorizontalPodAutoscaler %s.%s failed: %w", primaryHpaName, cd.Namespace, err)
+	}
+	return min, nil
+}
+
+func (hr *HPAReconciler) SizeTargetScaler(cd *flaggerv1.Canary, replicas int32) error {
+	name := cd.Spec.AutoscalerRef.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hpa, err := hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			// fallback to v2beta2
+			betaHpa, betaErr := hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if betaErr != nil {
+				return fmt.Errorf("HorizontalPodAutoscaler %s.%s get query error for both v2beta2: %s and v2: %s",
+					name, cd.Namespace, betaErr, err)
+			}
+			if isSizedScaler(betaHpa.Annotations, betaHpa.Spec.MinReplicas, betaHpa.Spec.MaxReplicas, replicas) {
+				return nil
+			}
+			betaHpaClone := betaHpa.DeepCopy()
+			betaHpaClone.Annotations = saveScalerBounds(betaHpaClone.Annotations, betaHpa.Spec.MinReplicas, &betaHpa.Spec.MaxReplicas)
+			betaHpaClone.Spec.MinReplicas = int32p(replicas)
+			betaHpaClone.Spec.MaxReplicas = replicas
+			_, err = hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), betaHpaClone, metav1.UpdateOptions{})
+			return err
+		}
+
+		if isSizedScaler(hpa.Annotations, hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas, replicas) {
+			return nil
+		}
+		hpaClone := hpa.DeepCopy()
+		hpaClone.Annotations = saveScalerBounds(hpaClone.Annotations, hpa.Spec.MinReplicas, &hpa.Spec.MaxReplicas)
+		hpaClone.Spec.MinReplicas = int32p(replicas)
+		hpaClone.Spec.MaxReplicas = replicas
+		_, err = hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), hpaClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("sizing HorizontalPodAutoscaler %s.%s failed: %w", name, cd.Namespace, err)
+	}
+	return nil
+}
+
+func (hr *HPAReconciler) RestoreTargetScaler(cd *flaggerv1.Canary) error {
+	name := cd.Spec.AutoscalerRef.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hpa, err := hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			// fallback to v2beta2
+			betaHpa, betaErr := hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if betaErr != nil {
+				return fmt.Errorf("HorizontalPodAutoscaler %s.%s get query error for both v2beta2: %s and v2: %s",
+					name, cd.Namespace, betaErr, err)
+			}
+			betaHpaClone := betaHpa.DeepCopy()
+			min, max, ok, err := loadScalerBounds(betaHpaClone.Annotations)
+			if !ok || err != nil {
+				return err
+			}
+			betaHpaClone.Spec.MinReplicas = min
+			if max != nil {
+				betaHpaClone.Spec.MaxReplicas = *max
+			}
+			_, err = hr.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), betaHpaClone, metav1.UpdateOptions{})
+			return err
+		}
+
+		hpaClone := hpa.DeepCopy()
+		min, max, ok, err := loadScalerBounds(hpaClone.Annotations)
+		if !ok || err != nil {
+			return err
+		}
+		hpaClone.Spec.MinReplicas = min
+		if max != nil {
+			hpaClone.Spec.MaxReplicas = *max
+		}
+		_, err = hr.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Update(context.TODO(), hpaClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("restoring HorizontalPodAutoscaler %s.%s failed: %w", name, cd.Namespace, err)
+	}
+	return nil
+}
+
 func (hr *HPAReconciler) updateObjectMeta(updateMeta, readMeta metav1.ObjectMeta) {
 	// update hpa annotations
 	filteredAnnotations := includeLabelsByPrefix(readMeta.Annotations, hr.includeLabelPrefix)
@@ -10,6 +10,8 @@ import (
 	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 )
 
 func Test_reconcilePrimaryHpa(t *testing.T) {
@@ -106,3 +108,87 @@ func Test_reconcilePrimaryHpaV2Beta2(t *testing.T) {
 	assert.Equal(t, int(*primaryHPA.Spec.Metrics[0].Resource.Target.AverageUtilization), 50)
 	assert.Equal(t, int(primaryHPA.Spec.MaxReplicas), 10)
 }
+
+func Test_preScalePrimaryHpa(t *testing.T) {
+	mocks := newScalerReconcilerFixture(scalerConfig{
+		targetName: "podinfo",
+		scaler:     "HorizontalPodAutoscaler",
+	})
+	hpaReconciler := mocks.scalerReconciler.(*HPAReconciler)
+
+	hpa, err := mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	hpa.Spec.MinReplicas = int32p(2)
+	hpa.Spec.MaxReplicas = 10
+	hpa, err = mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Update(context.TODO(), hpa, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = hpaReconciler.reconcilePrimaryHpaV2(mocks.canary, hpa, true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		replicas int32
+		min      int32
+	}{
+		{replicas: 5, min: 5},
+		{replicas: 3, min: 5},
+		{replicas: 20, min: 10},
+	}
+	for _, tt := range tests {
+		min, err := hpaReconciler.PreScalePrimaryScaler(mocks.canary, tt.replicas)
+		require.NoError(t, err)
+		assert.Equal(t, tt.min, min)
+
+		primaryHPA, err := mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.min, *primaryHPA.Spec.MinReplicas)
+	}
+
+	// the min replicas are restored from the target HPA
+	err = hpaReconciler.reconcilePrimaryHpaV2(mocks.canary, hpa, false)
+	require.NoError(t, err)
+
+	primaryHPA, err := mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *primaryHPA.Spec.MinReplicas)
+}
+
+func Test_sizeTargetHpa(t *testing.T) {
+	mocks := newScalerReconcilerFixture(scalerConfig{
+		targetName: "podinfo",
+		scaler:     "HorizontalPodAutoscaler",
+	})
+	hpaReconciler := mocks.scalerReconciler.(*HPAReconciler)
+
+	hpa, err := mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	hpa.Spec.MinReplicas = int32p(2)
+	hpa.Spec.MaxReplicas = 10
+	_, err = mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Update(context.TODO(), hpa, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the bounds set by the user are saved on the first step
+	for _, replicas := range []int32{1, 4} {
+		err = hpaReconciler.SizeTargetScaler(mocks.canary, replicas)
+		require.NoError(t, err)
+
+		hpa, err = mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, replicas, *hpa.Spec.MinReplicas)
+		assert.Equal(t, replicas, hpa.Spec.MaxReplicas)
+		assert.Equal(t, "2,10", hpa.Annotations[flaggerv1.ScalerBoundsAnnotation])
+	}
+
+	err = hpaReconciler.RestoreTargetScaler(mocks.canary)
+	require.NoError(t, err)
+
+	hpa, err = mocks.kubeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+	assert.NotContains(t, hpa.Annotations, flaggerv1.ScalerBoundsAnnotation)
+
+	// restoring a scaler that wasn't sized is a no-op
+	err = hpaReconciler.RestoreTargetScaler(mocks.canary)
+	require.NoError(t, err)
+}
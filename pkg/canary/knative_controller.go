@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	knativev1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+const knativeRevisionLabel = "serving.knative.dev/revision"
+
+// KnativeController is managing the operations for Knative services,
+// the primary is the last promoted revision and the canary is the latest created revision
+type KnativeController struct {
+	flaggerClient clientset.Interface
+	logger        *zap.SugaredLogger
+}
+
+// SetStatusFailedChecks updates the canary failed checks counter
+func (c *KnativeController) SetStatusFailedChecks(cd *flaggerv1.Canary, val int) error {
+	return setStatusFailedChecks(c.flaggerClient, cd, val)
+}
+
+// SetStatusWeight updates the canary status weight value
+func (c *KnativeController) SetStatusWeight(cd *flaggerv1.Canary, val int) error {
+	return setStatusWeight(c.flaggerClient, cd, val)
+}
+
+// SetStatusIterations updates the canary status iterations value
+func (c *KnativeController) SetStatusIterations(cd *flaggerv1.Canary, val int) error {
+	return setStatusIterations(c.flaggerClient, cd, val)
+}
+
+// SetStatusPhase updates the canary status phase
+func (c *KnativeController) SetStatusPhase(cd *flaggerv1.Canary, phase flaggerv1.CanaryPhase) error {
+	return setStatusPhase(c.flaggerClient, cd, phase)
+}
+
+// GetMetadata returns the revision label selector of the canary pods
+func (c *KnativeController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
+	service, err := c.getService(cd)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return knativeRevisionLabel, service.Status.LatestCreatedRevisionName, nil, nil
+}
+
+// Initialize pins the primary to the latest ready revision if no revision has been promoted yet
+func (c *KnativeController) Initialize(cd *flaggerv1.Canary) error {
+	service, err := c.getService(cd)
+	if err != nil {
+		return err
+	}
+
+	if service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation] != "" {
+		return nil
+	}
+
+	if service.Status.LatestReadyRevisionName == "" {
+		return fmt.Errorf("service %s.%s has no ready revision", cd.Spec.TargetRef.Name, cd.Namespace)
+	}
+
+	if err := c.setPrimaryRevision(cd, service.Status.LatestReadyRevisionName); err != nil {
+		return err
+	}
+
+	c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+		Infof("Revision %s.%s set as primary", service.Status.LatestReadyRevisionName, cd.Namespace)
+	return nil
+}
+
+// Promote sets the latest created revision as primary
+func (c *KnativeController) Promote(cd *flaggerv1.Canary) error {
+	service, err := c.getService(cd)
+	if err != nil {
+		return err
+	}
+
+	return c.setPrimaryRevision(cd, service.Status.LatestCreatedRevisionName)
+}
+
+// IsPrimaryReady checks the ready condition of the primary revision
+func (c *KnativeController) IsPrimaryReady(cd *flaggerv1.Canary) error {
+	service, err := c.getService(cd)
+	if err != nil {
+		return err
+	}
+
+	revisionName := service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation]
+	if revisionName == "" {
+		return fmt.Errorf("service %s.%s has no primary revision", cd.Spec.TargetRef.Name, cd.Namespace)
+	}
+
+	if _, err := c.isRevisionReady(cd, revisionName); err != nil {
+		return fmt.Errorf("%s.%s not ready: %w", revisionName, cd.Namespace, err)
+	}
+	return nil
+}
+
+// IsCanaryReady checks the ready condition of the latest created revision,
+// the revision is not retried if Knative reports it as failed
+func (c *KnativeController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error) {
+	service, err := c.getService(cd)
+	if err != nil {
+		return true, err
+	}
+
+	if service.Generation > service.Status.ObservedGeneration {
+		return true, fmt.Errorf("waiting for service %s.%s spec update to be observed", service.Name, cd.Namespace)
+	}
+
+	revisionName := service.Status.LatestCreatedRevisionName
+	if retryable, err := c.isRevisionReady(cd, revisionName); err != nil {
+		return retryable, fmt.Errorf("%s.%s not ready: %w", revisionName, cd.Namespace, err)
+	}
+	return true, nil
+}
+
+// HasTargetChanged returns true if the revision template has changed
+func (c *KnativeController) HasTargetChanged(cd *flaggerv1.Canary) (bool, error) {
+	service, err := c.getService(cd)
+	if err != nil {
+		return false, err
+	}
+	return hasSpecChanged(cd, service.Spec.Template)
+}
+
+// SyncStatus encodes the revision template and updates the canary status
+func (c *KnativeController) SyncStatus(cd *flaggerv1.Canary, status flaggerv1.CanaryStatus) error {
+	service, err := c.getService(cd)
+	if err != nil {
+		return err
+	}
+	return syncCanaryStatus(c.flaggerClient, cd, status, service.Spec.Template, func(cdCopy *flaggerv1.Canary) {})
+}
+
+// ScaleToZero is a no-op, Knative scales down the revisions that receive no traffic
+func (c *KnativeController) ScaleToZero(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// ScaleFromZero is a no-op, Knative scales up the revisions that receive traffic
+func (c *KnativeController) ScaleFromZero(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+func (c *KnativeController) HaveDependenciesChanged(_ *flaggerv1.Canary) (bool, error) {
+	return false, nil
+}
+
+// Finalize removes the primary revision annotation from the service
+func (c *KnativeController) Finalize(cd *flaggerv1.Canary) error {
+	return c.setPrimaryRevision(cd, "")
+}
+
+func (c *KnativeController) getService(cd *flaggerv1.Canary) (*knativev1.Service, error) {
+	service, err := c.flaggerClient.ServingV1().Services(cd.Namespace).Get(context.TODO(), cd.Spec.TargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("service %s.%s get query error: %w", cd.Spec.TargetRef.Name, cd.Namespace, err)
+	}
+	return service, nil
+}
+
+// setPrimaryRevision records the primary revision name on the service, an empty name removes it
+func (c *KnativeController) setPrimaryRevision(cd *flaggerv1.Canary, revisionName string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := c.getService(cd)
+		if err != nil {
+			return err
+		}
+
+		serviceCopy := service.DeepCopy()
+		if revisionName == "" {
+			delete(serviceCopy.Annotations, flaggerv1.KnativePrimaryRevisionAnnotation)
+		} else {
+			if serviceCopy.Annotations == nil {
+				serviceCopy.Annotations = make(map[string]string)
+			}
+			serviceCopy.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation] = revisionName
+		}
+
+		_, err = c.flaggerClient.ServingV1().Services(cd.Namespace).Update(context.TODO(), serviceCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating service %s.%s primary revision failed: %w", cd.Spec.TargetRef.Name, cd.Namespace, err)
+	}
+	return nil
+}
+
+// isRevisionReady returns an error if the revision ready condition is not true,
+// the error is not retryable if the condition is false
+func (c *KnativeController) isRevisionReady(cd *flaggerv1.Canary, name string) (bool, error) {
+	revision, err := c.flaggerClient.ServingV1().Revisions(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return true, fmt.Errorf("revision %s.%s get query error: %w", name, cd.Namespace, err)
+	}
+
+	condition := revision.Status.GetCondition(knativev1.ConditionReady)
+	switch {
+	case condition == nil:
+		return true, fmt.Errorf("waiting for revision %s to be ready", name)
+	case condition.Status == corev1.ConditionFalse:
+		return false, fmt.Errorf("revision %s failed: %s %s", name, condition.Reason, condition.Message)
+	case condition.Status != corev1.ConditionTrue:
+		return true, fmt.Errorf("waiting for revision %s to be ready: %s", name, condition.Message)
+	}
+	return true, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	knativev1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
+	fakeFlagger "github.com/fluxcd/flagger/pkg/client/clientset/versioned/fake"
+	"github.com/fluxcd/flagger/pkg/logger"
+)
+
+func TestKnativeController_Initialize(t *testing.T) {
+	canary := newKnativeControllerTestCanary()
+	flaggerClient := fakeFlagger.NewSimpleClientset(canary, newKnativeControllerTestService(),
+		newKnativeControllerTestRevision("podinfo-00001", corev1.ConditionTrue))
+	logger, _ := logger.NewLogger("debug")
+	ctrl := &KnativeController{flaggerClient: flaggerClient, logger: logger}
+
+	err := ctrl.Initialize(canary)
+	require.NoError(t, err)
+
+	service, err := flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-00001", service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation])
+
+	err = ctrl.IsPrimaryReady(canary)
+	require.NoError(t, err)
+
+	label, labelValue, _, err := ctrl.GetMetadata(canary)
+	require.NoError(t, err)
+	assert.Equal(t, "serving.knative.dev/revision", label)
+	assert.Equal(t, "podinfo-00001", labelValue)
+
+	// the primary revision is kept when a new revision is created
+	service.Status.LatestReadyRevisionName = "podinfo-00002"
+	service.Status.LatestCreatedRevisionName = "podinfo-00002"
+	_, err = flaggerClient.ServingV1().Services("default").UpdateStatus(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = ctrl.Initialize(canary)
+	require.NoError(t, err)
+
+	service, err = flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-00001", service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation])
+
+	err = ctrl.Finalize(canary)
+	require.NoError(t, err)
+
+	service, err = flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, service.Annotations, flaggerv1.KnativePrimaryRevisionAnnotation)
+}
+
+func TestKnativeController_Promote(t *testing.T) {
+	canary := newKnativeControllerTestCanary()
+	flaggerClient := fakeFlagger.NewSimpleClientset(canary, newKnativeControllerTestService(),
+		newKnativeControllerTestRevision("podinfo-00001", corev1.ConditionTrue))
+	logger, _ := logger.NewLogger("debug")
+	ctrl := &KnativeController{flaggerClient: flaggerClient, logger: logger}
+
+	err := ctrl.Initialize(canary)
+	require.NoError(t, err)
+
+	err = ctrl.SyncStatus(canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseInitialized})
+	require.NoError(t, err)
+	cd, err := flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	isNew, err := ctrl.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	// a new revision is created from the updated template
+	service, err := flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	service.Spec.Template.Spec.Containers[0].Image = "ghcr.io/stefanprodan/podinfo:6.0.1"
+	service.Generation = 2
+	service, err = flaggerClient.ServingV1().Services("default").Update(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err = ctrl.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+
+	retryable, err := ctrl.IsCanaryReady(cd)
+	require.Error(t, err)
+	assert.True(t, retryable)
+
+	service.Status.ObservedGeneration = 2
+	service.Status.LatestCreatedRevisionName = "podinfo-00002"
+	_, err = flaggerClient.ServingV1().Services("default").UpdateStatus(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = flaggerClient.ServingV1().Revisions("default").Create(context.TODO(),
+		newKnativeControllerTestRevision("podinfo-00002", corev1.ConditionFalse), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// failed revisions are not retried
+	retryable, err = ctrl.IsCanaryReady(cd)
+	require.Error(t, err)
+	assert.False(t, retryable)
+
+	revision, err := flaggerClient.ServingV1().Revisions("default").Get(context.TODO(), "podinfo-00002", metav1.GetOptions{})
+	require.NoError(t, err)
+	revision.Status.Conditions[0].Status = corev1.ConditionTrue
+	_, err = flaggerClient.ServingV1().Revisions("default").UpdateStatus(context.TODO(), revision, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	retryable, err = ctrl.IsCanaryReady(cd)
+	require.NoError(t, err)
+	assert.True(t, retryable)
+
+	err = ctrl.Promote(cd)
+	require.NoError(t, err)
+
+	service, err = flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-00002", service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation])
+}
+
+func newKnativeControllerTestCanary() *flaggerv1.Canary {
+	return &flaggerv1.Canary{
+		TypeMeta: metav1.TypeMeta{APIVersion: flaggerv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "podinfo",
+		},
+		Spec: flaggerv1.CanarySpec{
+			Provider: flaggerv1.KnativeProvider,
+			TargetRef: flaggerv1.LocalObjectReference{
+				Name:       "podinfo",
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+			},
+		},
+	}
+}
+
+func newKnativeControllerTestService() *knativev1.Service {
+	return &knativev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: knativev1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "podinfo",
+			Generation: 1,
+		},
+		Spec: knativev1.ServiceSpec{
+			Template: knativev1.RevisionTemplateSpec{
+				Spec: knativev1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "podinfo",
+								Image: "ghcr.io/stefanprodan/podinfo:6.0.0",
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: knativev1.ServiceStatus{
+			Status: knativev1.Status{
+				ObservedGeneration: 1,
+			},
+			LatestReadyRevisionName:   "podinfo-00001",
+			LatestCreatedRevisionName: "podinfo-00001",
+		},
+	}
+}
+
+func newKnativeControllerTestRevision(name string, ready corev1.ConditionStatus) *knativev1.Revision {
+	return &knativev1.Revision{
+		TypeMeta: metav1.TypeMeta{APIVersion: knativev1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Status: knativev1.RevisionStatus{
+			Status: knativev1.Status{
+				Conditions: []knativev1.Condition{
+					{Type: knativev1.ConditionReady, Status: ready, Reason: "ContainerMissing"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// PDBReconciler is a CompanionReconciler that reconciles the PodDisruptionBudgets
+// selecting the target pods. The PodDisruptionBudgets are discovered from the informer cache.
+type PDBReconciler struct {
+	kubeClient         kubernetes.Interface
+	pdbLister          policylisters.PodDisruptionBudgetLister
+	logger             *zap.SugaredLogger
+	includeLabelPrefix []string
+	label              string
+	labelValue         string
+}
+
+func (pr *PDBReconciler) ReconcilePrimaryCompanion(cd *flaggerv1.Canary, init bool) error {
+	pdbs, err := pr.listTargetPdbs(cd)
+	if err != nil {
+		return err
+	}
+
+	for _, pdb := range pdbs {
+		if err := pr.reconcilePrimaryPdb(cd, pdb, init); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pr *PDBReconciler) reconcilePrimaryPdb(cd *flaggerv1.Canary, pdb *policyv1.PodDisruptionBudget, init bool) error {
+	pdbSpec := policyv1.PodDisruptionBudgetSpec{
+		MinAvailable:   pdb.Spec.MinAvailable,
+		MaxUnavailable: pdb.Spec.MaxUnavailable,
+		Selector:       makePrimarySelector(pdb.Spec.Selector, pr.label, pr.labelValue),
+	}
+
+	primaryPdbName := fmt.Sprintf("%s-primary", pdb.Name)
+	primaryPdb, err := pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Get(context.TODO(), primaryPdbName, metav1.GetOptions{})
+
+	// create PDB
+	if errors.IsNotFound(err) {
+		primaryPdb = &policyv1.PodDisruptionBudget{
+			ObjectMeta: makeObjectMeta(primaryPdbName, pdb.Labels, cd),
+			Spec:       pdbSpec,
+		}
+		primaryPdb.Annotations = includeLabelsByPrefix(pdb.Annotations, pr.includeLabelPrefix)
+
+		_, err = pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Create(context.TODO(), primaryPdb, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating PodDisruptionBudget %s.%s failed: %w",
+				primaryPdb.Name, primaryPdb.Namespace, err)
+		}
+		pr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Infof(
+			"PodDisruptionBudget %s.%s created", primaryPdb.GetName(), cd.Namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("PodDisruptionBudget %s.%s get query failed: %w",
+			primaryPdbName, cd.Namespace, err)
+	}
+
+	// update PDB
+	if !init && primaryPdb != nil {
+		targetFields := pdbFields{
+			spec:        pdbSpec,
+			annotations: includeLabelsByPrefix(pdb.Annotations, pr.includeLabelPrefix),
+			labels:      filterMetadata(pdb.Labels),
+		}
+		primaryFields := pdbFields{
+			spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable:   primaryPdb.Spec.MinAvailable,
+				MaxUnavailable: primaryPdb.Spec.MaxUnavailable,
+				Selector:       primaryPdb.Spec.Selector,
+			},
+			annotations: primaryPdb.Annotations,
+			labels:      primaryPdb.Labels,
+		}
+		if hasPDBChanged(targetFields, primaryFields) {
+			err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				primaryPdb, err := pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Get(context.TODO(), primaryPdbName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				primaryPdbClone := primaryPdb.DeepCopy()
+				primaryPdbClone.Spec.MinAvailable = pdbSpec.MinAvailable
+				primaryPdbClone.Spec.MaxUnavailable = pdbSpec.MaxUnavailable
+				primaryPdbClone.Spec.Selector = pdbSpec.Selector
+				primaryPdbClone.Annotations = targetFields.annotations
+				primaryPdbClone.Labels = targetFields.labels
+
+				_, err = pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Update(context.TODO(), primaryPdbClone, metav1.UpdateOptions{})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("updating PodDisruptionBudget %s.%s failed: %w",
+					primaryPdbName, cd.Namespace, err)
+			}
+			pr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+				Infof("PodDisruptionBudget %s.%s updated", primaryPdbName, cd.Namespace)
+		}
+	}
+	return nil
+}
+
+func (pr *PDBReconciler) FinalizePrimaryCompanion(cd *flaggerv1.Canary) error {
+	pdbs, err := pr.listTargetPdbs(cd)
+	if err != nil {
+		return err
+	}
+
+	for _, pdb := range pdbs {
+		primaryPdbName := fmt.Sprintf("%s-primary", pdb.Name)
+		primaryPdb, err := pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Get(context.TODO(), primaryPdbName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("PodDisruptionBudget %s.%s get query failed: %w", primaryPdbName, cd.Namespace, err)
+		}
+		if !isOwnedByCanary(primaryPdb, cd) {
+			continue
+		}
+		err = pr.kubeClient.PolicyV1().PodDisruptionBudgets(cd.Namespace).Delete(context.TODO(), primaryPdbName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("deleting PodDisruptionBudget %s.%s failed: %w", primaryPdbName, cd.Namespace, err)
+		}
+		pr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+			Infof("PodDisruptionBudget %s.%s deleted", primaryPdbName, cd.Namespace)
+	}
+	return nil
+}
+
+// listTargetPdbs returns the PodDisruptionBudgets selecting the target pods,
+// or nil if the PodDisruptionBudget informer is not running
+func (pr *PDBReconciler) listTargetPdbs(cd *flaggerv1.Canary) ([]*policyv1.PodDisruptionBudget, error) {
+	if pr.pdbLister == nil {
+		return nil, nil
+	}
+	pdbs, err := pr.pdbLister.PodDisruptionBudgets(cd.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("PodDisruptionBudget %s list query error: %w", cd.Namespace, err)
+	}
+
+	var targetPdbs []*policyv1.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		if isOwnedByCanary(pdb, cd) || !selectsLabelValue(pdb.Spec.Selector, pr.label, pr.labelValue) {
+			continue
+		}
+		targetPdbs = append(targetPdbs, pdb)
+	}
+	return targetPdbs, nil
+}
+
+type pdbFields struct {
+	spec        policyv1.PodDisruptionBudgetSpec
+	annotations map[string]string
+	labels      map[string]string
+}
+
+func hasPDBChanged(target, primary pdbFields) bool {
+	diffSpec := cmp.Diff(target.spec, primary.spec, cmpopts.EquateEmpty())
+	diffLabels := cmp.Diff(target.labels, primary.labels, cmpopts.EquateEmpty())
+	diffAnnotations := cmp.Diff(target.annotations, primary.annotations, cmpopts.EquateEmpty())
+	return diffSpec != "" || diffLabels != "" || diffAnnotations != ""
+}
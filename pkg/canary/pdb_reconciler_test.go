@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/fluxcd/flagger/pkg/logger"
+)
+
+func TestPDBReconciler_ReconcilePrimaryCompanion(t *testing.T) {
+	cd := newDeploymentControllerTestCanary(canaryConfigs{targetName: "podinfo"})
+	pdbs := []*policyv1.PodDisruptionBudget{
+		newPDBReconcilerTestPDB("podinfo", "podinfo"),
+		newPDBReconcilerTestPDB("backend", "backend"),
+	}
+	kubeClient := fake.NewSimpleClientset(pdbs[0], pdbs[1])
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pdb := range pdbs {
+		require.NoError(t, indexer.Add(pdb))
+	}
+	logger, _ := logger.NewLogger("debug")
+	pdbReconciler := &PDBReconciler{
+		kubeClient: kubeClient,
+		pdbLister:  policylisters.NewPodDisruptionBudgetLister(indexer),
+		logger:     logger,
+		label:      "app",
+		labelValue: "podinfo",
+	}
+
+	err := pdbReconciler.ReconcilePrimaryCompanion(cd, true)
+	require.NoError(t, err)
+
+	primaryPdb, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-primary", primaryPdb.Spec.Selector.MatchLabels["app"])
+	assert.Equal(t, intstr.FromInt(1), *primaryPdb.Spec.MinAvailable)
+
+	// PDBs selecting other pods are ignored
+	_, err = kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "backend-primary", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// the primary PDB is synced on promotion
+	pdb, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	minAvailable := intstr.FromInt(2)
+	pdb.Spec.MinAvailable = &minAvailable
+	pdb, err = kubeClient.PolicyV1().PodDisruptionBudgets("default").Update(context.TODO(), pdb, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, indexer.Update(pdb))
+
+	err = pdbReconciler.ReconcilePrimaryCompanion(cd, false)
+	require.NoError(t, err)
+
+	primaryPdb, err = kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, intstr.FromInt(2), *primaryPdb.Spec.MinAvailable)
+	assert.Equal(t, "podinfo-primary", primaryPdb.Spec.Selector.MatchLabels["app"])
+
+	// the primary PDB is removed on finalization
+	err = pdbReconciler.FinalizePrimaryCompanion(cd)
+	require.NoError(t, err)
+
+	_, err = kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+	_, err = kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func Test_makePrimarySelector(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "podinfo", "tier": "web"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"podinfo"}},
+		},
+	}
+	assert.True(t, selectsLabelValue(selector, "app", "podinfo"))
+	assert.False(t, selectsLabelValue(selector, "app", "backend"))
+
+	primarySelector := makePrimarySelector(selector, "app", "podinfo")
+	assert.Equal(t, map[string]string{"app": "podinfo-primary", "tier": "web"}, primarySelector.MatchLabels)
+	assert.Equal(t, []string{"podinfo-primary"}, primarySelector.MatchExpressions[0].Values)
+
+	// the original selector is left untouched
+	assert.Equal(t, "podinfo", selector.MatchLabels["app"])
+}
+
+func newPDBReconcilerTestPDB(name string, app string) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(1)
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": app},
+			},
+		},
+	}
+}
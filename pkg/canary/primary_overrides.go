@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// applyPrimaryOverrides patches the primary pod template with the canary primary overrides,
+// the overrides are not part of the target spec hash so changing them doesn't trigger an analysis
+func applyPrimaryOverrides(cd *flaggerv1.Canary, template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	overrides := cd.Spec.PrimaryOverrides
+	if overrides == nil || strings.TrimSpace(overrides.Patch) == "" {
+		return template, nil
+	}
+
+	patch, err := yaml.YAMLToJSON([]byte(overrides.Patch))
+	if err != nil {
+		return template, fmt.Errorf("primary overrides patch decoding failed: %w", err)
+	}
+	original, err := json.Marshal(template)
+	if err != nil {
+		return template, fmt.Errorf("pod template encoding failed: %w", err)
+	}
+
+	var patched []byte
+	switch overrides.Type {
+	case flaggerv1.PrimaryOverridesJSONPatch:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return template, fmt.Errorf("primary overrides JSON patch decoding failed: %w", err)
+		}
+		patched, err = ops.Apply(original)
+		if err != nil {
+			return template, fmt.Errorf("primary overrides JSON patch failed: %w", err)
+		}
+	default:
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+		if err != nil {
+			return template, fmt.Errorf("primary overrides strategic merge patch failed: %w", err)
+		}
+	}
+
+	var result corev1.PodTemplateSpec
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return template, fmt.Errorf("patched pod template decoding failed: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func Test_applyPrimaryOverrides(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "podinfo-primary"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "podinfo", Image: "podinfo:1.0.0"},
+				{Name: "sidecar", Image: "sidecar:1.0.0"},
+			},
+		},
+	}
+	cd := &flaggerv1.Canary{}
+
+	t.Run("no overrides", func(t *testing.T) {
+		result, err := applyPrimaryOverrides(cd, template)
+		require.NoError(t, err)
+		assert.Equal(t, template, result)
+	})
+
+	t.Run("strategic merge patch", func(t *testing.T) {
+		cd.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+			Patch: `
+spec:
+  containers:
+    - name: podinfo
+      resources:
+        limits:
+          cpu: "2"
+`,
+		}
+		result, err := applyPrimaryOverrides(cd, template)
+		require.NoError(t, err)
+		require.Len(t, result.Spec.Containers, 2)
+		assert.Equal(t, "podinfo:1.0.0", result.Spec.Containers[0].Image)
+		assert.Equal(t, resource.MustParse("2"), result.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU])
+		assert.Equal(t, "podinfo-primary", result.Labels["app"])
+		assert.Empty(t, template.Spec.Containers[0].Resources.Limits)
+	})
+
+	t.Run("JSON patch", func(t *testing.T) {
+		cd.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+			Type: flaggerv1.PrimaryOverridesJSONPatch,
+			Patch: `
+- op: replace
+  path: /spec/containers/1/image
+  value: sidecar:2.0.0
+`,
+		}
+		result, err := applyPrimaryOverrides(cd, template)
+		require.NoError(t, err)
+		assert.Equal(t, "sidecar:2.0.0", result.Spec.Containers[1].Image)
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		cd.Spec.PrimaryOverrides = &flaggerv1.CanaryPrimaryOverrides{
+			Type:  flaggerv1.PrimaryOverridesJSONPatch,
+			Patch: `[{"op": "remove", "path": "/spec/hostname"}]`,
+		}
+		_, err := applyPrimaryOverrides(cd, template)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// ReplicaSizer is implemented by the controllers that can size
+// the canary workload to the traffic weight it receives
+type ReplicaSizer interface {
+	// ScaleToWeight sets the canary replicas for the traffic weight and returns them
+	ScaleToWeight(canary *flaggerv1.Canary, weight int, totalWeight int) (int32, error)
+	// RestoreReplicas restores the canary replicas saved by ScaleToWeight
+	RestoreReplicas(canary *flaggerv1.Canary) error
+}
+
+// saveReplicas records the workload replicas in the annotations
+// if they weren't already recorded by a previous sizing
+func saveReplicas(annotations map[string]string, replicas *int32) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, ok := annotations[flaggerv1.ReplicasAnnotation]; !ok {
+		annotations[flaggerv1.ReplicasAnnotation] = formatReplicas(replicas)
+	}
+	return annotations
+}
+
+// loadReplicas returns the replicas recorded in the workload annotations,
+// ok is false if no replicas were recorded
+func loadReplicas(annotations map[string]string) (replicas *int32, ok bool, err error) {
+	value, ok := annotations[flaggerv1.ReplicasAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	if replicas, err = parseReplicas(value); err != nil {
+		return nil, false, fmt.Errorf("annotation %s=%s parse error: %w", flaggerv1.ReplicasAnnotation, value, err)
+	}
+	return replicas, true, nil
+}
+
+// canaryReplicas returns the number of replicas needed to serve the given share
+// of the traffic handled by the primary capacity, plus the headroom
+func canaryReplicas(sizing *flaggerv1.CanaryReplicaSizing, capacity int32, weight int, totalWeight int) int32 {
+	floor := int32(1)
+	if sizing.MinReplicas != nil && *sizing.MinReplicas > 0 {
+		floor = *sizing.MinReplicas
+	}
+	if totalWeight < 1 {
+		return floor
+	}
+
+	share := int64(capacity) * int64(weight) * int64(100+sizing.Headroom)
+	replicas := int32((share + int64(totalWeight)*100 - 1) / (int64(totalWeight) * 100))
+	if replicas > capacity {
+		replicas = capacity
+	}
+	if replicas < floor {
+		replicas = floor
+	}
+	return replicas
+}
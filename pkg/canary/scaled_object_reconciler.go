@@ -152,6 +152,86 @@ func (sor *ScaledObjectReconciler) ResumeTargetScaler(cd *flaggerv1.Canary) erro
 	return err
 }
 
+func (sor *ScaledObjectReconciler) PreScalePrimaryScaler(cd *flaggerv1.Canary, replicas int32) (int32, error) {
+	primarySoName := fmt.Sprintf("%s-primary", cd.Spec.AutoscalerRef.Name)
+	var min int32
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		so, err := sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Get(context.TODO(), primarySoName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Keda ScaledObject %s.%s get query error: %w", primarySoName, cd.Namespace, err)
+		}
+
+		// KEDA defaults the max replica count to 100
+		max := int32(100)
+		if so.Spec.MaxReplicaCount != nil {
+			max = *so.Spec.MaxReplicaCount
+		}
+		var update bool
+		min, update = preScaledMinReplicas(so.Spec.MinReplicaCount, max, replicas)
+		if !update {
+			return nil
+		}
+
+		soClone := so.DeepCopy()
+		soClone.Spec.MinReplicaCount = &min
+		_, err = sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Update(context.TODO(), soClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pre-scaling ScaledObject %s.%s failed: %w", primarySoName, cd.Namespace, err)
+	}
+	return min, nil
+}
+
+func (sor *ScaledObjectReconciler) SizeTargetScaler(cd *flaggerv1.Canary, replicas int32) error {
+	name := cd.Spec.AutoscalerRef.Name
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		so, err := sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Keda ScaledObject %s.%s get query error: %w", name, cd.Namespace, err)
+		}
+
+		if so.Spec.MaxReplicaCount != nil &&
+			isSizedScaler(so.Annotations, so.Spec.MinReplicaCount, *so.Spec.MaxReplicaCount, replicas) {
+			return nil
+		}
+		soClone := so.DeepCopy()
+		soClone.Annotations = saveScalerBounds(soClone.Annotations, so.Spec.MinReplicaCount, so.Spec.MaxReplicaCount)
+		soClone.Spec.MinReplicaCount = int32p(replicas)
+		soClone.Spec.MaxReplicaCount = int32p(replicas)
+		_, err = sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Update(context.TODO(), soClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("sizing ScaledObject %s.%s failed: %w", name, cd.Namespace, err)
+	}
+	return nil
+}
+
+func (sor *ScaledObjectReconciler) RestoreTargetScaler(cd *flaggerv1.Canary) error {
+	name := cd.Spec.AutoscalerRef.Name
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		so, err := sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Keda ScaledObject %s.%s get query error: %w", name, cd.Namespace, err)
+		}
+
+		soClone := so.DeepCopy()
+		min, max, ok, err := loadScalerBounds(soClone.Annotations)
+		if !ok || err != nil {
+			return err
+		}
+		soClone.Spec.MinReplicaCount = min
+		soClone.Spec.MaxReplicaCount = max
+		_, err = sor.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Update(context.TODO(), soClone, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("restoring ScaledObject %s.%s failed: %w", name, cd.Namespace, err)
+	}
+	return nil
+}
+
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 func randSeq() string {
@@ -79,6 +79,63 @@ func Test_resumeScaledObject(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func Test_preScalePrimaryScaledObject(t *testing.T) {
+	mocks := newScalerReconcilerFixture(scalerConfig{
+		targetName: "podinfo",
+		scaler:     "ScaledObject",
+	})
+
+	soReconciler := mocks.scalerReconciler.(*ScaledObjectReconciler)
+	err := soReconciler.reconcilePrimaryScaler(mocks.canary, true)
+	require.NoError(t, err)
+
+	min, err := soReconciler.PreScalePrimaryScaler(mocks.canary, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), min)
+
+	// capped at the max replica count
+	min, err = soReconciler.PreScalePrimaryScaler(mocks.canary, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), min)
+
+	primarySO, err := mocks.flaggerClient.KedaV1alpha1().ScaledObjects("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), *primarySO.Spec.MinReplicaCount)
+
+	// the min replica count is restored from the target scaled object
+	err = soReconciler.reconcilePrimaryScaler(mocks.canary, false)
+	require.NoError(t, err)
+
+	primarySO, err = mocks.flaggerClient.KedaV1alpha1().ScaledObjects("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *primarySO.Spec.MinReplicaCount)
+}
+
+func Test_sizeTargetScaledObject(t *testing.T) {
+	mocks := newScalerReconcilerFixture(scalerConfig{
+		targetName: "podinfo",
+		scaler:     "ScaledObject",
+	})
+	soReconciler := mocks.scalerReconciler.(*ScaledObjectReconciler)
+
+	err := soReconciler.SizeTargetScaler(mocks.canary, 2)
+	require.NoError(t, err)
+
+	so, err := mocks.flaggerClient.KedaV1alpha1().ScaledObjects("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *so.Spec.MinReplicaCount)
+	assert.Equal(t, int32(2), *so.Spec.MaxReplicaCount)
+
+	err = soReconciler.RestoreTargetScaler(mocks.canary)
+	require.NoError(t, err)
+
+	so, err = mocks.flaggerClient.KedaV1alpha1().ScaledObjects("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *so.Spec.MinReplicaCount)
+	assert.Equal(t, int32(4), *so.Spec.MaxReplicaCount)
+	assert.NotContains(t, so.Annotations, flaggerv1.ScalerBoundsAnnotation)
+}
+
 func Test_setPrimaryScaledObjectQueries(t *testing.T) {
 	cd := &flaggerv1.Canary{
 		Spec: flaggerv1.CanarySpec{
@@ -1,6 +1,10 @@
 package canary
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 )
 
@@ -10,4 +14,86 @@ type ScalerReconciler interface {
 	ReconcilePrimaryScaler(cd *flaggerv1.Canary, init bool) error
 	PauseTargetScaler(cd *flaggerv1.Canary) error
 	ResumeTargetScaler(cd *flaggerv1.Canary) error
+	// PreScalePrimaryScaler raises the min replicas of the primary scaler to the given
+	// value capped at its max replicas, and returns the resulting min replicas
+	PreScalePrimaryScaler(cd *flaggerv1.Canary, replicas int32) (int32, error)
+	// SizeTargetScaler sets the min and max replicas of the canary scaler to the given value,
+	// the bounds are saved in the scaler annotations the first time
+	SizeTargetScaler(cd *flaggerv1.Canary, replicas int32) error
+	// RestoreTargetScaler restores the min and max replicas of the canary scaler saved by SizeTargetScaler
+	RestoreTargetScaler(cd *flaggerv1.Canary) error
+}
+
+// preScaledMinReplicas returns the min replicas of a scaler raised to the given replicas
+// and capped at max, and whether the scaler must be updated
+func preScaledMinReplicas(current *int32, max int32, replicas int32) (int32, bool) {
+	min := int32(1)
+	if current != nil {
+		min = *current
+	}
+	if replicas > max {
+		replicas = max
+	}
+	if replicas <= min {
+		return min, false
+	}
+	return replicas, true
+}
+
+// saveScalerBounds records the min and max replicas of a scaler in its annotations,
+// the bounds recorded before are kept
+func saveScalerBounds(annotations map[string]string, min *int32, max *int32) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, ok := annotations[flaggerv1.ScalerBoundsAnnotation]; !ok {
+		annotations[flaggerv1.ScalerBoundsAnnotation] = formatReplicas(min) + "," + formatReplicas(max)
+	}
+	return annotations
+}
+
+// loadScalerBounds returns the min and max replicas recorded in the scaler annotations
+// and removes the annotation, ok is false if no bounds were recorded
+func loadScalerBounds(annotations map[string]string) (min *int32, max *int32, ok bool, err error) {
+	value, ok := annotations[flaggerv1.ScalerBoundsAnnotation]
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	bounds := strings.Split(value, ",")
+	if len(bounds) != 2 {
+		return nil, nil, false, fmt.Errorf("annotation %s=%s parse error", flaggerv1.ScalerBoundsAnnotation, value)
+	}
+	if min, err = parseReplicas(bounds[0]); err != nil {
+		return nil, nil, false, fmt.Errorf("annotation %s=%s parse error: %w", flaggerv1.ScalerBoundsAnnotation, value, err)
+	}
+	if max, err = parseReplicas(bounds[1]); err != nil {
+		return nil, nil, false, fmt.Errorf("annotation %s=%s parse error: %w", flaggerv1.ScalerBoundsAnnotation, value, err)
+	}
+	delete(annotations, flaggerv1.ScalerBoundsAnnotation)
+	return min, max, true, nil
+}
+
+// isSizedScaler returns true if the scaler bounds are saved and its min and max replicas are set to the given value
+func isSizedScaler(annotations map[string]string, min *int32, max int32, replicas int32) bool {
+	_, ok := annotations[flaggerv1.ScalerBoundsAnnotation]
+	return ok && min != nil && *min == replicas && max == replicas
+}
+
+func formatReplicas(replicas *int32) string {
+	if replicas == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*replicas))
+}
+
+func parseReplicas(value string) (*int32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	return int32p(int32(replicas)), nil
 }
@@ -48,6 +48,9 @@ func syncCanaryStatus(flaggerClient clientset.Interface, cd *flaggerv1.Canary, s
 		cdCopy.Status.FailedChecks = status.FailedChecks
 		cdCopy.Status.Iterations = status.Iterations
 		cdCopy.Status.LastAppliedSpec = hash
+		if status.Phase != flaggerv1.CanaryPhaseQueued {
+			cdCopy.Status.QueuePosition = 0
+		}
 		if status.Phase == flaggerv1.CanaryPhaseInitialized {
 			cdCopy.Status.LastPromotedSpec = hash
 		}
@@ -157,6 +160,9 @@ func setStatusPhase(flaggerClient clientset.Interface, cd *flaggerv1.Canary, pha
 		cdCopy := cd.DeepCopy()
 		cdCopy.Status.Phase = phase
 		cdCopy.Status.LastTransitionTime = metav1.Now()
+		if phase != flaggerv1.CanaryPhaseQueued {
+			cdCopy.Status.QueuePosition = 0
+		}
 
 		if phase != flaggerv1.CanaryPhaseProgressing && phase != flaggerv1.CanaryPhaseWaiting {
 			cdCopy.Status.CanaryWeight = 0
@@ -213,6 +219,9 @@ func MakeStatusConditions(cd *flaggerv1.Canary,
 	case flaggerv1.CanaryPhaseWaiting:
 		status = corev1.ConditionUnknown
 		message = "Waiting for approval."
+	case flaggerv1.CanaryPhaseQueued:
+		status = corev1.ConditionUnknown
+		message = "Waiting in the rollout queue, concurrency limit reached."
 	case flaggerv1.CanaryPhaseWaitingPromotion:
 		status = corev1.ConditionUnknown
 		message = "Waiting for approval."
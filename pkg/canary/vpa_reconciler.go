@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// VPAResource is the resource of the VerticalPodAutoscalers reconciled by the VPAReconciler
+var VPAResource = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}
+
+// VPAReconciler is a CompanionReconciler that reconciles the VerticalPodAutoscalers
+// targeting the canary workload. The VerticalPodAutoscalers are discovered from the informer cache.
+type VPAReconciler struct {
+	dynamicClient      dynamic.Interface
+	vpaLister          cache.GenericLister
+	logger             *zap.SugaredLogger
+	includeLabelPrefix []string
+}
+
+func (vr *VPAReconciler) ReconcilePrimaryCompanion(cd *flaggerv1.Canary, init bool) error {
+	vpas, err := vr.listTargetVpas(cd)
+	if err != nil {
+		return err
+	}
+
+	for _, vpa := range vpas {
+		if err := vr.reconcilePrimaryVpa(cd, vpa, init); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vr *VPAReconciler) reconcilePrimaryVpa(cd *flaggerv1.Canary, vpa *unstructured.Unstructured, init bool) error {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	vpaSpec, _, err := unstructured.NestedMap(vpa.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("VerticalPodAutoscaler %s.%s spec is invalid: %w", vpa.GetName(), cd.Namespace, err)
+	}
+	if err := unstructured.SetNestedField(vpaSpec, primaryName, "targetRef", "name"); err != nil {
+		return fmt.Errorf("VerticalPodAutoscaler %s.%s targetRef is invalid: %w", vpa.GetName(), cd.Namespace, err)
+	}
+	annotations := includeLabelsByPrefix(vpa.GetAnnotations(), vr.includeLabelPrefix)
+
+	primaryVpaName := fmt.Sprintf("%s-primary", vpa.GetName())
+	client := vr.dynamicClient.Resource(VPAResource).Namespace(cd.Namespace)
+	primaryVpa, err := client.Get(context.TODO(), primaryVpaName, metav1.GetOptions{})
+
+	// create VPA
+	if errors.IsNotFound(err) {
+		primaryVpa = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": vpa.GetAPIVersion(),
+			"kind":       vpa.GetKind(),
+			"spec":       vpaSpec,
+		}}
+		meta := makeObjectMeta(primaryVpaName, vpa.GetLabels(), cd)
+		primaryVpa.SetName(meta.Name)
+		primaryVpa.SetNamespace(meta.Namespace)
+		primaryVpa.SetLabels(meta.Labels)
+		primaryVpa.SetAnnotations(annotations)
+		primaryVpa.SetOwnerReferences(meta.OwnerReferences)
+
+		_, err = client.Create(context.TODO(), primaryVpa, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating VerticalPodAutoscaler %s.%s failed: %w",
+				primaryVpaName, cd.Namespace, err)
+		}
+		vr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Infof(
+			"VerticalPodAutoscaler %s.%s created", primaryVpaName, cd.Namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("VerticalPodAutoscaler %s.%s get query failed: %w",
+			primaryVpaName, cd.Namespace, err)
+	}
+
+	// update VPA
+	if !init && primaryVpa != nil {
+		targetFields := vpaFields{
+			spec:        vpaSpec,
+			annotations: annotations,
+			labels:      filterMetadata(vpa.GetLabels()),
+		}
+		primarySpec, _, _ := unstructured.NestedMap(primaryVpa.Object, "spec")
+		primaryFields := vpaFields{
+			spec:        primarySpec,
+			annotations: primaryVpa.GetAnnotations(),
+			labels:      primaryVpa.GetLabels(),
+		}
+		if hasVPAChanged(targetFields, primaryFields) {
+			err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				primaryVpa, err := client.Get(context.TODO(), primaryVpaName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				primaryVpaClone := primaryVpa.DeepCopy()
+				if err := unstructured.SetNestedMap(primaryVpaClone.Object, vpaSpec, "spec"); err != nil {
+					return err
+				}
+				primaryVpaClone.SetAnnotations(targetFields.annotations)
+				primaryVpaClone.SetLabels(targetFields.labels)
+
+				_, err = client.Update(context.TODO(), primaryVpaClone, metav1.UpdateOptions{})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("updating VerticalPodAutoscaler %s.%s failed: %w",
+					primaryVpaName, cd.Namespace, err)
+			}
+			vr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+				Infof("VerticalPodAutoscaler %s.%s updated", primaryVpaName, cd.Namespace)
+		}
+	}
+	return nil
+}
+
+func (vr *VPAReconciler) FinalizePrimaryCompanion(cd *flaggerv1.Canary) error {
+	vpas, err := vr.listTargetVpas(cd)
+	if err != nil {
+		return err
+	}
+
+	client := vr.dynamicClient.Resource(VPAResource).Namespace(cd.Namespace)
+	for _, vpa := range vpas {
+		primaryVpaName := fmt.Sprintf("%s-primary", vpa.GetName())
+		primaryVpa, err := client.Get(context.TODO(), primaryVpaName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("VerticalPodAutoscaler %s.%s get query failed: %w", primaryVpaName, cd.Namespace, err)
+		}
+		if !isOwnedByCanary(primaryVpa, cd) {
+			continue
+		}
+		err = client.Delete(context.TODO(), primaryVpaName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("deleting VerticalPodAutoscaler %s.%s failed: %w", primaryVpaName, cd.Namespace, err)
+		}
+		vr.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+			Infof("VerticalPodAutoscaler %s.%s deleted", primaryVpaName, cd.Namespace)
+	}
+	return nil
+}
+
+// listTargetVpas returns the VerticalPodAutoscalers targeting the canary workload,
+// or nil if the VerticalPodAutoscaler CRD is not installed
+func (vr *VPAReconciler) listTargetVpas(cd *flaggerv1.Canary) ([]*unstructured.Unstructured, error) {
+	if vr.dynamicClient == nil || vr.vpaLister == nil {
+		return nil, nil
+	}
+	objects, err := vr.vpaLister.ByNamespace(cd.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("VerticalPodAutoscaler %s list query error: %w", cd.Namespace, err)
+	}
+
+	var vpas []*unstructured.Unstructured
+	for _, object := range objects {
+		vpa, ok := object.(*unstructured.Unstructured)
+		if !ok || isOwnedByCanary(vpa, cd) {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+		name, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+		if kind != cd.Spec.TargetRef.Kind || name != cd.Spec.TargetRef.Name {
+			continue
+		}
+		vpas = append(vpas, vpa)
+	}
+	return vpas, nil
+}
+
+type vpaFields struct {
+	spec        map[string]interface{}
+	annotations map[string]string
+	labels      map[string]string
+}
+
+func hasVPAChanged(target, primary vpaFields) bool {
+	diffSpec := cmp.Diff(target.spec, primary.spec, cmpopts.EquateEmpty())
+	diffLabels := cmp.Diff(target.labels, primary.labels, cmpopts.EquateEmpty())
+	diffAnnotations := cmp.Diff(target.annotations, primary.annotations, cmpopts.EquateEmpty())
+	return diffSpec != "" || diffLabels != "" || diffAnnotations != ""
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/fluxcd/flagger/pkg/logger"
+)
+
+func TestVPAReconciler_ReconcilePrimaryCompanion(t *testing.T) {
+	cd := newDeploymentControllerTestCanary(canaryConfigs{targetName: "podinfo"})
+	vpas := []*unstructured.Unstructured{
+		newVPAReconcilerTestVPA("podinfo", "podinfo"),
+		newVPAReconcilerTestVPA("backend", "backend"),
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{VPAResource: "VerticalPodAutoscalerList"},
+		vpas[0], vpas[1],
+	)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, vpa := range vpas {
+		require.NoError(t, indexer.Add(vpa))
+	}
+	logger, _ := logger.NewLogger("debug")
+	vpaReconciler := &VPAReconciler{
+		dynamicClient: dynamicClient,
+		vpaLister:     cache.NewGenericLister(indexer, VPAResource.GroupResource()),
+		logger:        logger,
+	}
+	vpaClient := dynamicClient.Resource(VPAResource).Namespace("default")
+
+	err := vpaReconciler.ReconcilePrimaryCompanion(cd, true)
+	require.NoError(t, err)
+
+	primaryVpa, err := vpaClient.Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	targetName, _, _ := unstructured.NestedString(primaryVpa.Object, "spec", "targetRef", "name")
+	assert.Equal(t, "podinfo-primary", targetName)
+
+	// VPAs targeting other workloads are ignored
+	_, err = vpaClient.Get(context.TODO(), "backend-primary", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// the primary VPA is synced on promotion
+	vpa, err := vpaClient.Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(vpa.Object, "Initial", "spec", "updatePolicy", "updateMode"))
+	vpa, err = vpaClient.Update(context.TODO(), vpa, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, indexer.Update(vpa))
+
+	err = vpaReconciler.ReconcilePrimaryCompanion(cd, false)
+	require.NoError(t, err)
+
+	primaryVpa, err = vpaClient.Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	updateMode, _, _ := unstructured.NestedString(primaryVpa.Object, "spec", "updatePolicy", "updateMode")
+	assert.Equal(t, "Initial", updateMode)
+	targetName, _, _ = unstructured.NestedString(primaryVpa.Object, "spec", "targetRef", "name")
+	assert.Equal(t, "podinfo-primary", targetName)
+
+	// the primary VPA is removed on finalization
+	err = vpaReconciler.FinalizePrimaryCompanion(cd)
+	require.NoError(t, err)
+
+	_, err = vpaClient.Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+	_, err = vpaClient.Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func newVPAReconcilerTestVPA(name string, target string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       target,
+			},
+			"updatePolicy": map[string]interface{}{
+				"updateMode": "Auto",
+			},
+		},
+	}}
+}
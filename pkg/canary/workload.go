@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// workloadResource returns the dynamic client of the canary target kind
+func workloadResource(dynamicClient dynamic.Interface, restMapper meta.RESTMapper, cd *flaggerv1.Canary) (dynamic.ResourceInterface, error) {
+	if dynamicClient == nil || restMapper == nil {
+		return nil, fmt.Errorf("TargetRef.Kind invalid: %s", cd.Spec.TargetRef.Kind)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(cd.Spec.TargetRef.APIVersion, cd.Spec.TargetRef.Kind)
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%s REST mapping error: %w", gvk.String(), err)
+	}
+	return dynamicClient.Resource(mapping.Resource).Namespace(cd.Namespace), nil
+}
+
+// fieldPath splits a JSONPath in dot notation, e.g. `{.spec.template}`, into fields
+func fieldPath(path string) []string {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
+
+// getWorkloadPodTemplate returns the pod template found at the given path
+func getWorkloadPodTemplate(obj *unstructured.Unstructured, path string) (*corev1.PodTemplateSpec, error) {
+	field, found, err := unstructured.NestedMap(obj.Object, fieldPath(path)...)
+	if err != nil || !found {
+		return nil, fmt.Errorf("%s %s.%s has no pod template at %s", obj.GetKind(), obj.GetName(), obj.GetNamespace(), path)
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, template); err != nil {
+		return nil, fmt.Errorf("%s %s.%s pod template conversion error: %w", obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return template, nil
+}
+
+// getWorkloadSelector returns the pod label selector found at the given path
+func getWorkloadSelector(obj *unstructured.Unstructured, path string) (*metav1.LabelSelector, error) {
+	field, found, err := unstructured.NestedMap(obj.Object, fieldPath(path)...)
+	if err != nil || !found {
+		return nil, fmt.Errorf("%s %s.%s has no selector at %s", obj.GetKind(), obj.GetName(), obj.GetNamespace(), path)
+	}
+
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, selector); err != nil {
+		return nil, fmt.Errorf("%s %s.%s selector conversion error: %w", obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return selector, nil
+}
+
+// getWorkloadReplicas returns the replicas found at the given path
+func getWorkloadReplicas(obj *unstructured.Unstructured, path string) (int32, bool) {
+	field, found, err := unstructured.NestedFieldNoCopy(obj.Object, fieldPath(path)...)
+	if err != nil || !found {
+		return 0, false
+	}
+
+	switch v := field.(type) {
+	case int64:
+		return int32(v), true
+	case float64:
+		return int32(v), true
+	default:
+		return 0, false
+	}
+}
+
+// setWorkloadField converts the value to its unstructured form and sets it at the given path
+func setWorkloadField(obj *unstructured.Unstructured, path string, value interface{}) error {
+	field, err := runtime.DefaultUnstructuredConverter.ToUnstructured(value)
+	if err != nil {
+		return fmt.Errorf("%s %s.%s conversion error at %s: %w", obj.GetKind(), obj.GetName(), obj.GetNamespace(), path, err)
+	}
+	return unstructured.SetNestedMap(obj.Object, field, fieldPath(path)...)
+}
@@ -25,19 +25,23 @@ import (
 	apisixv2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/apisix/v2"
 	appmeshv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/appmesh/v1beta1"
 	appmeshv1beta2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/appmesh/v1beta2"
+	consulv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/consul/v1alpha1"
 	flaggerv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/flagger/v1beta1"
 	gatewayv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gateway/v1"
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1"
 	gatewayapiv1alpha2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1alpha2"
 	gatewayapiv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1beta1"
 	gloov1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gloo/v1"
 	networkingv1alpha3 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/istio/v1alpha3"
 	kedav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/keda/v1alpha1"
+	servingv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/knative/v1"
 	kumav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/kuma/v1alpha1"
 	projectcontourv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/projectcontour/v1"
 	splitv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/smi/v1alpha1"
 	splitv1alpha2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/smi/v1alpha2"
 	splitv1alpha3 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/smi/v1alpha3"
 	traefikv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefik/v1alpha1"
+	traefikiov1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefikio/v1alpha1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -48,19 +52,23 @@ type Interface interface {
 	ApisixV2() apisixv2.ApisixV2Interface
 	AppmeshV1beta2() appmeshv1beta2.AppmeshV1beta2Interface
 	AppmeshV1beta1() appmeshv1beta1.AppmeshV1beta1Interface
+	ConsulV1alpha1() consulv1alpha1.ConsulV1alpha1Interface
 	FlaggerV1beta1() flaggerv1beta1.FlaggerV1beta1Interface
 	GatewayV1() gatewayv1.GatewayV1Interface
 	GatewayapiV1alpha2() gatewayapiv1alpha2.GatewayapiV1alpha2Interface
 	GatewayapiV1beta1() gatewayapiv1beta1.GatewayapiV1beta1Interface
+	GatewayapiV1() gatewayapiv1.GatewayapiV1Interface
 	GlooV1() gloov1.GlooV1Interface
 	NetworkingV1alpha3() networkingv1alpha3.NetworkingV1alpha3Interface
 	KedaV1alpha1() kedav1alpha1.KedaV1alpha1Interface
+	ServingV1() servingv1.ServingV1Interface
 	KumaV1alpha1() kumav1alpha1.KumaV1alpha1Interface
 	ProjectcontourV1() projectcontourv1.ProjectcontourV1Interface
 	SplitV1alpha1() splitv1alpha1.SplitV1alpha1Interface
 	SplitV1alpha2() splitv1alpha2.SplitV1alpha2Interface
 	SplitV1alpha3() splitv1alpha3.SplitV1alpha3Interface
 	TraefikV1alpha1() traefikv1alpha1.TraefikV1alpha1Interface
+	TraefikIOV1alpha1() traefikiov1alpha1.TraefikIOV1alpha1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -70,19 +78,23 @@ type Clientset struct {
 	apisixV2           *apisixv2.ApisixV2Client
 	appmeshV1beta2     *appmeshv1beta2.AppmeshV1beta2Client
 	appmeshV1beta1     *appmeshv1beta1.AppmeshV1beta1Client
+	consulV1alpha1     *consulv1alpha1.ConsulV1alpha1Client
 	flaggerV1beta1     *flaggerv1beta1.FlaggerV1beta1Client
 	gatewayV1          *gatewayv1.GatewayV1Client
 	gatewayapiV1alpha2 *gatewayapiv1alpha2.GatewayapiV1alpha2Client
 	gatewayapiV1beta1  *gatewayapiv1beta1.GatewayapiV1beta1Client
+	gatewayapiV1       *gatewayapiv1.GatewayapiV1Client
 	glooV1             *gloov1.GlooV1Client
 	networkingV1alpha3 *networkingv1alpha3.NetworkingV1alpha3Client
 	kedaV1alpha1       *kedav1alpha1.KedaV1alpha1Client
+	servingV1          *servingv1.ServingV1Client
 	kumaV1alpha1       *kumav1alpha1.KumaV1alpha1Client
 	projectcontourV1   *projectcontourv1.ProjectcontourV1Client
 	splitV1alpha1      *splitv1alpha1.SplitV1alpha1Client
 	splitV1alpha2      *splitv1alpha2.SplitV1alpha2Client
 	splitV1alpha3      *splitv1alpha3.SplitV1alpha3Client
 	traefikV1alpha1    *traefikv1alpha1.TraefikV1alpha1Client
+	traefikIOV1alpha1  *traefikiov1alpha1.TraefikIOV1alpha1Client
 }
 
 // ApisixV2 retrieves the ApisixV2Client
@@ -100,6 +112,11 @@ func (c *Clientset) AppmeshV1beta1() appmeshv1beta1.AppmeshV1beta1Interface {
 	return c.appmeshV1beta1
 }
 
+// ConsulV1alpha1 retrieves the ConsulV1alpha1Client
+func (c *Clientset) ConsulV1alpha1() consulv1alpha1.ConsulV1alpha1Interface {
+	return c.consulV1alpha1
+}
+
 // FlaggerV1beta1 retrieves the FlaggerV1beta1Client
 func (c *Clientset) FlaggerV1beta1() flaggerv1beta1.FlaggerV1beta1Interface {
 	return c.flaggerV1beta1
@@ -120,6 +137,11 @@ func (c *Clientset) GatewayapiV1beta1() gatewayapiv1beta1.GatewayapiV1beta1Inter
 	return c.gatewayapiV1beta1
 }
 
+// GatewayapiV1 retrieves the GatewayapiV1Client
+func (c *Clientset) GatewayapiV1() gatewayapiv1.GatewayapiV1Interface {
+	return c.gatewayapiV1
+}
+
 // GlooV1 retrieves the GlooV1Client
 func (c *Clientset) GlooV1() gloov1.GlooV1Interface {
 	return c.glooV1
@@ -135,6 +157,11 @@ func (c *Clientset) KedaV1alpha1() kedav1alpha1.KedaV1alpha1Interface {
 	return c.kedaV1alpha1
 }
 
+// ServingV1 retrieves the ServingV1Client
+func (c *Clientset) ServingV1() servingv1.ServingV1Interface {
+	return c.servingV1
+}
+
 // KumaV1alpha1 retrieves the KumaV1alpha1Client
 func (c *Clientset) KumaV1alpha1() kumav1alpha1.KumaV1alpha1Interface {
 	return c.kumaV1alpha1
@@ -165,6 +192,11 @@ func (c *Clientset) TraefikV1alpha1() traefikv1alpha1.TraefikV1alpha1Interface {
 	return c.traefikV1alpha1
 }
 
+// TraefikIOV1alpha1 retrieves the TraefikIOV1alpha1Client
+func (c *Clientset) TraefikIOV1alpha1() traefikiov1alpha1.TraefikIOV1alpha1Interface {
+	return c.traefikIOV1alpha1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -221,6 +253,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.consulV1alpha1, err = consulv1alpha1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 	cs.flaggerV1beta1, err = flaggerv1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
 		return nil, err
@@ -237,6 +273,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.gatewayapiV1, err = gatewayapiv1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 	cs.glooV1, err = gloov1.NewForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
 		return nil, err
@@ -249,6 +289,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.servingV1, err = servingv1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 	cs.kumaV1alpha1, err = kumav1alpha1.NewForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
 		return nil, err
@@ -273,6 +317,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.traefikIOV1alpha1, err = traefikiov1alpha1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -297,19 +345,23 @@ func New(c rest.Interface) *Clientset {
 	cs.apisixV2 = apisixv2.New(c)
 	cs.appmeshV1beta2 = appmeshv1beta2.New(c)
 	cs.appmeshV1beta1 = appmeshv1beta1.New(c)
+	cs.consulV1alpha1 = consulv1alpha1.New(c)
 	cs.flaggerV1beta1 = flaggerv1beta1.New(c)
 	cs.gatewayV1 = gatewayv1.New(c)
 	cs.gatewayapiV1alpha2 = gatewayapiv1alpha2.New(c)
 	cs.gatewayapiV1beta1 = gatewayapiv1beta1.New(c)
+	cs.gatewayapiV1 = gatewayapiv1.New(c)
 	cs.glooV1 = gloov1.New(c)
 	cs.networkingV1alpha3 = networkingv1alpha3.New(c)
 	cs.kedaV1alpha1 = kedav1alpha1.New(c)
+	cs.servingV1 = servingv1.New(c)
 	cs.kumaV1alpha1 = kumav1alpha1.New(c)
 	cs.projectcontourV1 = projectcontourv1.New(c)
 	cs.splitV1alpha1 = splitv1alpha1.New(c)
 	cs.splitV1alpha2 = splitv1alpha2.New(c)
 	cs.splitV1alpha3 = splitv1alpha3.New(c)
 	cs.traefikV1alpha1 = traefikv1alpha1.New(c)
+	cs.traefikIOV1alpha1 = traefikiov1alpha1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
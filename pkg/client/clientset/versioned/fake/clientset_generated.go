@@ -26,10 +26,14 @@ import (
 	fakeappmeshv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/appmesh/v1beta1/fake"
 	appmeshv1beta2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/appmesh/v1beta2"
 	fakeappmeshv1beta2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/appmesh/v1beta2/fake"
+	consulv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/consul/v1alpha1"
+	fakeconsulv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/consul/v1alpha1/fake"
 	flaggerv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/flagger/v1beta1"
 	fakeflaggerv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/flagger/v1beta1/fake"
 	gatewayv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gateway/v1"
 	fakegatewayv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gateway/v1/fake"
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1"
+	fakegatewayapiv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1/fake"
 	gatewayapiv1alpha2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1alpha2"
 	fakegatewayapiv1alpha2 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1alpha2/fake"
 	gatewayapiv1beta1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/gatewayapi/v1beta1"
@@ -40,6 +44,8 @@ import (
 	fakenetworkingv1alpha3 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/istio/v1alpha3/fake"
 	kedav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/keda/v1alpha1"
 	fakekedav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/keda/v1alpha1/fake"
+	servingv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/knative/v1"
+	fakeservingv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/knative/v1/fake"
 	kumav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/kuma/v1alpha1"
 	fakekumav1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/kuma/v1alpha1/fake"
 	projectcontourv1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/projectcontour/v1"
@@ -52,6 +58,8 @@ import (
 	fakesplitv1alpha3 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/smi/v1alpha3/fake"
 	traefikv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefik/v1alpha1"
 	faketraefikv1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefik/v1alpha1/fake"
+	traefikiov1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefikio/v1alpha1"
+	faketraefikiov1alpha1 "github.com/fluxcd/flagger/pkg/client/clientset/versioned/typed/traefikio/v1alpha1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -124,6 +132,11 @@ func (c *Clientset) AppmeshV1beta1() appmeshv1beta1.AppmeshV1beta1Interface {
 	return &fakeappmeshv1beta1.FakeAppmeshV1beta1{Fake: &c.Fake}
 }
 
+// ConsulV1alpha1 retrieves the ConsulV1alpha1Client
+func (c *Clientset) ConsulV1alpha1() consulv1alpha1.ConsulV1alpha1Interface {
+	return &fakeconsulv1alpha1.FakeConsulV1alpha1{Fake: &c.Fake}
+}
+
 // FlaggerV1beta1 retrieves the FlaggerV1beta1Client
 func (c *Clientset) FlaggerV1beta1() flaggerv1beta1.FlaggerV1beta1Interface {
 	return &fakeflaggerv1beta1.FakeFlaggerV1beta1{Fake: &c.Fake}
@@ -144,6 +157,11 @@ func (c *Clientset) GatewayapiV1beta1() gatewayapiv1beta1.GatewayapiV1beta1Inter
 	return &fakegatewayapiv1beta1.FakeGatewayapiV1beta1{Fake: &c.Fake}
 }
 
+// GatewayapiV1 retrieves the GatewayapiV1Client
+func (c *Clientset) GatewayapiV1() gatewayapiv1.GatewayapiV1Interface {
+	return &fakegatewayapiv1.FakeGatewayapiV1{Fake: &c.Fake}
+}
+
 // GlooV1 retrieves the GlooV1Client
 func (c *Clientset) GlooV1() gloov1.GlooV1Interface {
 	return &fakegloov1.FakeGlooV1{Fake: &c.Fake}
@@ -159,6 +177,11 @@ func (c *Clientset) KedaV1alpha1() kedav1alpha1.KedaV1alpha1Interface {
 	return &fakekedav1alpha1.FakeKedaV1alpha1{Fake: &c.Fake}
 }
 
+// ServingV1 retrieves the ServingV1Client
+func (c *Clientset) ServingV1() servingv1.ServingV1Interface {
+	return &fakeservingv1.FakeServingV1{Fake: &c.Fake}
+}
+
 // KumaV1alpha1 retrieves the KumaV1alpha1Client
 func (c *Clientset) KumaV1alpha1() kumav1alpha1.KumaV1alpha1Interface {
 	return &fakekumav1alpha1.FakeKumaV1alpha1{Fake: &c.Fake}
@@ -188,3 +211,8 @@ func (c *Clientset) SplitV1alpha3() splitv1alpha3.SplitV1alpha3Interface {
 func (c *Clientset) TraefikV1alpha1() traefikv1alpha1.TraefikV1alpha1Interface {
 	return &faketraefikv1alpha1.FakeTraefikV1alpha1{Fake: &c.Fake}
 }
+
+// TraefikIOV1alpha1 retrieves the TraefikIOV1alpha1Client
+func (c *Clientset) TraefikIOV1alpha1() traefikiov1alpha1.TraefikIOV1alpha1Interface {
+	return &faketraefikiov1alpha1.FakeTraefikIOV1alpha1{Fake: &c.Fake}
+}
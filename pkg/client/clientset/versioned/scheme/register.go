@@ -22,19 +22,23 @@ import (
 	apisixv2 "github.com/fluxcd/flagger/pkg/apis/apisix/v2"
 	appmeshv1beta1 "github.com/fluxcd/flagger/pkg/apis/appmesh/v1beta1"
 	appmeshv1beta2 "github.com/fluxcd/flagger/pkg/apis/appmesh/v1beta2"
+	consulv1alpha1 "github.com/fluxcd/flagger/pkg/apis/consul/v1alpha1"
 	flaggerv1beta1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1"
 	gatewayapiv1alpha2 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1alpha2"
 	gatewayapiv1beta1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
 	gatewayv1 "github.com/fluxcd/flagger/pkg/apis/gloo/gateway/v1"
 	gloov1 "github.com/fluxcd/flagger/pkg/apis/gloo/gloo/v1"
 	networkingv1alpha3 "github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
 	kedav1alpha1 "github.com/fluxcd/flagger/pkg/apis/keda/v1alpha1"
+	servingv1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
 	kumav1alpha1 "github.com/fluxcd/flagger/pkg/apis/kuma/v1alpha1"
 	projectcontourv1 "github.com/fluxcd/flagger/pkg/apis/projectcontour/v1"
 	splitv1alpha1 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha1"
 	splitv1alpha2 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha2"
 	splitv1alpha3 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha3"
 	traefikv1alpha1 "github.com/fluxcd/flagger/pkg/apis/traefik/v1alpha1"
+	traefikiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/traefikio/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -49,19 +53,23 @@ var localSchemeBuilder = runtime.SchemeBuilder{
 	apisixv2.AddToScheme,
 	appmeshv1beta2.AddToScheme,
 	appmeshv1beta1.AddToScheme,
+	consulv1alpha1.AddToScheme,
 	flaggerv1beta1.AddToScheme,
 	gatewayv1.AddToScheme,
 	gatewayapiv1alpha2.AddToScheme,
 	gatewayapiv1beta1.AddToScheme,
+	gatewayapiv1.AddToScheme,
 	gloov1.AddToScheme,
 	networkingv1alpha3.AddToScheme,
 	kedav1alpha1.AddToScheme,
+	servingv1.AddToScheme,
 	kumav1alpha1.AddToScheme,
 	projectcontourv1.AddToScheme,
 	splitv1alpha1.AddToScheme,
 	splitv1alpha2.AddToScheme,
 	splitv1alpha3.AddToScheme,
 	traefikv1alpha1.AddToScheme,
+	traefikiov1alpha1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
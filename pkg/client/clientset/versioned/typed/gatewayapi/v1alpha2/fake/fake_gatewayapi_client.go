@@ -32,6 +32,14 @@ func (c *FakeGatewayapiV1alpha2) HTTPRoutes(namespace string) v1alpha2.HTTPRoute
 	return &FakeHTTPRoutes{c, namespace}
 }
 
+func (c *FakeGatewayapiV1alpha2) TCPRoutes(namespace string) v1alpha2.TCPRouteInterface {
+	return &FakeTCPRoutes{c, namespace}
+}
+
+func (c *FakeGatewayapiV1alpha2) TLSRoutes(namespace string) v1alpha2.TLSRouteInterface {
+	return &FakeTLSRoutes{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeGatewayapiV1alpha2) RESTClient() rest.Interface {
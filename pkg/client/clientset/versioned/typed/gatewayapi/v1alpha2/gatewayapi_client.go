@@ -29,6 +29,8 @@ import (
 type GatewayapiV1alpha2Interface interface {
 	RESTClient() rest.Interface
 	HTTPRoutesGetter
+	TCPRoutesGetter
+	TLSRoutesGetter
 }
 
 // GatewayapiV1alpha2Client is used to interact with features provided by the gatewayapi group.
@@ -40,6 +42,14 @@ func (c *GatewayapiV1alpha2Client) HTTPRoutes(namespace string) HTTPRouteInterfa
 	return newHTTPRoutes(c, namespace)
 }
 
+func (c *GatewayapiV1alpha2Client) TCPRoutes(namespace string) TCPRouteInterface {
+	return newTCPRoutes(c, namespace)
+}
+
+func (c *GatewayapiV1alpha2Client) TLSRoutes(namespace string) TLSRouteInterface {
+	return newTLSRoutes(c, namespace)
+}
+
 // NewForConfig creates a new GatewayapiV1alpha2Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
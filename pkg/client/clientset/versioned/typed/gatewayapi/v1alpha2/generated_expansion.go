@@ -19,3 +19,7 @@ limitations under the License.
 package v1alpha2
 
 type HTTPRouteExpansion interface{}
+
+type TCPRouteExpansion interface{}
+
+type TLSRouteExpansion interface{}
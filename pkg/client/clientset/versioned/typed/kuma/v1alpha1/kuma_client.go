@@ -28,6 +28,7 @@ import (
 
 type KumaV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	MeshHTTPRoutesGetter
 	TrafficRoutesGetter
 }
 
@@ -36,6 +37,10 @@ type KumaV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KumaV1alpha1Client) MeshHTTPRoutes(namespace string) MeshHTTPRouteInterface {
+	return newMeshHTTPRoutes(c, namespace)
+}
+
 func (c *KumaV1alpha1Client) TrafficRoutes() TrafficRouteInterface {
 	return newTrafficRoutes(c)
 }
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	internalinterfaces "github.com/fluxcd/flagger/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// ServiceResolvers returns a ServiceResolverInformer.
+	ServiceResolvers() ServiceResolverInformer
+	// ServiceRouters returns a ServiceRouterInformer.
+	ServiceRouters() ServiceRouterInformer
+	// ServiceSplitters returns a ServiceSplitterInformer.
+	ServiceSplitters() ServiceSplitterInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ServiceResolvers returns a ServiceResolverInformer.
+func (v *version) ServiceResolvers() ServiceResolverInformer {
+	return &serviceResolverInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ServiceRouters returns a ServiceRouterInformer.
+func (v *version) ServiceRouters() ServiceRouterInformer {
+	return &serviceRouterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ServiceSplitters returns a ServiceSplitterInformer.
+func (v *version) ServiceSplitters() ServiceSplitterInformer {
+	return &serviceSplitterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
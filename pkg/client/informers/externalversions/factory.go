@@ -26,6 +26,7 @@ import (
 	versioned "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
 	apisix "github.com/fluxcd/flagger/pkg/client/informers/externalversions/apisix"
 	appmesh "github.com/fluxcd/flagger/pkg/client/informers/externalversions/appmesh"
+	consul "github.com/fluxcd/flagger/pkg/client/informers/externalversions/consul"
 	flagger "github.com/fluxcd/flagger/pkg/client/informers/externalversions/flagger"
 	gateway "github.com/fluxcd/flagger/pkg/client/informers/externalversions/gateway"
 	gatewayapi "github.com/fluxcd/flagger/pkg/client/informers/externalversions/gatewayapi"
@@ -33,10 +34,12 @@ import (
 	internalinterfaces "github.com/fluxcd/flagger/pkg/client/informers/externalversions/internalinterfaces"
 	istio "github.com/fluxcd/flagger/pkg/client/informers/externalversions/istio"
 	keda "github.com/fluxcd/flagger/pkg/client/informers/externalversions/keda"
+	knative "github.com/fluxcd/flagger/pkg/client/informers/externalversions/knative"
 	kuma "github.com/fluxcd/flagger/pkg/client/informers/externalversions/kuma"
 	projectcontour "github.com/fluxcd/flagger/pkg/client/informers/externalversions/projectcontour"
 	smi "github.com/fluxcd/flagger/pkg/client/informers/externalversions/smi"
 	traefik "github.com/fluxcd/flagger/pkg/client/informers/externalversions/traefik"
+	traefikio "github.com/fluxcd/flagger/pkg/client/informers/externalversions/traefikio"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -185,16 +188,19 @@ type SharedInformerFactory interface {
 
 	Apisix() apisix.Interface
 	Appmesh() appmesh.Interface
+	Consul() consul.Interface
 	Flagger() flagger.Interface
 	Gateway() gateway.Interface
 	Gatewayapi() gatewayapi.Interface
 	Gloo() gloo.Interface
 	Networking() istio.Interface
 	Keda() keda.Interface
+	Serving() knative.Interface
 	Kuma() kuma.Interface
 	Projectcontour() projectcontour.Interface
 	Split() smi.Interface
 	Traefik() traefik.Interface
+	TraefikIO() traefikio.Interface
 }
 
 func (f *sharedInformerFactory) Apisix() apisix.Interface {
@@ -205,6 +211,10 @@ func (f *sharedInformerFactory) Appmesh() appmesh.Interface {
 	return appmesh.New(f, f.namespace, f.tweakListOptions)
 }
 
+func (f *sharedInformerFactory) Consul() consul.Interface {
+	return consul.New(f, f.namespace, f.tweakListOptions)
+}
+
 func (f *sharedInformerFactory) Flagger() flagger.Interface {
 	return flagger.New(f, f.namespace, f.tweakListOptions)
 }
@@ -229,6 +239,10 @@ func (f *sharedInformerFactory) Keda() keda.Interface {
 	return keda.New(f, f.namespace, f.tweakListOptions)
 }
 
+func (f *sharedInformerFactory) Serving() knative.Interface {
+	return knative.New(f, f.namespace, f.tweakListOptions)
+}
+
 func (f *sharedInformerFactory) Kuma() kuma.Interface {
 	return kuma.New(f, f.namespace, f.tweakListOptions)
 }
@@ -244,3 +258,7 @@ func (f *sharedInformerFactory) Split() smi.Interface {
 func (f *sharedInformerFactory) Traefik() traefik.Interface {
 	return traefik.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) TraefikIO() traefikio.Interface {
+	return traefikio.New(f, f.namespace, f.tweakListOptions)
+}
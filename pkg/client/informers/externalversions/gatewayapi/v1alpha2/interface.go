@@ -26,6 +26,10 @@ import (
 type Interface interface {
 	// HTTPRoutes returns a HTTPRouteInformer.
 	HTTPRoutes() HTTPRouteInformer
+	// TCPRoutes returns a TCPRouteInformer.
+	TCPRoutes() TCPRouteInformer
+	// TLSRoutes returns a TLSRouteInformer.
+	TLSRoutes() TLSRouteInformer
 }
 
 type version struct {
@@ -43,3 +47,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) HTTPRoutes() HTTPRouteInformer {
 	return &hTTPRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TCPRoutes returns a TCPRouteInformer.
+func (v *version) TCPRoutes() TCPRouteInformer {
+	return &tCPRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TLSRoutes returns a TLSRouteInformer.
+func (v *version) TLSRoutes() TLSRouteInformer {
+	return &tLSRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -24,19 +24,23 @@ import (
 	v2 "github.com/fluxcd/flagger/pkg/apis/apisix/v2"
 	v1beta1 "github.com/fluxcd/flagger/pkg/apis/appmesh/v1beta1"
 	v1beta2 "github.com/fluxcd/flagger/pkg/apis/appmesh/v1beta2"
+	v1alpha1 "github.com/fluxcd/flagger/pkg/apis/consul/v1alpha1"
 	flaggerv1beta1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1"
 	v1alpha2 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1alpha2"
 	gatewayapiv1beta1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
 	v1 "github.com/fluxcd/flagger/pkg/apis/gloo/gateway/v1"
 	gloov1 "github.com/fluxcd/flagger/pkg/apis/gloo/gloo/v1"
 	v1alpha3 "github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
-	v1alpha1 "github.com/fluxcd/flagger/pkg/apis/keda/v1alpha1"
+	kedav1alpha1 "github.com/fluxcd/flagger/pkg/apis/keda/v1alpha1"
+	knativev1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
 	kumav1alpha1 "github.com/fluxcd/flagger/pkg/apis/kuma/v1alpha1"
 	projectcontourv1 "github.com/fluxcd/flagger/pkg/apis/projectcontour/v1"
 	smiv1alpha1 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha1"
 	smiv1alpha2 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha2"
 	smiv1alpha3 "github.com/fluxcd/flagger/pkg/apis/smi/v1alpha3"
 	traefikv1alpha1 "github.com/fluxcd/flagger/pkg/apis/traefik/v1alpha1"
+	traefikiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/traefikio/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -87,6 +91,14 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1beta2.SchemeGroupVersion.WithResource("virtualservices"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Appmesh().V1beta2().VirtualServices().Informer()}, nil
 
+		// Group=consul.hashicorp.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("serviceresolvers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Consul().V1alpha1().ServiceResolvers().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("servicerouters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Consul().V1alpha1().ServiceRouters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("servicesplitters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Consul().V1alpha1().ServiceSplitters().Informer()}, nil
+
 		// Group=flagger.app, Version=v1beta1
 	case flaggerv1beta1.SchemeGroupVersion.WithResource("alertproviders"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Flagger().V1beta1().AlertProviders().Informer()}, nil
@@ -99,9 +111,19 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1.SchemeGroupVersion.WithResource("routetables"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1().RouteTables().Informer()}, nil
 
+		// Group=gatewayapi, Version=v1
+	case gatewayapiv1.SchemeGroupVersion.WithResource("grpcroutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gatewayapi().V1().GRPCRoutes().Informer()}, nil
+	case gatewayapiv1.SchemeGroupVersion.WithResource("httproutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gatewayapi().V1().HTTPRoutes().Informer()}, nil
+
 		// Group=gatewayapi, Version=v1alpha2
 	case v1alpha2.SchemeGroupVersion.WithResource("httproutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Gatewayapi().V1alpha2().HTTPRoutes().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("tcproutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gatewayapi().V1alpha2().TCPRoutes().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("tlsroutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gatewayapi().V1alpha2().TLSRoutes().Informer()}, nil
 
 		// Group=gatewayapi, Version=v1beta1
 	case gatewayapiv1beta1.SchemeGroupVersion.WithResource("httproutes"):
@@ -112,10 +134,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Gloo().V1().Upstreams().Informer()}, nil
 
 		// Group=keda.sh, Version=v1alpha1
-	case v1alpha1.SchemeGroupVersion.WithResource("scaledobjects"):
+	case kedav1alpha1.SchemeGroupVersion.WithResource("scaledobjects"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Keda().V1alpha1().ScaledObjects().Informer()}, nil
 
 		// Group=kuma.io, Version=v1alpha1
+	case kumav1alpha1.SchemeGroupVersion.WithResource("meshhttproutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kuma().V1alpha1().MeshHTTPRoutes().Informer()}, nil
 	case kumav1alpha1.SchemeGroupVersion.WithResource("trafficroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kuma().V1alpha1().TrafficRoutes().Informer()}, nil
 
@@ -129,6 +153,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case projectcontourv1.SchemeGroupVersion.WithResource("httpproxies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Projectcontour().V1().HTTPProxies().Informer()}, nil
 
+		// Group=serving.knative.dev, Version=v1
+	case knativev1.SchemeGroupVersion.WithResource("revisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Serving().V1().Revisions().Informer()}, nil
+	case knativev1.SchemeGroupVersion.WithResource("services"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Serving().V1().Services().Informer()}, nil
+
 		// Group=split.smi-spec.io, Version=v1alpha1
 	case smiv1alpha1.SchemeGroupVersion.WithResource("trafficsplits"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Split().V1alpha1().TrafficSplits().Informer()}, nil
@@ -145,6 +175,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case traefikv1alpha1.SchemeGroupVersion.WithResource("traefikservices"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Traefik().V1alpha1().TraefikServices().Informer()}, nil
 
+		// Group=traefik.io, Version=v1alpha1
+	case traefikiov1alpha1.SchemeGroupVersion.WithResource("traefikservices"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.TraefikIO().V1alpha1().TraefikServices().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
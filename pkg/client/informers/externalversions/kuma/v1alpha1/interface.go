@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// MeshHTTPRoutes returns a MeshHTTPRouteInformer.
+	MeshHTTPRoutes() MeshHTTPRouteInformer
 	// TrafficRoutes returns a TrafficRouteInformer.
 	TrafficRoutes() TrafficRouteInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// MeshHTTPRoutes returns a MeshHTTPRouteInformer.
+func (v *version) MeshHTTPRoutes() MeshHTTPRouteInformer {
+	return &meshHTTPRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TrafficRoutes returns a TrafficRouteInformer.
 func (v *version) TrafficRoutes() TrafficRouteInformer {
 	return &trafficRouteInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
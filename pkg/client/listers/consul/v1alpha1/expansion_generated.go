@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// ServiceResolverListerExpansion allows custom methods to be added to
+// ServiceResolverLister.
+type ServiceResolverListerExpansion interface{}
+
+// ServiceResolverNamespaceListerExpansion allows custom methods to be added to
+// ServiceResolverNamespaceLister.
+type ServiceResolverNamespaceListerExpansion interface{}
+
+// ServiceRouterListerExpansion allows custom methods to be added to
+// ServiceRouterLister.
+type ServiceRouterListerExpansion interface{}
+
+// ServiceRouterNamespaceListerExpansion allows custom methods to be added to
+// ServiceRouterNamespaceLister.
+type ServiceRouterNamespaceListerExpansion interface{}
+
+// ServiceSplitterListerExpansion allows custom methods to be added to
+// ServiceSplitterLister.
+type ServiceSplitterListerExpansion interface{}
+
+// ServiceSplitterNamespaceListerExpansion allows custom methods to be added to
+// ServiceSplitterNamespaceLister.
+type ServiceSplitterNamespaceListerExpansion interface{}
@@ -25,3 +25,19 @@ type HTTPRouteListerExpansion interface{}
 // HTTPRouteNamespaceListerExpansion allows custom methods to be added to
 // HTTPRouteNamespaceLister.
 type HTTPRouteNamespaceListerExpansion interface{}
+
+// TCPRouteListerExpansion allows custom methods to be added to
+// TCPRouteLister.
+type TCPRouteListerExpansion interface{}
+
+// TCPRouteNamespaceListerExpansion allows custom methods to be added to
+// TCPRouteNamespaceLister.
+type TCPRouteNamespaceListerExpansion interface{}
+
+// TLSRouteListerExpansion allows custom methods to be added to
+// TLSRouteLister.
+type TLSRouteListerExpansion interface{}
+
+// TLSRouteNamespaceListerExpansion allows custom methods to be added to
+// TLSRouteNamespaceLister.
+type TLSRouteNamespaceListerExpansion interface{}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 	"github.com/fluxcd/flagger/pkg/canary"
@@ -50,6 +51,7 @@ const controllerAgentName = "flagger"
 // Controller is managing the canary objects and schedules canary deployments
 type Controller struct {
 	kubeClient           kubernetes.Interface
+	kubeMetricsClient    metricsclientset.Interface
 	flaggerClient        clientset.Interface
 	flaggerInformers     Informers
 	flaggerSynced        cache.InformerSynced
@@ -68,6 +70,7 @@ type Controller struct {
 	eventWebhook         string
 	clusterName          string
 	noCrossNamespaceRefs bool
+	queue                rolloutQueue
 }
 
 type Informers struct {
@@ -78,6 +81,7 @@ type Informers struct {
 
 func NewController(
 	kubeClient kubernetes.Interface,
+	kubeMetricsClient metricsclientset.Interface,
 	flaggerClient clientset.Interface,
 	flaggerInformers Informers,
 	flaggerWindow time.Duration,
@@ -91,6 +95,8 @@ func NewController(
 	eventWebhook string,
 	clusterName string,
 	noCrossNamespaceRefs bool,
+	maxConcurrentCanaries int,
+	maxConcurrentCanariesPerNamespace int,
 ) *Controller {
 	logger.Debug("Creating event broadcaster")
 	flaggerscheme.AddToScheme(scheme.Scheme)
@@ -106,6 +112,7 @@ func NewController(
 
 	ctrl := &Controller{
 		kubeClient:           kubeClient,
+		kubeMetricsClient:    kubeMetricsClient,
 		flaggerClient:        flaggerClient,
 		flaggerInformers:     flaggerInformers,
 		flaggerSynced:        flaggerInformers.CanaryInformer.Informer().HasSynced,
@@ -124,6 +131,10 @@ func NewController(
 		eventWebhook:         eventWebhook,
 		clusterName:          clusterName,
 		noCrossNamespaceRefs: noCrossNamespaceRefs,
+		queue: rolloutQueue{
+			maxConcurrent:             maxConcurrentCanaries,
+			maxConcurrentPerNamespace: maxConcurrentCanariesPerNamespace,
+		},
 	}
 
 	flaggerInformers.CanaryInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -332,6 +343,11 @@ func verifyNoCrossNamespaceRefs(canary *flaggerv1.Canary) error {
 	if canary.Spec.UpstreamRef != nil && canary.Spec.UpstreamRef.Namespace != canary.Namespace {
 		return fmt.Errorf("can't access gloo upstream %s.%s, cross-namespace references are blocked", canary.Spec.UpstreamRef.Name, canary.Spec.UpstreamRef.Namespace)
 	}
+	for _, dependency := range canary.Spec.DependsOn {
+		if dependency.Namespace != "" && dependency.Namespace != canary.Namespace {
+			return fmt.Errorf("can't access canary %s.%s, cross-namespace references are blocked", dependency.Name, dependency.Namespace)
+		}
+	}
 	if canary.Spec.Analysis != nil {
 		for _, metric := range canary.Spec.Analysis.Metrics {
 			if metric.TemplateRef != nil && metric.TemplateRef.Namespace != canary.Namespace {
@@ -90,6 +90,24 @@ func TestController_verifyCanary(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Canary dependency in a different namespace should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					DependsOn: []flaggerv1.CanaryDependency{
+						{
+							Name:      "cd-2",
+							Namespace: "test",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	ctrl := &Controller{
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -40,7 +41,7 @@ func (c *Controller) finalize(old interface{}) error {
 	}
 
 	// Retrieve a controller
-	canaryController := c.canaryFactory.Controller(canary.Spec.TargetRef.Kind)
+	canaryController := c.canaryFactory.Controller(canary.Spec.TargetRef)
 
 	// Set the status to terminating if not already in that state
 	if canary.Status.Phase != flaggerv1.CanaryPhaseTerminating {
@@ -52,6 +53,19 @@ func (c *Controller) finalize(old interface{}) error {
 		c.recordEventInfof(canary, "Terminating canary %s.%s", canary.Name, canary.Namespace)
 	}
 
+	// Restore the canary replicas and autoscaler bounds set by the replica sizing
+	if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+		c.logger.Warnf("%s.%s failed to restore replicas: %v", canary.Name, canary.Namespace, err)
+	}
+	if canary.Spec.AutoscalerRef != nil {
+		if scalerReconciler := c.canaryFactory.ScalerReconciler(canary.Spec.AutoscalerRef.Kind); scalerReconciler != nil {
+			// the autoscaler may have been removed along with the canary
+			if err := scalerReconciler.RestoreTargetScaler(canary); err != nil {
+				c.logger.Warnf("%s.%s failed to restore autoscaler: %v", canary.Name, canary.Namespace, err)
+			}
+		}
+	}
+
 	// Revert the Kubernetes deployment or daemonset
 	err = canaryController.Finalize(canary)
 	if err != nil {
@@ -78,6 +92,13 @@ func (c *Controller) finalize(old interface{}) error {
 	}
 	c.logger.Infof("%s.%s router reverted", canary.Name, canary.Namespace)
 
+	// Remove the primary PDBs and VPAs
+	for _, companionReconciler := range c.canaryFactory.CompanionReconcilers(canary.Spec.TargetRef, labelSelector, labelValue) {
+		if err := companionReconciler.FinalizePrimaryCompanion(canary); err != nil {
+			return fmt.Errorf("failed to remove primary companion: %w", err)
+		}
+	}
+
 	// Revert the mesh objects
 	if err := c.revertMesh(canary); err != nil {
 		return fmt.Errorf("failed to revert mesh: %w", err)
@@ -90,17 +111,14 @@ func (c *Controller) finalize(old interface{}) error {
 // revertMesh reverts defined mesh provider based upon the implementation's respective Finalize method.
 // If the Finalize method encounters and error that is returned, else revert is considered successful.
 func (c *Controller) revertMesh(r *flaggerv1.Canary) error {
-	provider := c.meshProvider
-	if r.Spec.Provider != "" {
-		provider = r.Spec.Provider
-	}
+	providers := r.GetProviders(c.meshProvider)
 
-	meshRouter := c.routerFactory.MeshRouter(provider, "")
+	meshRouter := c.routerFactory.CanaryRouters(r, providers, "")
 	if err := meshRouter.Finalize(r); err != nil {
 		return fmt.Errorf("meshRouter.Finlize failed: %w", err)
 	}
 
-	c.logger.Infof("%s.%s mesh provider %s reverted", r.Name, r.Namespace, provider)
+	c.logger.Infof("%s.%s mesh provider %s reverted", r.Name, r.Namespace, strings.Join(providers, ","))
 	return nil
 }
 
@@ -164,14 +164,12 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
-	// override the global provider if one is specified in the canary spec
-	provider := c.meshProvider
-	if cd.Spec.Provider != "" {
-		provider = cd.Spec.Provider
-	}
+	// override the global provider if one or more are specified in the canary spec
+	providers := cd.GetProviders(c.meshProvider)
+	provider := providers[0]
 
 	// init controller based on target kind
-	canaryController := c.canaryFactory.Controller(cd.Spec.TargetRef.Kind)
+	canaryController := c.canaryFactory.Controller(cd.Spec.TargetRef)
 	labelSelector, labelValue, ports, err := canaryController.GetMetadata(cd)
 	if err != nil {
 		c.recordEventWarningf(cd, "%v", err)
@@ -182,6 +180,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	if cd.Spec.AutoscalerRef != nil {
 		scalerReconciler = c.canaryFactory.ScalerReconciler(cd.Spec.AutoscalerRef.Kind)
 	}
+	companionReconcilers := c.canaryFactory.CompanionReconcilers(cd.Spec.TargetRef, labelSelector, labelValue)
 
 	// init Kubernetes router
 	kubeRouter := c.routerFactory.KubernetesRouter(cd.Spec.TargetRef.Kind, labelSelector, labelValue, ports)
@@ -200,11 +199,17 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	// init mesh router
-	meshRouter := c.routerFactory.MeshRouter(provider, labelSelector)
+	meshRouter := c.routerFactory.CanaryRouters(cd, providers, labelSelector)
 
 	// register the AppMesh VirtualNodes before creating the primary deployment
 	// otherwise the pods will not be injected with the Envoy proxy
-	if strings.HasPrefix(provider, flaggerv1.AppMeshProvider) {
+	appMesh := false
+	for _, p := range providers {
+		if strings.HasPrefix(p, flaggerv1.AppMeshProvider) {
+			appMesh = true
+		}
+	}
+	if appMesh {
 		if err := meshRouter.Reconcile(cd); err != nil {
 			c.recordEventWarningf(cd, "%v", err)
 			return
@@ -233,6 +238,13 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		}
 	}
 
+	for _, companionReconciler := range companionReconcilers {
+		if err := companionReconciler.ReconcilePrimaryCompanion(cd, true); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+	}
+
 	// change the apex service pod selector to primary
 	if err := kubeRouter.Reconcile(cd); err != nil {
 		c.recordEventWarningf(cd, "%v", err)
@@ -241,7 +253,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 
 	// take over an existing virtual service or ingress
 	// runs after the primary is ready to ensure zero downtime
-	if !strings.HasPrefix(provider, flaggerv1.AppMeshProvider) {
+	if !appMesh {
 		if err := meshRouter.Reconcile(cd); err != nil {
 			c.recordEventWarningf(cd, "%v", err)
 			return
@@ -260,6 +272,11 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// check dependencies
+	if ok := c.runDependencyChecks(cd, canaryController); !ok {
+		return
+	}
+
 	// check gates
 	if isApproved := c.runConfirmRolloutHooks(cd, canaryController); !isApproved {
 		return
@@ -311,6 +328,10 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		}
 		if err := canaryController.SyncStatus(cd, status); err != nil {
 			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		if err := c.startPodHealthChecks(cd); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
 		}
 		return
 	}
@@ -324,7 +345,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	// check if analysis should be skipped
-	if skip := c.shouldSkipAnalysis(cd, canaryController, meshRouter, scalerReconciler, err, retriable); skip {
+	if skip := c.shouldSkipAnalysis(cd, canaryController, meshRouter, scalerReconciler, companionReconcilers, err, retriable); skip {
 		return
 	}
 
@@ -343,7 +364,23 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	// route traffic back to primary if analysis has succeeded
 	if cd.Status.Phase == flaggerv1.CanaryPhasePromoting {
 		if scalerReconciler != nil {
-			if err := scalerReconciler.ReconcilePrimaryScaler(cd, false); err != nil {
+			// the primary scaler is reconciled from the canary scaler bounds set by the user
+			if err := scalerReconciler.RestoreTargetScaler(cd); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return
+			}
+			if cd.Spec.PrimaryPreScaling != "" {
+				// the primary scaler is reconciled once the traffic is routed to primary
+				if ready := c.preScalePrimary(cd, scalerReconciler, canaryWeight, primaryWeight); !ready {
+					return
+				}
+			} else if err := scalerReconciler.ReconcilePrimaryScaler(cd, false); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return
+			}
+		}
+		for _, companionReconciler := range companionReconcilers {
+			if err := companionReconciler.ReconcilePrimaryCompanion(cd, false); err != nil {
 				c.recordEventWarningf(cd, "%v", err)
 				return
 			}
@@ -355,6 +392,13 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	// scale canary to zero if promotion has finished
 	if cd.Status.Phase == flaggerv1.CanaryPhaseFinalising {
 		if scalerReconciler != nil {
+			if cd.Spec.PrimaryPreScaling != "" {
+				// restore the primary scaler min replicas
+				if err := scalerReconciler.ReconcilePrimaryScaler(cd, false); err != nil {
+					c.recordEventWarningf(cd, "%v", err)
+					return
+				}
+			}
 			if err := scalerReconciler.PauseTargetScaler(cd); err != nil {
 				c.recordEventWarningf(cd, "%v", err)
 				return
@@ -378,9 +422,21 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// a member of a release bundle follows the promotion of its peers without further analysis,
+	// the peers are promoted only after every member has passed its last analysis
+	bundlePromoting := false
+	if cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion {
+		peer, err := c.getPromotingBundlePeer(cd)
+		if err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		bundlePromoting = peer != nil
+	}
+
 	// check if the number of failed checks reached the threshold
 	if (cd.Status.Phase == flaggerv1.CanaryPhaseProgressing || cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion) &&
-		(!retriable || cd.Status.FailedChecks >= cd.GetAnalysisThreshold()) {
+		!bundlePromoting && (!retriable || cd.Status.FailedChecks >= cd.GetAnalysisThreshold()) {
 		if !retriable {
 			c.recordEventWarningf(cd, "Rolling back %s.%s progress deadline exceeded %v",
 				cd.Name, cd.Namespace, err)
@@ -391,6 +447,30 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// roll back all the members of a release bundle if one of them failed
+	if (cd.Status.Phase == flaggerv1.CanaryPhaseProgressing || cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion) &&
+		!bundlePromoting {
+		peer, err := c.getFailedBundlePeer(cd)
+		if err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		if peer != nil {
+			c.recordEventWarningf(cd, "Rolling back %s.%s bundle %s member %s.%s failed",
+				cd.Name, cd.Namespace, cd.Spec.Bundle, peer.Name, peer.Namespace)
+			c.alert(cd, fmt.Sprintf("Rolling back bundle %s member %s failed", cd.Spec.Bundle, peer.Name),
+				false, flaggerv1.SeverityError)
+			c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+			return
+		}
+	}
+
+	// check if the routes were accepted by the ingress controller or mesh before shifting traffic
+	if err := meshRouter.IsRouteReady(cd); err != nil {
+		c.recordEventWarningf(cd, "Halt advancement %v", err)
+		return
+	}
+
 	// record analysis duration
 	defer func() {
 		c.recorder.SetDuration(cd, time.Since(begin))
@@ -409,7 +489,20 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 			}
 			return
 		}
-	} else {
+	} else if !bundlePromoting {
+		if ok, rollback := c.runPodHealthChecks(cd); !ok {
+			if rollback {
+				c.recordEventWarningf(cd, "Rolling back %s.%s pod health check failed", cd.Name, cd.Namespace)
+				c.alert(cd, "Rolling back pod health check failed", false, flaggerv1.SeverityError)
+				c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+				return
+			}
+			if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+			}
+			return
+		}
+
 		if ok := c.runAnalysis(cd); !ok {
 			if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
 				c.recordEventWarningf(cd, "%v", err)
@@ -418,8 +511,8 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		}
 	}
 
-	// use blue/green strategy for kubernetes provider
-	if provider == flaggerv1.KubernetesProvider {
+	// use blue/green strategy for kubernetes provider unless the canary is rolled out to a subset of nodes
+	if provider == flaggerv1.KubernetesProvider && !cd.HasNodeRollout() {
 		if len(cd.GetAnalysis().Match) > 0 {
 			c.recordEventWarningf(cd, "A/B testing is not supported when using the kubernetes provider")
 			cd.GetAnalysis().Match = nil
@@ -431,6 +524,16 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		}
 	}
 
+	// advance the members of a release bundle in lockstep
+	if peer, err := c.getLaggingBundlePeer(cd); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	} else if peer != nil {
+		c.recordEventInfof(cd, "Halt %s.%s advancement waiting for bundle %s member %s.%s",
+			cd.Name, cd.Namespace, cd.Spec.Bundle, peer.Name, peer.Namespace)
+		return
+	}
+
 	// strategy: A/B testing
 	if len(cd.GetAnalysis().Match) > 0 && cd.GetAnalysis().Iterations > 0 {
 		c.runAB(cd, canaryController, meshRouter)
@@ -459,7 +562,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 func (c *Controller) runPromotionTrafficShift(canary *flaggerv1.Canary, canaryController canary.Controller,
 	meshRouter router.Interface, provider string, canaryWeight int, primaryWeight int) {
 	// finalize promotion since no traffic shifting is possible for Kubernetes CNI
-	if provider == flaggerv1.KubernetesProvider {
+	if provider == flaggerv1.KubernetesProvider && !canary.HasNodeRollout() {
 		if err := canaryController.SetStatusPhase(canary, flaggerv1.CanaryPhaseFinalising); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 		}
@@ -561,6 +664,13 @@ func (c *Controller) runCanary(canary *flaggerv1.Canary, canaryController canary
 
 		c.recorder.SetWeight(canary, primaryWeight, canaryWeight)
 		c.recordEventInfof(canary, "Advance %s.%s canary weight %v", canary.Name, canary.Namespace, canaryWeight)
+
+		// size the canary for the next step
+		if sizer, ok := c.getReplicaSizer(canary, canaryController); ok {
+			if err := c.scaleCanaryToWeight(canary, sizer, c.upcomingWeight(canary, canaryWeight)); err != nil {
+				c.recordEventWarningf(canary, "%v", err)
+			}
+		}
 		return
 	}
 
@@ -571,6 +681,12 @@ func (c *Controller) runCanary(canary *flaggerv1.Canary, canaryController canary
 			return
 		}
 
+		// restore the canary replicas before they are copied to primary
+		if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return
+		}
+
 		// update primary spec
 		c.recordEventInfof(canary, "Copying %s.%s template spec to %s.%s",
 			canary.Spec.TargetRef.Name, canary.Namespace, primaryName, canary.Namespace)
@@ -713,6 +829,13 @@ func (c *Controller) runAnalysis(canary *flaggerv1.Canary) bool {
 		}
 	}
 
+	// run the metrics of all the release bundle members
+	canary, err := c.withBundleMetrics(canary)
+	if err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return false
+	}
+
 	ok := c.runBuiltinMetricChecks(canary)
 	if !ok {
 		return ok
@@ -726,7 +849,7 @@ func (c *Controller) runAnalysis(canary *flaggerv1.Canary) bool {
 	return true
 }
 
-func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryController canary.Controller, meshRouter router.Interface, scalerReconciler canary.ScalerReconciler, err error, retriable bool) bool {
+func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryController canary.Controller, meshRouter router.Interface, scalerReconciler canary.ScalerReconciler, companionReconcilers []canary.CompanionReconciler, err error, retriable bool) bool {
 	if !canary.SkipAnalysis() {
 		return false
 	}
@@ -749,6 +872,11 @@ func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryControll
 	}
 	c.recorder.SetWeight(canary, primaryWeight, canaryWeight)
 
+	if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return true
+	}
+
 	// copy spec and configs from canary to primary
 	c.recordEventInfof(canary, "Copying %s.%s template spec to %s-primary.%s",
 		canary.Spec.TargetRef.Name, canary.Namespace, canary.Spec.TargetRef.Name, canary.Namespace)
@@ -758,6 +886,10 @@ func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryControll
 	}
 
 	if scalerReconciler != nil {
+		if err := scalerReconciler.RestoreTargetScaler(canary); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return true
+		}
 		if err := scalerReconciler.ReconcilePrimaryScaler(canary, false); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return true
@@ -767,6 +899,12 @@ func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryControll
 			return true
 		}
 	}
+	for _, companionReconciler := range companionReconcilers {
+		if err := companionReconciler.ReconcilePrimaryCompanion(canary, false); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return true
+		}
+	}
 
 	// shutdown canary
 	if err := canaryController.ScaleToZero(canary); err != nil {
@@ -795,6 +933,7 @@ func (c *Controller) shouldAdvance(canary *flaggerv1.Canary, canaryController ca
 		canary.Status.Phase == flaggerv1.CanaryPhaseInitializing ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseProgressing ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseWaiting ||
+		canary.Status.Phase == flaggerv1.CanaryPhaseQueued ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion ||
 		canary.Status.Phase == flaggerv1.CanaryPhasePromoting ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseFinalising {
@@ -855,6 +994,10 @@ func (c *Controller) checkCanaryStatus(canary *flaggerv1.Canary, canaryControlle
 	}
 
 	if shouldAdvance {
+		if admitted := c.admitCanary(canary); !admitted {
+			return false
+		}
+
 		canaryPhaseProgressing := canary.DeepCopy()
 		canaryPhaseProgressing.Status.Phase = flaggerv1.CanaryPhaseProgressing
 		c.recordEventInfof(canaryPhaseProgressing, "New revision detected! Scaling up %s.%s", canaryPhaseProgressing.Spec.TargetRef.Name, canaryPhaseProgressing.Namespace)
@@ -868,7 +1011,7 @@ func (c *Controller) checkCanaryStatus(canary *flaggerv1.Canary, canaryControlle
 				return false
 			}
 		}
-		if err := canaryController.ScaleFromZero(canary); err != nil {
+		if err := c.scaleCanaryFromZero(canary, canaryController); err != nil {
 			c.recordEventErrorf(canary, "%v", err)
 			return false
 		}
@@ -876,6 +1019,9 @@ func (c *Controller) checkCanaryStatus(canary *flaggerv1.Canary, canaryControlle
 			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).Errorf("%v", err)
 			return false
 		}
+		if err := c.startPodHealthChecks(canary); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+		}
 		c.recorder.SetStatus(canary, flaggerv1.CanaryPhaseProgressing)
 		return false
 	}
@@ -919,7 +1065,15 @@ func (c *Controller) rollback(canary *flaggerv1.Canary, canaryController canary.
 
 	c.recorder.SetWeight(canary, primaryWeight, canaryWeight)
 
+	if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return
+	}
 	if scalerReconciler != nil {
+		if err := scalerReconciler.RestoreTargetScaler(canary); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return
+		}
 		if err := scalerReconciler.PauseTargetScaler(canary); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// getBundlePeers returns the other canaries of the release bundle
+func (c *Controller) getBundlePeers(cd *flaggerv1.Canary) ([]*flaggerv1.Canary, error) {
+	if cd.Spec.Bundle == "" {
+		return nil, nil
+	}
+
+	list, err := c.flaggerInformers.CanaryInformer.Lister().Canaries(cd.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("canaries list query error: %w", err)
+	}
+
+	var peers []*flaggerv1.Canary
+	for _, peer := range list {
+		if peer.Spec.Bundle == cd.Spec.Bundle && peer.Name != cd.Name {
+			peers = append(peers, peer)
+		}
+	}
+	return peers, nil
+}
+
+// getFailedBundlePeer returns a canary of the release bundle that failed
+// after the analysis of the given canary has started
+func (c *Controller) getFailedBundlePeer(cd *flaggerv1.Canary) (*flaggerv1.Canary, error) {
+	peers, err := c.getBundlePeers(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := analysisStartTime(cd)
+	for _, peer := range peers {
+		if peer.Status.Phase == flaggerv1.CanaryPhaseFailed &&
+			!peer.Status.LastTransitionTime.Before(&start) {
+			return peer, nil
+		}
+	}
+	return nil, nil
+}
+
+// getLaggingBundlePeer returns a canary of the release bundle that is under analysis
+// and has made less progress than the given canary
+func (c *Controller) getLaggingBundlePeer(cd *flaggerv1.Canary) (*flaggerv1.Canary, error) {
+	peers, err := c.getBundlePeers(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := c.analysisProgress(cd)
+	for _, peer := range peers {
+		switch peer.Status.Phase {
+		case flaggerv1.CanaryPhaseProgressing, flaggerv1.CanaryPhaseWaiting,
+			flaggerv1.CanaryPhaseQueued, flaggerv1.CanaryPhaseWaitingPromotion:
+			if c.analysisProgress(peer) < progress {
+				return peer, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getUnfinishedBundlePeer returns a canary of the release bundle that is under analysis
+// and has not yet passed its last analysis step
+func (c *Controller) getUnfinishedBundlePeer(cd *flaggerv1.Canary) (*flaggerv1.Canary, error) {
+	peers, err := c.getBundlePeers(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, peer := range peers {
+		switch peer.Status.Phase {
+		case flaggerv1.CanaryPhaseProgressing, flaggerv1.CanaryPhaseWaiting, flaggerv1.CanaryPhaseQueued:
+			return peer, nil
+		}
+	}
+	return nil, nil
+}
+
+// getPromotingBundlePeer returns a canary of the release bundle that has been promoted
+// after the analysis of the given canary has started
+func (c *Controller) getPromotingBundlePeer(cd *flaggerv1.Canary) (*flaggerv1.Canary, error) {
+	peers, err := c.getBundlePeers(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := analysisStartTime(cd)
+	for _, peer := range peers {
+		switch peer.Status.Phase {
+		case flaggerv1.CanaryPhasePromoting, flaggerv1.CanaryPhaseFinalising, flaggerv1.CanaryPhaseSucceeded:
+			if !peer.Status.LastTransitionTime.Before(&start) {
+				return peer, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// withBundleMetrics returns a copy of the canary that has the metrics of the release bundle
+// members merged into its analysis, the metrics of the canary take precedence on name clashes
+func (c *Controller) withBundleMetrics(cd *flaggerv1.Canary) (*flaggerv1.Canary, error) {
+	peers, err := c.getBundlePeers(cd)
+	if err != nil || len(peers) == 0 {
+		return cd, err
+	}
+
+	names := make(map[string]bool)
+	for _, metric := range cd.GetAnalysis().Metrics {
+		names[metric.Name] = true
+	}
+
+	union := cd.DeepCopy()
+	for _, peer := range peers {
+		if peer.SkipAnalysis() {
+			continue
+		}
+		for _, metric := range peer.GetAnalysis().Metrics {
+			if names[metric.Name] {
+				continue
+			}
+			names[metric.Name] = true
+			union.GetAnalysis().Metrics = append(union.GetAnalysis().Metrics, metric)
+		}
+	}
+	return union, nil
+}
+
+// analysisStartTime returns the time the analysis of the canary started, the promoted condition
+// is unknown from the start of the analysis until the promotion finished or the canary failed
+func analysisStartTime(cd *flaggerv1.Canary) metav1.Time {
+	for _, condition := range cd.Status.Conditions {
+		if condition.Type == flaggerv1.PromotedType && condition.Status == corev1.ConditionUnknown {
+			return condition.LastTransitionTime
+		}
+	}
+	return cd.Status.LastTransitionTime
+}
+
+// analysisProgress returns the fraction of the analysis steps completed by the canary,
+// the steps are the traffic weight increments or the iterations
+func (c *Controller) analysisProgress(cd *flaggerv1.Canary) float64 {
+	switch cd.Status.Phase {
+	case flaggerv1.CanaryPhaseWaiting, flaggerv1.CanaryPhaseQueued:
+		return 0
+	case flaggerv1.CanaryPhaseWaitingPromotion:
+		return 1
+	}
+
+	iterations := cd.GetAnalysis().Iterations
+	provider := cd.GetProviders(c.meshProvider)[0]
+	if iterations < 1 && provider == flaggerv1.KubernetesProvider && !cd.HasNodeRollout() {
+		// the kubernetes provider defaults to blue/green with ten iterations
+		iterations = 10
+	}
+
+	var progress float64
+	if iterations > 0 {
+		progress = float64(cd.Status.Iterations) / float64(iterations)
+	} else if maxWeight := c.maxWeight(cd); maxWeight > 0 {
+		progress = float64(cd.Status.CanaryWeight) / float64(maxWeight)
+	}
+
+	if progress > 1 {
+		return 1
+	}
+	return progress
+}
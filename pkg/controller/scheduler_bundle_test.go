@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestScheduler_BundleLockstep(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Bundle = "release"
+	mocks := newDeploymentFixture(cd)
+
+	peer := newBundleTestPeer(flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing})
+	mocks.storeCanary(t, peer)
+
+	startBundleTestAnalysis(t, mocks)
+
+	// the analysis starts at the same step as the peer
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 10, c.Status.CanaryWeight)
+
+	// the canary waits for the peer to catch up
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 10, c.Status.CanaryWeight)
+
+	// the peer has a larger step weight and is ahead
+	peer.Status.CanaryWeight = 50
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 20, c.Status.CanaryWeight)
+
+	for i := 0; i < 3; i++ {
+		mocks.ctrl.advanceCanary("podinfo", "default")
+	}
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 50, c.Status.CanaryWeight)
+
+	// the promotion is held until the peer has passed its last analysis
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseWaitingPromotion))
+
+	// the canary is promoted once the peer completed its analysis
+	peer.Status.Phase = flaggerv1.CanaryPhaseWaitingPromotion
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+}
+
+func TestScheduler_BundleFollowPromotion(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Bundle = "release"
+	mocks := newDeploymentFixture(cd)
+
+	peer := newBundleTestPeer(flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing, CanaryWeight: 50})
+	mocks.storeCanary(t, peer)
+
+	startBundleTestAnalysis(t, mocks)
+
+	// advance to max weight and wait for the peer last analysis
+	for i := 0; i < 6; i++ {
+		mocks.ctrl.advanceCanary("podinfo", "default")
+	}
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseWaitingPromotion))
+
+	// the peer has been promoted
+	peer.Status.Phase = flaggerv1.CanaryPhasePromoting
+	peer.Status.LastTransitionTime = metav1.Now()
+	mocks.storeCanary(t, peer)
+
+	// the canary follows the promotion regardless of the failed checks
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Status.FailedChecks = c.GetAnalysisThreshold()
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+}
+
+func TestScheduler_BundleRollback(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Bundle = "release"
+	mocks := newDeploymentFixture(cd)
+
+	// a failure of a previous release is ignored
+	peer := newBundleTestPeer(flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseFailed, LastTransitionTime: metav1.Now()})
+	mocks.storeCanary(t, peer)
+
+	startBundleTestAnalysis(t, mocks)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+
+	// the peer fails during the analysis
+	peer.Status.LastTransitionTime = metav1.Now()
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+}
+
+func TestScheduler_BundleMetrics(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Bundle = "release"
+	mocks := newDeploymentFixture(cd)
+
+	peer := newBundleTestPeer(flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing})
+	peer.Spec.Analysis.Metrics = []flaggerv1.CanaryMetric{
+		{
+			Name:      "request-success-rate",
+			Threshold: 90,
+		},
+		{
+			Name:        "bff-errors",
+			TemplateRef: &flaggerv1.CrossNamespaceObjectReference{Name: "bff-errors"},
+		},
+	}
+	mocks.storeCanary(t, peer)
+
+	union, err := mocks.ctrl.withBundleMetrics(cd)
+	require.NoError(t, err)
+
+	var names []string
+	for _, metric := range union.GetAnalysis().Metrics {
+		names = append(names, metric.Name)
+	}
+	assert.Equal(t, []string{"request-success-rate", "request-duration", "custom", "bff-errors"}, names)
+	assert.Equal(t, float64(99), union.GetAnalysis().Metrics[0].Threshold)
+	assert.Len(t, cd.GetAnalysis().Metrics, 3)
+}
+
+func TestScheduler_BundleConcurrencyLimit(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Bundle = "release"
+	mocks := newDeploymentFixture(cd)
+	mocks.ctrl.queue.maxConcurrent = 1
+
+	peer := newBundleTestPeer(flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseQueued})
+	mocks.storeCanary(t, peer)
+
+	// the first member of the bundle takes the only slot
+	startBundleTestAnalysis(t, mocks)
+
+	// the other members share the slot
+	assert.True(t, mocks.ctrl.admitCanary(peer))
+	peer.Status = flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing, CanaryWeight: 10}
+	peer.ResourceVersion = "2"
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+	assert.Equal(t, 10, c.Status.CanaryWeight)
+
+	// canaries outside of the bundle wait for the slot
+	other := newQueueTestCanary("backend", "default", flaggerv1.CanaryPhaseQueued)
+	mocks.storeCanary(t, other)
+	assert.False(t, mocks.ctrl.admitCanary(other))
+}
+
+// startBundleTestAnalysis initializes the canary and triggers the analysis of a new revision
+func startBundleTestAnalysis(t *testing.T, mocks fixture) {
+	// initializing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// make primary ready
+	mocks.makePrimaryReady(t)
+
+	// initialized
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+
+	// update
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+	mocks.makeCanaryReady(t)
+}
+
+func newBundleTestPeer(status flaggerv1.CanaryStatus) *flaggerv1.Canary {
+	peer := newDeploymentTestCanary()
+	peer.Name = "podinfo-bff"
+	peer.Spec.TargetRef.Name = "podinfo-bff"
+	peer.Spec.Bundle = "release"
+	peer.Status = status
+	return peer
+}
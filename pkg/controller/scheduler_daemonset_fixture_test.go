@@ -103,7 +103,7 @@ func newDaemonSetFixture(c *flaggerv1.Canary) daemonSetFixture {
 		KubeClient:    kubeClient,
 		FlaggerClient: flaggerClient,
 	}
-	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, configTracker, []string{"app", "name"}, []string{""}, logger)
+	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, nil, nil, nil, nil, configTracker, []string{"app", "name"}, []string{""}, logger)
 
 	ctrl := &Controller{
 		kubeClient:       kubeClient,
@@ -130,7 +130,7 @@ func newDaemonSetFixture(c *flaggerv1.Canary) daemonSetFixture {
 
 	return daemonSetFixture{
 		canary:        c,
-		deployer:      canaryFactory.Controller("DaemonSet"),
+		deployer:      canaryFactory.Controller(flaggerv1.LocalObjectReference{Kind: "DaemonSet"}),
 		logger:        logger,
 		flaggerClient: flaggerClient,
 		meshClient:    flaggerClient,
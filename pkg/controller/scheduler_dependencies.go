@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// runDependencyChecks halts the start of the analysis until the canaries
+// listed in dependsOn have been promoted
+func (c *Controller) runDependencyChecks(cd *flaggerv1.Canary, canaryController canary.Controller) bool {
+	if len(cd.Spec.DependsOn) == 0 {
+		return true
+	}
+
+	// dependencies are checked only before the analysis starts
+	switch cd.Status.Phase {
+	case flaggerv1.CanaryPhaseInitialized, flaggerv1.CanaryPhaseSucceeded,
+		flaggerv1.CanaryPhaseFailed, flaggerv1.CanaryPhaseWaiting:
+	default:
+		return true
+	}
+
+	if cycle := c.findDependencyCycle(cd); len(cycle) > 0 {
+		c.setPhaseWaiting(cd, canaryController)
+		c.recordEventWarningf(cd, "Halt %s.%s advancement dependency cycle detected %s",
+			cd.Name, cd.Namespace, strings.Join(cycle, " -> "))
+		return false
+	}
+
+	reason, err := c.checkDependencies(cd)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	if reason == "" {
+		return true
+	}
+
+	if cd.Status.Phase != flaggerv1.CanaryPhaseWaiting {
+		c.setPhaseWaiting(cd, canaryController)
+		c.recordEventInfof(cd, "Halt %s.%s advancement waiting for %s", cd.Name, cd.Namespace, reason)
+		c.alert(cd, fmt.Sprintf("Canary is waiting for %s.", reason), false, flaggerv1.SeverityInfo)
+	}
+	return false
+}
+
+func (c *Controller) setPhaseWaiting(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	if cd.Status.Phase == flaggerv1.CanaryPhaseWaiting {
+		return
+	}
+	if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseWaiting); err != nil {
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+	}
+}
+
+// checkDependencies returns the reason why the analysis can't start,
+// or an empty string if all the dependencies have been promoted
+func (c *Controller) checkDependencies(cd *flaggerv1.Canary) (string, error) {
+	for _, dependency := range cd.Spec.DependsOn {
+		name, namespace := dependency.Name, dependency.Namespace
+		if namespace == "" {
+			namespace = cd.Namespace
+		}
+		if c.noCrossNamespaceRefs && namespace != cd.Namespace {
+			return "", fmt.Errorf("can't access canary %s.%s, cross-namespace references are blocked", name, namespace)
+		}
+
+		dep, err := c.flaggerClient.FlaggerV1beta1().Canaries(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return fmt.Sprintf("dependency %s.%s to be created", name, namespace), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("canary %s.%s get query error: %w", name, namespace, err)
+		}
+
+		if dep.Status.Phase != flaggerv1.CanaryPhaseSucceeded &&
+			dep.Status.Phase != flaggerv1.CanaryPhaseInitialized {
+			return fmt.Sprintf("dependency %s.%s to be promoted", name, namespace), nil
+		}
+
+		// a dependency that hasn't started the analysis of its new revision yet is not promoted
+		changed, err := c.canaryFactory.Controller(dep.Spec.TargetRef).HasTargetChanged(dep)
+		if err != nil {
+			return "", err
+		}
+		if changed {
+			return fmt.Sprintf("dependency %s.%s to be promoted", name, namespace), nil
+		}
+
+		if dependency.RevisionLabel != "" {
+			want := c.getTargetLabel(cd, dependency.RevisionLabel)
+			got := c.getTargetLabel(dep, dependency.RevisionLabel)
+			if want == "" || want != got {
+				return fmt.Sprintf("dependency %s.%s to be promoted with %s=%s",
+					name, namespace, dependency.RevisionLabel, want), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// findDependencyCycle returns the chain of canaries that leads back to the given canary,
+// or nil if the canary is not part of a dependency cycle
+func (c *Controller) findDependencyCycle(cd *flaggerv1.Canary) []string {
+	root := fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)
+	visited := map[string]bool{}
+
+	var walk func(cd *flaggerv1.Canary, path []string) []string
+	walk = func(cd *flaggerv1.Canary, path []string) []string {
+		for _, dependency := range cd.Spec.DependsOn {
+			namespace := dependency.Namespace
+			if namespace == "" {
+				namespace = cd.Namespace
+			}
+			if c.noCrossNamespaceRefs && namespace != cd.Namespace {
+				continue
+			}
+			key := fmt.Sprintf("%s.%s", dependency.Name, namespace)
+			if key == root {
+				return append(path, key)
+			}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			dep, err := c.flaggerClient.FlaggerV1beta1().Canaries(namespace).Get(context.TODO(), dependency.Name, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			if cycle := walk(dep, append(path, key)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return walk(cd, []string{root})
+}
+
+// getTargetLabel returns the value of a pod template label of the canary target
+func (c *Controller) getTargetLabel(cd *flaggerv1.Canary, label string) string {
+	podTemplate := c.getTargetPodTemplate(cd)
+	if podTemplate == nil {
+		return ""
+	}
+	return podTemplate.Labels[label]
+}
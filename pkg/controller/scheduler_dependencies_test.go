@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestScheduler_DependsOn(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CanaryDependency{{Name: "podinfo-api", RevisionLabel: "app"}}
+	mocks := newUpdatedDeploymentFixture(t, cd)
+
+	// the dependency doesn't exist
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseWaiting))
+
+	// the dependency is under analysis
+	dep := newDeploymentTestCanary()
+	dep.Name = "podinfo-api"
+	dep.Status.Phase = flaggerv1.CanaryPhaseProgressing
+	dep, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Create(context.TODO(), dep, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseWaiting))
+
+	// the dependency is promoted
+	err = mocks.ctrl.canaryFactory.Controller(dep.Spec.TargetRef).SyncStatus(dep, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseSucceeded})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+}
+
+func TestScheduler_DependsOnCycle(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CanaryDependency{{Name: "podinfo-api"}}
+	mocks := newUpdatedDeploymentFixture(t, cd)
+
+	dep := newDeploymentTestCanary()
+	dep.Name = "podinfo-api"
+	dep.Spec.DependsOn = []flaggerv1.CanaryDependency{{Name: "podinfo", Namespace: "default"}}
+	_, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Create(context.TODO(), dep, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"podinfo.default", "podinfo-api.default", "podinfo.default"}, mocks.ctrl.findDependencyCycle(cd))
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseWaiting))
+}
+
+func TestScheduler_DependsOnCrossNamespace(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CanaryDependency{{Name: "podinfo-api", Namespace: "test"}}
+	mocks := newUpdatedDeploymentFixture(t, cd)
+	mocks.ctrl.noCrossNamespaceRefs = true
+
+	// the promoted dependency in another namespace is not accessed
+	dep := newDeploymentTestCanary()
+	dep.Name = "podinfo-api"
+	dep.Namespace = "test"
+	dep.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	_, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("test").Create(context.TODO(), dep, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	_, err = mocks.ctrl.checkDependencies(cd)
+	require.Error(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+}
+
+// newUpdatedDeploymentFixture initializes the canary and updates its target to a new revision
+func newUpdatedDeploymentFixture(t *testing.T, cd *flaggerv1.Canary) fixture {
+	mocks := newDeploymentFixture(cd)
+
+	// initializing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// make primary ready
+	mocks.makePrimaryReady(t)
+
+	// initialized
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+
+	// update
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	return mocks
+}
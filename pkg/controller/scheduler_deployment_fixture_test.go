@@ -82,6 +82,18 @@ func (f fixture) makeReady(t *testing.T, name string) {
 	require.NoError(t, err)
 }
 
+// storeCanary creates or updates the canary status in the API and in the informer cache
+func (f fixture) storeCanary(t *testing.T, cd *flaggerv1.Canary) {
+	_, err := f.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).Get(context.TODO(), cd.Name, metav1.GetOptions{})
+	if err != nil {
+		_, err = f.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).Create(context.TODO(), cd, metav1.CreateOptions{})
+	} else {
+		_, err = f.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).UpdateStatus(context.TODO(), cd, metav1.UpdateOptions{})
+	}
+	require.NoError(t, err)
+	require.NoError(t, f.ctrl.flaggerInformers.CanaryInformer.Informer().GetIndexer().Add(cd.DeepCopy()))
+}
+
 func newDeploymentFixture(c *flaggerv1.Canary) fixture {
 	if c == nil {
 		c = newDeploymentTestCanary()
@@ -131,7 +143,7 @@ func newDeploymentFixture(c *flaggerv1.Canary) fixture {
 		KubeClient:    kubeClient,
 		FlaggerClient: flaggerClient,
 	}
-	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, configTracker, []string{"app", "name"}, []string{""}, logger)
+	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, nil, nil, nil, nil, configTracker, []string{"app", "name"}, []string{""}, logger)
 
 	ctrl := &Controller{
 		kubeClient:       kubeClient,
@@ -158,7 +170,7 @@ func newDeploymentFixture(c *flaggerv1.Canary) fixture {
 
 	return fixture{
 		canary:        c,
-		deployer:      canaryFactory.Controller("Deployment"),
+		deployer:      canaryFactory.Controller(flaggerv1.LocalObjectReference{Kind: "Deployment"}),
 		logger:        logger,
 		flaggerClient: flaggerClient,
 		meshClient:    flaggerClient,
@@ -59,11 +59,14 @@ func (c *Controller) runConfirmRolloutHooks(canary *flaggerv1.Canary, canaryCont
 				return false
 			} else {
 				if canary.Status.Phase == flaggerv1.CanaryPhaseWaiting {
+					if admitted := c.admitCanary(canary); !admitted {
+						return false
+					}
 					if err := canaryController.SetStatusPhase(canary, flaggerv1.CanaryPhaseProgressing); err != nil {
 						c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).Errorf("%v", err)
 						return false
 					}
-					if err := canaryController.ScaleFromZero(canary); err != nil {
+					if err := c.scaleCanaryFromZero(canary, canaryController); err != nil {
 						c.recordEventErrorf(canary, "%v", err)
 						return false
 					}
@@ -101,6 +104,21 @@ func (c *Controller) runConfirmPromotionHooks(canary *flaggerv1.Canary, canaryCo
 			}
 		}
 	}
+
+	// hold the promotion until every member of the release bundle has passed its last analysis
+	if peer, err := c.getUnfinishedBundlePeer(canary); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return false
+	} else if peer != nil {
+		if canary.Status.Phase != flaggerv1.CanaryPhaseWaitingPromotion {
+			if err := canaryController.SetStatusPhase(canary, flaggerv1.CanaryPhaseWaitingPromotion); err != nil {
+				c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).Errorf("%v", err)
+			}
+		}
+		c.recordEventInfof(canary, "Halt %s.%s promotion waiting for bundle %s member %s.%s",
+			canary.Name, canary.Namespace, canary.Spec.Bundle, peer.Name, peer.Namespace)
+		return false
+	}
 	return true
 }
 
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -38,6 +39,11 @@ const (
 func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) error {
 	for _, metric := range canary.GetAnalysis().Metrics {
 		if metric.Name == "request-success-rate" || metric.Name == "request-duration" {
+			for _, provider := range c.getMetricsProviders(canary) {
+				if strings.TrimSuffix(provider, MetricsProviderServiceSuffix) == flaggerv1.KongProvider {
+					return fmt.Errorf("metric %s: %w", metric.Name, observers.ErrKongBuiltinMetrics)
+				}
+			}
 			observerFactory := c.observerFactory
 			if canary.Spec.MetricsServer != "" {
 				var err error
@@ -73,7 +79,7 @@ func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) e
 				credentials = secret.Data
 			}
 
-			factory := providers.Factory{}
+			factory := c.newProviderFactory(canary)
 			provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials)
 			if err != nil {
 				return fmt.Errorf("metric template %s.%s provider %s error: %v",
@@ -90,29 +96,39 @@ func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) e
 	return nil
 }
 
-func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
-	// override the global provider if one is specified in the canary spec
-	var metricsProvider string
-	// set the metrics provider to Crossover Prometheus when Crossover is the mesh provider
-	// For example, `crossover` metrics provider should be used for `smi:crossover` mesh provider
-	if strings.Contains(c.meshProvider, "crossover") {
-		metricsProvider = "crossover"
-	} else {
-		metricsProvider = c.meshProvider
-	}
-
-	if canary.Spec.Provider != "" {
-		metricsProvider = canary.Spec.Provider
-
+// getMetricsProviders returns the providers queried by the builtin metric checks
+func (c *Controller) getMetricsProviders(canary *flaggerv1.Canary) []string {
+	// override the global provider if one or more are specified in the canary spec
+	var metricsProviders []string
+	if len(canary.Spec.Providers) > 0 {
+		// the providers list is used as is, each provider has its own metrics
+		metricsProviders = append(metricsProviders, canary.Spec.Providers...)
+	} else if canary.Spec.Provider != "" {
+		metricsProviders = []string{canary.Spec.Provider}
 		// set the metrics provider to Linkerd Prometheus when Linkerd is the default mesh provider
 		if strings.Contains(c.meshProvider, "linkerd") {
-			metricsProvider = "linkerd"
+			metricsProviders = []string{"linkerd"}
 		}
+	} else if strings.Contains(c.meshProvider, "crossover") {
+		// set the metrics provider to Crossover Prometheus when Crossover is the mesh provider
+		// For example, `crossover` metrics provider should be used for `smi:crossover` mesh provider
+		metricsProviders = []string{"crossover"}
+	} else {
+		metricsProviders = []string{c.meshProvider}
 	}
+
 	// set the metrics provider to query Prometheus for the canary Kubernetes service if the canary target is Service
-	if canary.Spec.TargetRef.Kind == "Service" {
-		metricsProvider = metricsProvider + MetricsProviderServiceSuffix
+	if canary.Spec.TargetRef.Kind == "Service" && !canary.Spec.TargetRef.IsKnativeService() {
+		for i := range metricsProviders {
+			metricsProviders[i] = metricsProviders[i] + MetricsProviderServiceSuffix
+		}
 	}
+	return metricsProviders
+}
+
+func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
+	metricsProviders := c.getMetricsProviders(canary)
+	metricsProvider := strings.Join(metricsProviders, ",")
 
 	// create observer based on the mesh provider
 	observerFactory := c.observerFactory
@@ -126,7 +142,8 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 			return false
 		}
 	}
-	observer := observerFactory.Observer(metricsProvider)
+	observer := observerFactory.Observers(metricsProviders)
+	podTemplate := c.getTargetPodTemplate(canary)
 
 	// run metrics checks
 	for _, metric := range canary.GetAnalysis().Metrics {
@@ -135,7 +152,7 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 		}
 
 		if metric.Name == "request-success-rate" {
-			val, err := observer.GetRequestSuccessRate(toMetricModel(canary, metric.Interval))
+			val, err := observer.GetRequestSuccessRate(toMetricModel(canary, metric.Interval, podTemplate))
 			if err != nil {
 				if errors.Is(err, providers.ErrNoValuesFound) {
 					c.recordEventWarningf(canary,
@@ -167,7 +184,7 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 		}
 
 		if metric.Name == "request-duration" {
-			val, err := observer.GetRequestDuration(toMetricModel(canary, metric.Interval))
+			val, err := observer.GetRequestDuration(toMetricModel(canary, metric.Interval, podTemplate))
 			if err != nil {
 				if errors.Is(err, providers.ErrNoValuesFound) {
 					c.recordEventWarningf(canary, "Halt advancement no values found for %s metric %s probably %s.%s is not receiving traffic",
@@ -199,7 +216,7 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 
 		// in-line PromQL
 		if metric.Query != "" {
-			query, err := observers.RenderQuery(metric.Query, toMetricModel(canary, metric.Interval))
+			query, err := observers.RenderQuery(metric.Query, toMetricModel(canary, metric.Interval, podTemplate))
 			val, err := observerFactory.Client.RunQuery(query)
 			if err != nil {
 				if errors.Is(err, providers.ErrNoValuesFound) {
@@ -235,6 +252,7 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 }
 
 func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) bool {
+	podTemplate := c.getTargetPodTemplate(canary)
 	for _, metric := range canary.GetAnalysis().Metrics {
 		if metric.TemplateRef != nil {
 			namespace := canary.Namespace
@@ -259,7 +277,7 @@ func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) bool {
 				credentials = secret.Data
 			}
 
-			factory := providers.Factory{}
+			factory := c.newProviderFactory(canary)
 			provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials)
 			if err != nil {
 				c.recordEventErrorf(canary, "Metric template %s.%s provider %s error: %v",
@@ -267,7 +285,7 @@ func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) bool {
 				return false
 			}
 
-			query, err := observers.RenderQuery(template.Spec.Query, toMetricModel(canary, metric.Interval))
+			query, err := observers.RenderQuery(template.Spec.Query, toMetricModel(canary, metric.Interval, podTemplate))
 			if err != nil {
 				c.recordEventErrorf(canary, "Metric template %s.%s query render error: %v",
 					metric.TemplateRef.Name, namespace, err)
@@ -310,19 +328,123 @@ func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) bool {
 	return true
 }
 
-func toMetricModel(r *flaggerv1.Canary, interval string) flaggerv1.MetricTemplateModel {
+// newProviderFactory returns a metric providers factory for the canary target
+func (c *Controller) newProviderFactory(canary *flaggerv1.Canary) providers.Factory {
+	factory := providers.Factory{}
+	if c.kubeMetricsClient == nil {
+		return factory
+	}
+
+	canaryController := c.canaryFactory.Controller(canary.Spec.TargetRef)
+	label, labelValue, _, err := canaryController.GetMetadata(canary)
+	if err != nil || label == "" {
+		return factory
+	}
+
+	factory.KubernetesTarget = &providers.KubernetesTarget{
+		Client:          c.kubeMetricsClient.MetricsV1beta1(),
+		Namespace:       canary.Namespace,
+		CanarySelector:  fmt.Sprintf("%s=%s", label, labelValue),
+		PrimarySelector: fmt.Sprintf("%s=%s-primary", label, labelValue),
+	}
+	return factory
+}
+
+// getTargetPodTemplate returns the pod template of the canary workload,
+// or nil if the target kind has no pod template or the workload can't be fetched
+func (c *Controller) getTargetPodTemplate(canary *flaggerv1.Canary) *corev1.PodTemplateSpec {
+	if canary.Spec.TargetRef.IsKnativeService() {
+		svc, err := c.flaggerClient.ServingV1().Services(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
+		if err != nil {
+			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+				Errorf("Service %s.%s get query error: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+			return nil
+		}
+		// the template is named after the latest revision created from it
+		template := &corev1.PodTemplateSpec{
+			ObjectMeta: *svc.Spec.Template.ObjectMeta.DeepCopy(),
+			Spec:       svc.Spec.Template.Spec.PodSpec,
+		}
+		template.Name = svc.Status.LatestCreatedRevisionName
+		return template
+	}
+
+	switch canary.Spec.TargetRef.Kind {
+	case "Deployment":
+		dep, err := c.kubeClient.AppsV1().Deployments(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
+		if err != nil {
+			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+				Errorf("Deployment %s.%s get query error: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+			return nil
+		}
+		return &dep.Spec.Template
+	case "DaemonSet":
+		dae, err := c.kubeClient.AppsV1().DaemonSets(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
+		if err != nil {
+			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+				Errorf("DaemonSet %s.%s get query error: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+			return nil
+		}
+		return &dae.Spec.Template
+	default:
+		return nil
+	}
+}
+
+// getTargetImage returns the image of the container named after the target,
+// or the image of the first container if there is no such container
+func getTargetImage(name string, podTemplate *corev1.PodTemplateSpec) string {
+	if podTemplate == nil || len(podTemplate.Spec.Containers) == 0 {
+		return ""
+	}
+	for _, container := range podTemplate.Spec.Containers {
+		if container.Name == name {
+			return container.Image
+		}
+	}
+	return podTemplate.Spec.Containers[0].Image
+}
+
+// getImageTag returns the tag of an image reference, e.g. `1.0.0` for `ghcr.io/stefanprodan/podinfo:1.0.0@sha256:...`
+func getImageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+func toMetricModel(r *flaggerv1.Canary, interval string, podTemplate *corev1.PodTemplateSpec) flaggerv1.MetricTemplateModel {
 	service := r.Spec.TargetRef.Name
 	if r.Spec.Service.Name != "" {
 		service = r.Spec.Service.Name
 	}
 	ingress := r.Spec.TargetRef.Name
-	if r.Spec.IngressRef != nil {
-		ingress = r.Spec.IngressRef.Name
+	var ingresses []string
+	for _, ref := range r.GetIngressRefs() {
+		ingresses = append(ingresses, ref.Name)
+	}
+	if len(ingresses) > 0 {
+		ingress = ingresses[0]
+	} else {
+		ingresses = []string{ingress}
 	}
 	route := r.Spec.TargetRef.Name
 	if r.Spec.RouteRef != nil {
 		route = r.Spec.RouteRef.Name
 	}
+	image := getTargetImage(r.Spec.TargetRef.Name, podTemplate)
+	labels := map[string]string{}
+	revision := ""
+	if podTemplate != nil {
+		revision = podTemplate.Name
+		for k, v := range podTemplate.Labels {
+			labels[k] = v
+		}
+	}
 	return flaggerv1.MetricTemplateModel{
 		Name:      r.Name,
 		Namespace: r.Namespace,
@@ -331,5 +453,10 @@ func toMetricModel(r *flaggerv1.Canary, interval string) flaggerv1.MetricTemplat
 		Ingress:   ingress,
 		Route:     route,
 		Interval:  interval,
+		Image:     image,
+		ImageTag:  getImageTag(image),
+		Labels:    labels,
+		Ingresses: ingresses,
+		Revision:  revision,
 	}
 }
@@ -19,6 +19,7 @@ package controller
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"k8s.io/client-go/tools/record"
@@ -46,6 +47,10 @@ func TestController_checkMetricProviderAvailability(t *testing.T) {
 		// ok
 		canary.Spec.MetricsServer = testMetricsServerURL
 		require.NoError(t, ctrl.checkMetricProviderAvailability(canary))
+
+		// error (the Kong metrics include the primary requests)
+		canary.Spec.Provider = flaggerv1.KongProvider
+		require.ErrorIs(t, ctrl.checkMetricProviderAvailability(canary), observers.ErrKongBuiltinMetrics)
 	})
 
 	t.Run("templateRef", func(t *testing.T) {
@@ -82,3 +87,43 @@ func TestController_checkMetricProviderAvailability(t *testing.T) {
 		require.NoError(t, ctrl.checkMetricProviderAvailability(canary))
 	})
 }
+
+func TestController_toMetricModel(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+
+	podTemplate := mocks.ctrl.getTargetPodTemplate(mocks.canary)
+	require.NotNil(t, podTemplate)
+
+	model := toMetricModel(mocks.canary, "1m", podTemplate)
+	require.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", model.Image)
+	require.Equal(t, "1.2.0", model.ImageTag)
+	require.Equal(t, "podinfo", model.Labels["app"])
+
+	query, err := observers.RenderQuery(`release:{{ imageTag }} app:{{ labels.app }}`, model)
+	require.NoError(t, err)
+	require.Equal(t, "release:1.2.0 app:podinfo", query)
+}
+
+func TestController_getMetricsProviders(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.ctrl.meshProvider = "linkerd"
+	cd := mocks.canary.DeepCopy()
+
+	// the mesh default applies to the canary provider
+	assert.Equal(t, []string{"linkerd"}, mocks.ctrl.getMetricsProviders(cd))
+	cd.Spec.Provider = flaggerv1.NGINXProvider
+	assert.Equal(t, []string{"linkerd"}, mocks.ctrl.getMetricsProviders(cd))
+
+	// each provider of the list is queried
+	cd.Spec.Provider = ""
+	cd.Spec.Providers = []string{flaggerv1.NGINXProvider, flaggerv1.LinkerdProvider}
+	assert.Equal(t, []string{"nginx", "linkerd"}, mocks.ctrl.getMetricsProviders(cd))
+	assert.Equal(t, []string{"nginx", "linkerd"}, cd.Spec.Providers)
+}
+
+func Test_getImageTag(t *testing.T) {
+	require.Equal(t, "1.2.0", getImageTag("quay.io/stefanprodan/podinfo:1.2.0"))
+	require.Equal(t, "1.2.0", getImageTag("localhost:5000/podinfo:1.2.0@sha256:abcd"))
+	require.Equal(t, "", getImageTag("localhost:5000/podinfo"))
+	require.Equal(t, "", getImageTag("podinfo@sha256:abcd"))
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// runPodHealthChecks inspects the canary pods for container restarts, OOMKilled terminations,
+// CrashLoopBackOff and warning events. It returns false if a check fails and
+// true for rollback if the canary should be rolled back without waiting for the failed checks threshold.
+func (c *Controller) runPodHealthChecks(canary *flaggerv1.Canary) (ok bool, rollback bool) {
+	health := canary.GetAnalysis().PodHealth
+	if health == nil {
+		return true, false
+	}
+
+	pods, err := c.listCanaryPods(canary)
+	if err != nil {
+		c.recordEventErrorf(canary, "Pod health check failed for %s.%s: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+		return false, false
+	}
+	if pods == nil {
+		return true, false
+	}
+
+	// the baseline is recorded when the analysis starts,
+	// record it now if the analysis started before pod health checks were enabled
+	baseline := canary.Status.PodHealth
+	if baseline == nil || baseline.Revision != canary.Status.LastAppliedSpec {
+		baseline, err = c.setPodHealthBaseline(canary, pods)
+		if err != nil {
+			c.recordEventErrorf(canary, "Pod health check failed for %s.%s: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+			return false, false
+		}
+	}
+
+	var restarts int32
+	names := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		names[pod.Name] = true
+		var podRestarts int32
+		for _, status := range pod.Status.ContainerStatuses {
+			podRestarts += status.RestartCount
+
+			if health.OOMKilled && isOOMKilled(status) {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement pod %s container %s was OOMKilled",
+					canary.Name, canary.Namespace, pod.Name, status.Name)
+				return false, health.Rollback
+			}
+
+			if health.CrashLoopBackOff && status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement pod %s container %s is in CrashLoopBackOff",
+					canary.Name, canary.Namespace, pod.Name, status.Name)
+				return false, health.Rollback
+			}
+		}
+
+		// count the restarts since the analysis started
+		if podRestarts > baseline.Restarts[pod.Name] {
+			restarts += podRestarts - baseline.Restarts[pod.Name]
+		}
+	}
+
+	if health.MaxRestarts != nil && restarts > *health.MaxRestarts {
+		c.recordEventWarningf(canary, "Halt %s.%s advancement pod restarts %v > %v",
+			canary.Name, canary.Namespace, restarts, *health.MaxRestarts)
+		return false, health.Rollback
+	}
+
+	if health.MaxWarningEvents != nil {
+		events, err := c.kubeClient.CoreV1().Events(canary.Namespace).List(context.TODO(), metav1.ListOptions{
+			FieldSelector: fields.Set{
+				"involvedObject.kind": "Pod",
+				"type":                corev1.EventTypeWarning,
+			}.String(),
+		})
+		if err != nil {
+			c.recordEventErrorf(canary, "Pod health check failed for %s.%s: %v", canary.Spec.TargetRef.Name, canary.Namespace, err)
+			return false, false
+		}
+
+		// count the events emitted after the analysis started
+		var count int32
+		for _, event := range events.Items {
+			timestamp := event.LastTimestamp
+			if timestamp.IsZero() {
+				timestamp = metav1.NewTime(event.EventTime.Time)
+			}
+			if !names[event.InvolvedObject.Name] || timestamp.Before(&baseline.StartTime) {
+				continue
+			}
+			// an aggregated event that began before the analysis started counts for its last occurrence only
+			if event.Count == 0 || event.FirstTimestamp.Before(&baseline.StartTime) {
+				count++
+				continue
+			}
+			count += event.Count
+		}
+
+		if count > *health.MaxWarningEvents {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement pod warning events %v > %v",
+				canary.Name, canary.Namespace, count, *health.MaxWarningEvents)
+			return false, health.Rollback
+		}
+	}
+
+	return true, false
+}
+
+// startPodHealthChecks records the pod health checks baseline when the analysis starts
+func (c *Controller) startPodHealthChecks(canary *flaggerv1.Canary) error {
+	if canary.GetAnalysis().PodHealth == nil {
+		return nil
+	}
+
+	pods, err := c.listCanaryPods(canary)
+	if err != nil {
+		return fmt.Errorf("pod health baseline for %s.%s failed: %w", canary.Name, canary.Namespace, err)
+	}
+	_, err = c.setPodHealthBaseline(canary, pods)
+	return err
+}
+
+// listCanaryPods returns the canary pods, or nil if the target has no pod selector
+func (c *Controller) listCanaryPods(canary *flaggerv1.Canary) ([]corev1.Pod, error) {
+	canaryController := c.canaryFactory.Controller(canary.Spec.TargetRef)
+	label, labelValue, _, err := canaryController.GetMetadata(canary)
+	if err != nil || label == "" {
+		return nil, nil
+	}
+
+	pods, err := c.kubeClient.CoreV1().Pods(canary.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", label, labelValue),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pods.Items == nil {
+		return []corev1.Pod{}, nil
+	}
+	return pods.Items, nil
+}
+
+// setPodHealthBaseline records the current time and the restart count of each canary pod
+// in the canary status, for the current revision
+func (c *Controller) setPodHealthBaseline(canary *flaggerv1.Canary, pods []corev1.Pod) (*flaggerv1.CanaryPodHealthStatus, error) {
+	baseline := &flaggerv1.CanaryPodHealthStatus{
+		StartTime: metav1.Now(),
+		Restarts:  make(map[string]int32, len(pods)),
+	}
+	for _, pod := range pods {
+		var restarts int32
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		baseline.Restarts[pod.Name] = restarts
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cd, err := c.flaggerClient.FlaggerV1beta1().Canaries(canary.Namespace).Get(context.TODO(), canary.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("canary %s.%s get query failed: %w", canary.Name, canary.Namespace, err)
+		}
+		baseline.Revision = cd.Status.LastAppliedSpec
+		cdCopy := cd.DeepCopy()
+		cdCopy.Status.PodHealth = baseline
+		_, err = c.flaggerClient.FlaggerV1beta1().Canaries(canary.Namespace).UpdateStatus(context.TODO(), cdCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pod health baseline for %s.%s failed: %w", canary.Name, canary.Namespace, err)
+	}
+	return baseline, nil
+}
+
+func isOOMKilled(status corev1.ContainerStatus) bool {
+	if status.State.Terminated != nil && status.State.Terminated.Reason == "OOMKilled" {
+		return true
+	}
+	return status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.Reason == "OOMKilled"
+}
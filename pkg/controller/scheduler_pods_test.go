@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func newPodHealthTestPod(name string, app string, status corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": app},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{status},
+		},
+	}
+}
+
+func TestController_runPodHealthChecks(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	cd := mocks.canary.DeepCopy()
+
+	// no pod health checks
+	ok, rollback := mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+	assert.False(t, rollback)
+
+	maxRestarts := int32(2)
+	cd.Spec.Analysis.PodHealth = &flaggerv1.CanaryPodHealth{
+		MaxRestarts:      &maxRestarts,
+		OOMKilled:        true,
+		CrashLoopBackOff: true,
+	}
+
+	// the baseline is recorded before the canary pods are created
+	ok, _ = mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+	stored, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, stored.Status.PodHealth)
+	cd.Status = stored.Status
+
+	// restarts within threshold, primary pods are ignored
+	_, err = mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-1", "podinfo", corev1.ContainerStatus{Name: "podinfo", RestartCount: 2}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-primary-1", "podinfo-primary", corev1.ContainerStatus{
+			Name:  "podinfo",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ok, _ = mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+
+	// restarts above threshold
+	_, err = mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-2", "podinfo", corev1.ContainerStatus{Name: "podinfo", RestartCount: 1}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ok, rollback = mocks.ctrl.runPodHealthChecks(cd)
+	assert.False(t, ok)
+	assert.False(t, rollback)
+
+	// restarts that happened before the analysis started are ignored
+	pods, err := mocks.ctrl.listCanaryPods(cd)
+	require.NoError(t, err)
+	cd.Status.PodHealth, err = mocks.ctrl.setPodHealthBaseline(cd, pods)
+	require.NoError(t, err)
+	ok, _ = mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+
+	// OOMKilled with rollback
+	maxRestarts = 10
+	cd.Spec.Analysis.PodHealth.Rollback = true
+	_, err = mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-3", "podinfo", corev1.ContainerStatus{
+			Name: "podinfo",
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+			},
+		}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ok, rollback = mocks.ctrl.runPodHealthChecks(cd)
+	assert.False(t, ok)
+	assert.True(t, rollback)
+}
+
+func TestController_runPodHealthChecksEvents(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	cd := mocks.canary.DeepCopy()
+	maxWarningEvents := int32(1)
+	cd.Spec.Analysis.PodHealth = &flaggerv1.CanaryPodHealth{
+		MaxWarningEvents: &maxWarningEvents,
+	}
+
+	_, err := mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-1", "podinfo", corev1.ContainerStatus{Name: "podinfo"}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	pods, err := mocks.ctrl.listCanaryPods(cd)
+	require.NoError(t, err)
+	cd.Status.PodHealth, err = mocks.ctrl.setPodHealthBaseline(cd, pods)
+	require.NoError(t, err)
+
+	newEvent := func(name string, timestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "podinfo-1", Namespace: "default"},
+			Type:           corev1.EventTypeWarning,
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(timestamp),
+		}
+	}
+
+	// the events emitted before the analysis started are ignored
+	start := cd.Status.PodHealth.StartTime.Time
+	for i, timestamp := range []time.Time{start.Add(-time.Minute), start.Add(-time.Second), start.Add(time.Second)} {
+		_, err = mocks.kubeClient.CoreV1().Events("default").Create(context.TODO(),
+			newEvent(fmt.Sprintf("podinfo-1.%d", i), timestamp), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	ok, _ := mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+
+	// the events are counted even if the status was updated since the analysis started
+	cd.Status.LastTransitionTime = metav1.NewTime(start.Add(time.Hour))
+	_, err = mocks.kubeClient.CoreV1().Events("default").Create(context.TODO(),
+		newEvent("podinfo-1.3", start.Add(2*time.Second)), metav1.CreateOptions{})
+	require.NoError(t, err)
+	ok, _ = mocks.ctrl.runPodHealthChecks(cd)
+	assert.False(t, ok)
+}
+
+func TestController_runPodHealthChecksAggregatedEvents(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	cd := mocks.canary.DeepCopy()
+	maxWarningEvents := int32(1)
+	cd.Spec.Analysis.PodHealth = &flaggerv1.CanaryPodHealth{
+		MaxWarningEvents: &maxWarningEvents,
+	}
+
+	_, err := mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-1", "podinfo", corev1.ContainerStatus{Name: "podinfo"}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	pods, err := mocks.ctrl.listCanaryPods(cd)
+	require.NoError(t, err)
+	cd.Status.PodHealth, err = mocks.ctrl.setPodHealthBaseline(cd, pods)
+	require.NoError(t, err)
+
+	// the occurrences of an aggregated event from before the analysis started are ignored
+	start := cd.Status.PodHealth.StartTime.Time
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "podinfo-1.0", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "podinfo-1", Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Count:          10,
+		FirstTimestamp: metav1.NewTime(start.Add(-time.Hour)),
+		LastTimestamp:  metav1.NewTime(start.Add(time.Second)),
+	}
+	_, err = mocks.kubeClient.CoreV1().Events("default").Create(context.TODO(), event, metav1.CreateOptions{})
+	require.NoError(t, err)
+	ok, _ := mocks.ctrl.runPodHealthChecks(cd)
+	assert.True(t, ok)
+
+	// the occurrences of an aggregated event that began after the analysis started are counted
+	event = event.DeepCopy()
+	event.Name = "podinfo-1.1"
+	event.Count = 2
+	event.FirstTimestamp = metav1.NewTime(start.Add(time.Second))
+	_, err = mocks.kubeClient.CoreV1().Events("default").Create(context.TODO(), event, metav1.CreateOptions{})
+	require.NoError(t, err)
+	ok, _ = mocks.ctrl.runPodHealthChecks(cd)
+	assert.False(t, ok)
+}
+
+func TestScheduler_DeploymentPodHealthRollback(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	// initializing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// make primary ready
+	mocks.makePrimaryReady(t)
+
+	// initialized
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// enable pod health checks with rollback
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	cd := c.DeepCopy()
+	cd.Spec.Analysis.PodHealth = &flaggerv1.CanaryPodHealth{
+		CrashLoopBackOff: true,
+		Rollback:         true,
+	}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), cd, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// update canary spec
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.makeCanaryReady(t)
+
+	// start analysis
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+
+	// crash the canary pod
+	_, err = mocks.kubeClient.CoreV1().Pods("default").Create(context.TODO(),
+		newPodHealthTestPod("podinfo-1", "podinfo", corev1.ContainerStatus{
+			Name:  "podinfo",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// namespaceLimitTTL is the time the namespace concurrency limits are cached for
+const namespaceLimitTTL = time.Minute
+
+// rolloutQueue holds the limits of canaries under analysis at the same time
+type rolloutQueue struct {
+	// mu serializes the admission of canaries across the scheduler jobs
+	mu sync.Mutex
+
+	// maxConcurrent is the max number of canaries under analysis cluster wide, zero means no limit
+	maxConcurrent int
+
+	// maxConcurrentPerNamespace is the default max number of canaries under analysis in a namespace,
+	// it can be overridden with the max-concurrent-canaries namespace annotation
+	maxConcurrentPerNamespace int
+
+	// admitted holds the resource version of the admitted canaries as seen in the informer cache,
+	// an admitted canary takes a slot until the cache observes its status update
+	admitted map[string]string
+
+	// limits caches the namespace concurrency limits read from the namespace annotations
+	limits map[string]namespaceLimit
+}
+
+type namespaceLimit struct {
+	value   int
+	expires time.Time
+}
+
+// admitCanary returns true if the analysis of the canary can start without exceeding the concurrency limits,
+// otherwise the canary is kept in the rollout queue ordered by priority and arrival
+func (c *Controller) admitCanary(cd *flaggerv1.Canary) bool {
+	c.queue.mu.Lock()
+	defer c.queue.mu.Unlock()
+
+	nsLimit, err := c.getNamespaceConcurrencyLimit(cd.Namespace)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	if c.queue.maxConcurrent < 1 && nsLimit < 1 {
+		return true
+	}
+
+	list, err := c.flaggerInformers.CanaryInformer.Lister().List(labels.Everything())
+	if err != nil {
+		c.recordEventWarningf(cd, "canaries list query error: %v", err)
+		return false
+	}
+
+	key := fmt.Sprintf("%s/%s", cd.Namespace, cd.Name)
+	version := ""
+	active := rolloutSlots{}
+	admitted := map[string]string{}
+	var queue []*flaggerv1.Canary
+	for _, item := range list {
+		itemKey := fmt.Sprintf("%s/%s", item.Namespace, item.Name)
+		if itemKey == key {
+			version = item.ResourceVersion
+			continue
+		}
+
+		// a canary admitted by a previous job takes a slot until its status update is observed
+		if v, ok := c.queue.admitted[itemKey]; ok && v == item.ResourceVersion {
+			admitted[itemKey] = v
+			active.take(item)
+			continue
+		}
+
+		switch item.Status.Phase {
+		case flaggerv1.CanaryPhaseProgressing, flaggerv1.CanaryPhaseWaitingPromotion,
+			flaggerv1.CanaryPhasePromoting, flaggerv1.CanaryPhaseFinalising:
+			active.take(item)
+		case flaggerv1.CanaryPhaseQueued:
+			queue = append(queue, item)
+		}
+	}
+	c.queue.admitted = admitted
+
+	// the canary joins the queue behind the canaries that arrived before it
+	self := cd
+	if cd.Status.Phase != flaggerv1.CanaryPhaseQueued {
+		self = cd.DeepCopy()
+		self.Status.LastTransitionTime = metav1.Now()
+	}
+	queue = append(queue, self)
+	sortRolloutQueue(queue)
+
+	// hand out the free slots in queue order, a canary held back by the limit
+	// of its namespace doesn't block the canaries of other namespaces
+	position := 0
+	for _, item := range queue {
+		limit, err := c.getNamespaceConcurrencyLimit(item.Namespace)
+		if err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+
+		// the members of a release bundle share the slot taken by the first of them
+		fits := active.has(item) ||
+			((c.queue.maxConcurrent < 1 || len(active) < c.queue.maxConcurrent) &&
+				(limit < 1 || active.count(item.Namespace) < limit))
+
+		if item == self {
+			if fits {
+				c.queue.admitted[key] = version
+				return true
+			}
+			position++
+			if cd.Status.Phase != flaggerv1.CanaryPhaseQueued {
+				c.recordEventInfof(cd, "Halt %s.%s advancement queued at position %d, concurrency limit reached",
+					cd.Name, cd.Namespace, position)
+			}
+			if cd.Status.Phase != flaggerv1.CanaryPhaseQueued || position != cd.Status.QueuePosition {
+				if err := c.setPhaseQueued(cd, position); err != nil {
+					c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+				}
+			}
+			return false
+		}
+
+		if fits {
+			active.take(item)
+		} else {
+			position++
+		}
+	}
+	return false
+}
+
+// rolloutSlots holds the concurrency slots taken by the canaries under analysis,
+// the canaries of a release bundle are analysed together and take a single slot
+type rolloutSlots map[string]string
+
+func rolloutSlot(cd *flaggerv1.Canary) string {
+	if cd.Spec.Bundle != "" {
+		return fmt.Sprintf("%s/bundle/%s", cd.Namespace, cd.Spec.Bundle)
+	}
+	return fmt.Sprintf("%s/%s", cd.Namespace, cd.Name)
+}
+
+func (s rolloutSlots) take(cd *flaggerv1.Canary) {
+	s[rolloutSlot(cd)] = cd.Namespace
+}
+
+func (s rolloutSlots) has(cd *flaggerv1.Canary) bool {
+	_, ok := s[rolloutSlot(cd)]
+	return ok
+}
+
+// count returns the number of slots taken in the namespace
+func (s rolloutSlots) count(namespace string) int {
+	n := 0
+	for _, ns := range s {
+		if ns == namespace {
+			n++
+		}
+	}
+	return n
+}
+
+// sortRolloutQueue orders the queued canaries by priority then by the time they joined the queue
+func sortRolloutQueue(queue []*flaggerv1.Canary) {
+	sort.SliceStable(queue, func(i, j int) bool {
+		if queue[i].Spec.Priority != queue[j].Spec.Priority {
+			return queue[i].Spec.Priority > queue[j].Spec.Priority
+		}
+		if !queue[i].Status.LastTransitionTime.Equal(&queue[j].Status.LastTransitionTime) {
+			return queue[i].Status.LastTransitionTime.Before(&queue[j].Status.LastTransitionTime)
+		}
+		return queue[i].Namespace+"/"+queue[i].Name < queue[j].Namespace+"/"+queue[j].Name
+	})
+}
+
+// getNamespaceConcurrencyLimit returns the max number of canaries under analysis in a namespace,
+// the values read from the namespace annotations are cached for namespaceLimitTTL
+func (c *Controller) getNamespaceConcurrencyLimit(namespace string) (int, error) {
+	if limit, ok := c.queue.limits[namespace]; ok && time.Now().Before(limit.expires) {
+		return limit.value, nil
+	}
+
+	limit := c.queue.maxConcurrentPerNamespace
+	ns, err := c.kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, fmt.Errorf("namespace %s get query error: %w", namespace, err)
+	}
+	if err == nil {
+		if value, ok := ns.Annotations[flaggerv1.MaxConcurrentCanariesAnnotation]; ok {
+			limit, err = strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("namespace %s annotation %s parse error: %w",
+					namespace, flaggerv1.MaxConcurrentCanariesAnnotation, err)
+			}
+		}
+	}
+
+	if c.queue.limits == nil {
+		c.queue.limits = map[string]namespaceLimit{}
+	}
+	c.queue.limits[namespace] = namespaceLimit{value: limit, expires: time.Now().Add(namespaceLimitTTL)}
+	return limit, nil
+}
+
+func (c *Controller) setPhaseQueued(cd *flaggerv1.Canary, position int) error {
+	phase := flaggerv1.CanaryPhaseQueued
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			cd, err = c.flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := cd.DeepCopy()
+		cdCopy.Status.QueuePosition = position
+		if cd.Status.Phase != phase {
+			cdCopy.Status.Phase = phase
+			cdCopy.Status.CanaryWeight = 0
+			cdCopy.Status.Iterations = 0
+			cdCopy.Status.LastTransitionTime = metav1.Now()
+		}
+		if ok, conditions := canary.MakeStatusConditions(cdCopy, phase); ok {
+			cdCopy.Status.Conditions = conditions
+		}
+		_, err = c.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).UpdateStatus(context.TODO(), cdCopy, metav1.UpdateOptions{})
+		firstTry = false
+		return
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	k8sTesting "k8s.io/client-go/testing"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	fakeFlagger "github.com/fluxcd/flagger/pkg/client/clientset/versioned/fake"
+)
+
+func TestScheduler_QueueConcurrencyLimit(t *testing.T) {
+	mocks := newUpdatedDeploymentFixture(t, newDeploymentTestCanary())
+	mocks.ctrl.queue.maxConcurrent = 1
+
+	peer := newQueueTestCanary("podinfo-api", "default", flaggerv1.CanaryPhaseProgressing)
+	mocks.storeCanary(t, peer)
+
+	// the only slot is taken
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseQueued, c.Status.Phase)
+	assert.Equal(t, 1, c.Status.QueuePosition)
+
+	// the slot is released
+	peer.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+	assert.Equal(t, 0, c.Status.QueuePosition)
+}
+
+func TestScheduler_QueueNamespaceLimit(t *testing.T) {
+	mocks := newUpdatedDeploymentFixture(t, newDeploymentTestCanary())
+	mocks.ctrl.queue.maxConcurrent = 3
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{flaggerv1.MaxConcurrentCanariesAnnotation: "1"},
+		},
+	}
+	_, err := mocks.kubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// a canary of another namespace is ahead in the queue but doesn't count for the namespace limit
+	queued := newQueueTestCanary("backend", "test", flaggerv1.CanaryPhaseQueued)
+	queued.Status.LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	mocks.storeCanary(t, queued)
+
+	peer := newQueueTestCanary("podinfo-api", "default", flaggerv1.CanaryPhaseProgressing)
+	mocks.storeCanary(t, peer)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseQueued, c.Status.Phase)
+	assert.Equal(t, 1, c.Status.QueuePosition)
+
+	// the namespace limit is lifted and the cached limits expire
+	ns.Annotations[flaggerv1.MaxConcurrentCanariesAnnotation] = "0"
+	_, err = mocks.kubeClient.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseQueued))
+
+	mocks.ctrl.queue.limits = nil
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+}
+
+func TestScheduler_QueueAdmission(t *testing.T) {
+	mocks := newUpdatedDeploymentFixture(t, newDeploymentTestCanary())
+	mocks.ctrl.queue.maxConcurrent = 2
+
+	peer := newQueueTestCanary("podinfo-api", "default", flaggerv1.CanaryPhaseProgressing)
+	mocks.storeCanary(t, peer)
+
+	waiting := newQueueTestCanary("podinfo-bff", "default", flaggerv1.CanaryPhaseSucceeded)
+	mocks.storeCanary(t, waiting)
+
+	// a free slot is taken without joining the queue
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+	for _, action := range mocks.flaggerClient.(*fakeFlagger.Clientset).Actions() {
+		if update, ok := action.(k8sTesting.UpdateAction); ok && update.GetSubresource() == "status" {
+			cd := update.GetObject().(*flaggerv1.Canary)
+			assert.NotEqual(t, flaggerv1.CanaryPhaseQueued, cd.Status.Phase)
+		}
+	}
+
+	// the admitted canary takes the slot until the cache observes its status update
+	assert.False(t, mocks.ctrl.admitCanary(waiting))
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo-bff", flaggerv1.CanaryPhaseQueued))
+
+	admitted, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	admitted.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	admitted.ResourceVersion = "2"
+	mocks.storeCanary(t, admitted)
+
+	waiting, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo-bff", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, mocks.ctrl.admitCanary(waiting))
+}
+
+func TestScheduler_SortRolloutQueue(t *testing.T) {
+	now := time.Now()
+	first := newQueueTestCanary("first", "default", flaggerv1.CanaryPhaseQueued)
+	first.Status.LastTransitionTime = metav1.NewTime(now.Add(-time.Minute))
+	second := newQueueTestCanary("second", "default", flaggerv1.CanaryPhaseQueued)
+	second.Status.LastTransitionTime = metav1.NewTime(now)
+	urgent := newQueueTestCanary("urgent", "default", flaggerv1.CanaryPhaseQueued)
+	urgent.Status.LastTransitionTime = metav1.NewTime(now)
+	urgent.Spec.Priority = 10
+
+	queue := []*flaggerv1.Canary{second, first, urgent}
+	sortRolloutQueue(queue)
+
+	var names []string
+	for _, item := range queue {
+		names = append(names, item.Name)
+	}
+	assert.Equal(t, []string{"urgent", "first", "second"}, names)
+}
+
+func newQueueTestCanary(name string, namespace string, phase flaggerv1.CanaryPhase) *flaggerv1.Canary {
+	cd := newDeploymentTestCanary()
+	cd.Name = name
+	cd.Namespace = namespace
+	cd.Spec.TargetRef.Name = name
+	cd.Status.Phase = phase
+	return cd
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// getReplicaSizer returns the replica sizer of the canary controller if the canary
+// replicas are sized to the traffic weight, the sizing applies only to the progressive
+// traffic increase strategy without mirroring
+func (c *Controller) getReplicaSizer(cd *flaggerv1.Canary, canaryController canary.Controller) (canary.ReplicaSizer, bool) {
+	if cd.Spec.ReplicaSizing == nil || cd.GetAnalysis().Iterations > 0 || cd.GetAnalysis().Mirror {
+		return nil, false
+	}
+
+	// the kubernetes provider defaults to blue/green
+	if cd.GetProviders(c.meshProvider)[0] == flaggerv1.KubernetesProvider {
+		return nil, false
+	}
+
+	sizer, ok := canaryController.(canary.ReplicaSizer)
+	return sizer, ok
+}
+
+// scaleCanaryFromZero scales up the canary at the start of the analysis,
+// with replica sizing the canary is sized for the first traffic step
+func (c *Controller) scaleCanaryFromZero(cd *flaggerv1.Canary, canaryController canary.Controller) error {
+	if sizer, ok := c.getReplicaSizer(cd, canaryController); ok {
+		return c.scaleCanaryToWeight(cd, sizer, c.upcomingWeight(cd, 0))
+	}
+	return canaryController.ScaleFromZero(cd)
+}
+
+// scaleCanaryToWeight sizes the canary for the traffic weight and sets the min and max replicas
+// of the canary autoscaler to the canary replicas, the autoscaler would override them otherwise
+func (c *Controller) scaleCanaryToWeight(cd *flaggerv1.Canary, sizer canary.ReplicaSizer, weight int) error {
+	replicas, err := sizer.ScaleToWeight(cd, weight, c.totalWeight(cd))
+	if err != nil {
+		return err
+	}
+	if cd.Spec.AutoscalerRef == nil {
+		return nil
+	}
+	if scalerReconciler := c.canaryFactory.ScalerReconciler(cd.Spec.AutoscalerRef.Kind); scalerReconciler != nil {
+		return scalerReconciler.SizeTargetScaler(cd, replicas)
+	}
+	return nil
+}
+
+// restoreCanaryReplicas restores the canary replicas declared by the user
+// that were overridden by the replica sizing
+func (c *Controller) restoreCanaryReplicas(cd *flaggerv1.Canary, canaryController canary.Controller) error {
+	if sizer, ok := canaryController.(canary.ReplicaSizer); ok {
+		return sizer.RestoreReplicas(cd)
+	}
+	return nil
+}
+
+// upcomingWeight returns the canary weight after the next traffic step,
+// the canary is sized ahead of the step so that its pods are ready when the traffic shifts
+func (c *Controller) upcomingWeight(cd *flaggerv1.Canary, canaryWeight int) int {
+	weight := canaryWeight + c.nextStepWeight(cd, canaryWeight)
+	if maxWeight := c.maxWeight(cd); weight > maxWeight {
+		weight = maxWeight
+	}
+	return weight
+}
+
+// preScalePrimary raises the primary autoscaler min replicas to the replicas needed to serve
+// the traffic routed back to the primary on promotion, it returns false until the primary replicas are available.
+// Pre-scaling is only supported for Deployment targets, the primary autoscaler of other targets is reconciled as usual.
+func (c *Controller) preScalePrimary(cd *flaggerv1.Canary, scalerReconciler canary.ScalerReconciler,
+	canaryWeight int, primaryWeight int) bool {
+	if cd.Spec.TargetRef.Kind != "Deployment" {
+		c.recordEventWarningf(cd, "Primary pre-scaling is not supported for %s %s.%s, skipping pre-scaling",
+			cd.Spec.TargetRef.Kind, cd.Spec.TargetRef.Name, cd.Namespace)
+		if err := scalerReconciler.ReconcilePrimaryScaler(cd, false); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+		return true
+	}
+
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	target, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		c.recordEventWarningf(cd, "deployment %s.%s get query error: %v", targetName, cd.Namespace, err)
+		return false
+	}
+	primary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		c.recordEventWarningf(cd, "deployment %s.%s get query error: %v", primaryName, cd.Namespace, err)
+		return false
+	}
+
+	var canaryReplicas, primaryReplicas int32
+	if target.Spec.Replicas != nil {
+		canaryReplicas = *target.Spec.Replicas
+	}
+	if primary.Spec.Replicas != nil {
+		primaryReplicas = *primary.Spec.Replicas
+	}
+
+	replicas := canaryReplicas
+	if cd.Spec.PrimaryPreScaling == flaggerv1.PrimaryPreScalingCapacity {
+		totalWeight := int32(c.totalWeight(cd))
+		if canaryWeight > 0 {
+			if r := ceilDiv(canaryReplicas*totalWeight, int32(canaryWeight)); r > replicas {
+				replicas = r
+			}
+		}
+		if primaryWeight > 0 {
+			if r := ceilDiv(primaryReplicas*totalWeight, int32(primaryWeight)); r > replicas {
+				replicas = r
+			}
+		}
+	}
+
+	min, err := scalerReconciler.PreScalePrimaryScaler(cd, replicas)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+
+	if primary.Status.AvailableReplicas < min {
+		deadline := time.Duration(cd.GetProgressDeadlineSeconds()) * time.Second
+		if time.Since(cd.Status.LastTransitionTime.Time) > deadline {
+			c.recordEventWarningf(cd, "Pre-scaling %s.%s deadline exceeded, routing traffic with %v of %v replicas available",
+				primaryName, cd.Namespace, primary.Status.AvailableReplicas, min)
+			return true
+		}
+		c.recordEventInfof(cd, "Waiting for %s.%s pre-scaling to finish: %v of %v replicas are available",
+			primaryName, cd.Namespace, primary.Status.AvailableReplicas, min)
+		return false
+	}
+	return true
+}
+
+func ceilDiv(a int32, b int32) int32 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestScheduler_ReplicaSizing(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.AutoscalerRef = nil
+	cd.Spec.ReplicaSizing = &flaggerv1.CanaryReplicaSizing{}
+	mocks := newUpdatedDeploymentFixture(t, cd)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Spec.Replicas = int32p(10)
+	primary.Status = appsv1.DeploymentStatus{Replicas: 10, UpdatedReplicas: 10, ReadyReplicas: 10, AvailableReplicas: 10}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	dep.Spec.Replicas = int32p(10)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the canary is sized for the first step
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+	assert.Equal(t, int32(1), getReplicaSizingTestReplicas(t, mocks))
+
+	// the canary is sized for the next step after each traffic increase
+	for _, replicas := range []int32{2, 3, 4, 5, 5} {
+		makeReplicaSizingTestReady(t, mocks)
+		mocks.ctrl.advanceCanary("podinfo", "default")
+		assert.Equal(t, replicas, getReplicaSizingTestReplicas(t, mocks))
+	}
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 50, c.Status.CanaryWeight)
+
+	// the declared replicas are restored on promotion and copied to the primary
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	dep, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), *dep.Spec.Replicas)
+	assert.NotContains(t, dep.Annotations, flaggerv1.ReplicasAnnotation)
+
+	makeReplicaSizingTestReady(t, mocks)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFinalising))
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
+
+	primary, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), *primary.Spec.Replicas)
+	assert.NotContains(t, primary.Annotations, flaggerv1.ReplicasAnnotation)
+}
+
+func TestScheduler_ReplicaSizingAutoscaler(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.ReplicaSizing = &flaggerv1.CanaryReplicaSizing{}
+	mocks := newUpdatedDeploymentFixture(t, cd)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Spec.Replicas = int32p(10)
+	primary.Status = appsv1.DeploymentStatus{Replicas: 10, UpdatedReplicas: 10, ReadyReplicas: 10, AvailableReplicas: 10}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	hpa, err := mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	min, max := hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas
+
+	// the canary autoscaler bounds follow the canary replicas
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+	for _, replicas := range []int32{2, 3, 4, 5, 5} {
+		makeReplicaSizingTestReady(t, mocks)
+		mocks.ctrl.advanceCanary("podinfo", "default")
+
+		hpa, err = mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, replicas, *hpa.Spec.MinReplicas)
+		assert.Equal(t, replicas, hpa.Spec.MaxReplicas)
+	}
+
+	// the bounds are restored on promotion and copied to the primary autoscaler
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	hpa, err = mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, min, hpa.Spec.MinReplicas)
+	assert.Equal(t, max, hpa.Spec.MaxReplicas)
+	assert.NotContains(t, hpa.Annotations, flaggerv1.ScalerBoundsAnnotation)
+
+	primaryHPA, err := mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, max, primaryHPA.Spec.MaxReplicas)
+}
+
+func TestScheduler_PrimaryPreScaling(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.PrimaryPreScaling = flaggerv1.PrimaryPreScalingCanary
+	mocks := newUpdatedDeploymentFixture(t, cd)
+
+	primaryHPA, err := mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primaryHPA.Spec.MaxReplicas = 10
+	_, err = mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Update(context.TODO(), primaryHPA, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	dep.Spec.Replicas = int32p(3)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	makeReplicaSizingTestReady(t, mocks)
+
+	// promote
+	err = mocks.router.SetRoutes(mocks.canary, 50, 50, false)
+	require.NoError(t, err)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	// the traffic is kept on the canary until the primary is scaled up
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	primaryHPA, err = mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *primaryHPA.Spec.MinReplicas)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Spec.Replicas = int32p(3)
+	primary.Status = appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// finalise
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFinalising))
+
+	// the primary min replicas are restored
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
+
+	primaryHPA, err = mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, primaryHPA.Spec.MinReplicas)
+}
+
+func getReplicaSizingTestReplicas(t *testing.T, mocks fixture) int32 {
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	return *dep.Spec.Replicas
+}
+
+// makeReplicaSizingTestReady marks all the canary replicas as ready
+func makeReplicaSizingTestReady(t *testing.T, mocks fixture) {
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	replicas := *dep.Spec.Replicas
+	dep.Status = appsv1.DeploymentStatus{Replicas: replicas, UpdatedReplicas: replicas,
+		ReadyReplicas: replicas, AvailableReplicas: replicas}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestScheduler_PrimaryPreScalingUnsupportedTarget(t *testing.T) {
+	mocks := newUpdatedDeploymentFixture(t, nil)
+	cd := mocks.canary.DeepCopy()
+	cd.Spec.TargetRef.Kind = "DaemonSet"
+	cd.Spec.PrimaryPreScaling = flaggerv1.PrimaryPreScalingCanary
+
+	hpa, err := mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the primary autoscaler is reconciled from the canary autoscaler without pre-scaling
+	scalerReconciler := mocks.ctrl.canaryFactory.ScalerReconciler(cd.Spec.AutoscalerRef.Kind)
+	assert.True(t, mocks.ctrl.preScalePrimary(cd, scalerReconciler, 50, 50))
+
+	primaryHPA, err := mocks.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, hpa.Spec.MinReplicas, primaryHPA.Spec.MinReplicas)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// albQueries are CloudWatch GetMetricData queries for the canary target group,
+// the target group is selected with a search on the name generated by the AWS Load Balancer Controller
+var albQueries = map[string]string{
+	"request-success-rate": `[
+	{
+		"Id": "errors",
+		"Expression": "SUM(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=\"HTTPCode_Target_5XX_Count\" %[1]s', 'Sum', %[2]d))",
+		"ReturnData": false
+	},
+	{
+		"Id": "requests",
+		"Expression": "SUM(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=\"RequestCount\" %[1]s', 'Sum', %[2]d))",
+		"ReturnData": false
+	},
+	{
+		"Id": "result",
+		"Expression": "100 - 100 * FILL(errors, 0) / requests",
+		"ReturnData": true
+	}
+]`,
+	"request-rate": `[
+	{
+		"Id": "result",
+		"Expression": "SUM(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=\"RequestCount\" %[1]s', 'Sum', %[2]d)) / %[2]d",
+		"ReturnData": true
+	}
+]`,
+	"error-rate": `[
+	{
+		"Id": "result",
+		"Expression": "SUM(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=\"HTTPCode_Target_5XX_Count\" %[1]s', 'Sum', %[2]d)) / %[2]d",
+		"ReturnData": true
+	}
+]`,
+	"request-duration": `[
+	{
+		"Id": "result",
+		"Expression": "MAX(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=\"TargetResponseTime\" %[1]s', 'p99', %[2]d)) * 1000",
+		"ReturnData": true
+	}
+]`,
+}
+
+var albInvalidTargetGroupName = regexp.MustCompile("[[:^alnum:]]")
+
+type ALBObserver struct {
+	// newClient returns a CloudWatch client for the metric interval
+	newClient func(interval string) (providers.Interface, error)
+}
+
+func (ob *ALBObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error) {
+	query, err := ob.renderQuery(albQueries["request-success-rate"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	client, err := ob.newClient(model.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("creating CloudWatch client failed: %w", err)
+	}
+
+	value, err := client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetRequestRates returns the request counts of the canary target group divided by the metric period
+func (ob *ALBObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	query, err := ob.renderQuery(albQueries["request-rate"], model)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	client, err := ob.newClient(model.Interval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("creating CloudWatch client failed: %w", err)
+	}
+
+	total, err := client.RunQuery(query)
+	if err != nil {
+		return 0, 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	query, err = ob.renderQuery(albQueries["error-rate"], model)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	// the 5XX metric is published only when a target returns an error
+	failed, err := client.RunQuery(query)
+	if err != nil && !errors.Is(err, providers.ErrNoValuesFound) {
+		return 0, 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	return total, failed, nil
+}
+
+func (ob *ALBObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	query, err := ob.renderQuery(albQueries["request-duration"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	client, err := ob.newClient(model.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("creating CloudWatch client failed: %w", err)
+	}
+
+	value, err := client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	ms := time.Duration(int64(value)) * time.Millisecond
+	return ms, nil
+}
+
+// renderQuery sets the canary target group name prefix and the metric period in seconds,
+// CloudWatch periods must be a multiple of 60
+func (ob *ALBObserver) renderQuery(query string, model flaggerv1.MetricTemplateModel) (string, error) {
+	interval, err := time.ParseDuration(model.Interval)
+	if err != nil {
+		return "", fmt.Errorf("error parsing interval: %w", err)
+	}
+	period := int(interval.Minutes()) * 60
+	if period < 60 {
+		period = 60
+	}
+
+	return fmt.Sprintf(query, albTargetGroupPrefix(model.Namespace, fmt.Sprintf("%s-canary", model.Service)), period), nil
+}
+
+// albTargetGroupPrefix returns the prefix of the target group name
+// generated by the AWS Load Balancer Controller for a Kubernetes service
+func albTargetGroupPrefix(namespace string, service string) string {
+	return fmt.Sprintf("k8s-%.8s-%.8s",
+		albInvalidTargetGroupName.ReplaceAllString(namespace, ""),
+		albInvalidTargetGroupName.ReplaceAllString(service, ""))
+}
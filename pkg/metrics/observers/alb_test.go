@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+type fakeALBClient struct {
+	query    string
+	value    float64
+	interval string
+}
+
+func (c *fakeALBClient) newClient(interval string) (providers.Interface, error) {
+	c.interval = interval
+	return c, nil
+}
+
+func (c *fakeALBClient) RunQuery(query string) (float64, error) {
+	c.query = query
+	return c.value, nil
+}
+
+func (c *fakeALBClient) IsOnline() (bool, error) {
+	return true, nil
+}
+
+func TestALBObserver_GetRequestSuccessRate(t *testing.T) {
+	client := &fakeALBClient{value: 99}
+	observer := &ALBObserver{
+		newClient: client.newClient,
+	}
+
+	val, err := observer.GetRequestSuccessRate(flaggerv1.MetricTemplateModel{
+		Name:      "podinfo",
+		Namespace: "test-ns",
+		Target:    "podinfo",
+		Service:   "podinfo",
+		Interval:  "2m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, float64(99), val)
+	assert.Equal(t, "2m", client.interval)
+
+	var queries []*cloudwatch.MetricDataQuery
+	require.NoError(t, json.Unmarshal([]byte(client.query), &queries))
+	require.Len(t, queries, 3)
+	assert.Equal(t, `SUM(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName="HTTPCode_Target_5XX_Count" k8s-testns-podinfoc', 'Sum', 120))`, *queries[0].Expression)
+	assert.Equal(t, "result", *queries[2].Id)
+	assert.True(t, *queries[2].ReturnData)
+}
+
+func TestALBObserver_GetRequestDuration(t *testing.T) {
+	client := &fakeALBClient{value: 100}
+	observer := &ALBObserver{
+		newClient: client.newClient,
+	}
+
+	val, err := observer.GetRequestDuration(flaggerv1.MetricTemplateModel{
+		Name:      "podinfo",
+		Namespace: "default",
+		Target:    "podinfo",
+		Service:   "podinfo",
+		Interval:  "30s",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, val)
+
+	var queries []*cloudwatch.MetricDataQuery
+	require.NoError(t, json.Unmarshal([]byte(client.query), &queries))
+	require.Len(t, queries, 1)
+	assert.Equal(t, `MAX(SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName="TargetResponseTime" k8s-default-podinfoc', 'p99', 60)) * 1000`, *queries[0].Expression)
+}
+
+func TestFactory_ALBObserver(t *testing.T) {
+	factory, err := NewFactory("cloudwatch://us-west-2")
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", factory.Region)
+
+	// the CloudWatch client is created for the metric interval
+	observer := factory.Observer(flaggerv1.ALBProvider).(*ALBObserver)
+	client, err := observer.newClient("5m")
+	require.NoError(t, err)
+	assert.IsType(t, &providers.CloudWatchProvider{}, client)
+	_, err = observer.newClient("5")
+	assert.Error(t, err)
+
+	// the region is required when the metrics server is Prometheus
+	t.Setenv("AWS_REGION", "")
+	factory, err = NewFactory("http://prometheus:9090")
+	require.NoError(t, err)
+	observer = factory.Observer(flaggerv1.ALBProvider).(*ALBObserver)
+	_, err = observer.newClient("1m")
+	assert.Error(t, err)
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	factory, err = NewFactory("http://prometheus:9090")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", factory.Region)
+}
@@ -42,6 +42,23 @@ var apisixQueries = map[string]string{
 			}[{{ interval }}]
 		)
 	) * 100`,
+	"request-rate": `
+	sum(
+		rate(
+			apisix_http_status{
+				route=~"{{ namespace }}_{{ route }}-{{ target }}-canary_.+"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			apisix_http_status{
+				route=~"{{ namespace }}_{{ route }}-{{ target }}-canary_.+",
+				code!~"5.."
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99, 
@@ -73,6 +90,10 @@ func (ob *ApisixObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateMo
 	return value, nil
 }
 
+func (ob *ApisixObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, apisixQueries, model)
+}
+
 func (ob *ApisixObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(apisixQueries["request-duration"], model)
 	if err != nil {
@@ -45,6 +45,25 @@ var appMeshQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				kubernetes_namespace="{{ namespace }}",
+				kubernetes_pod_name=~"{{ target }}-[0-9a-zA-Z]+(-[0-9a-zA-Z]+)"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				kubernetes_namespace="{{ namespace }}",
+				kubernetes_pod_name=~"{{ target }}-[0-9a-zA-Z]+(-[0-9a-zA-Z]+)",
+				envoy_response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -77,6 +96,10 @@ func (ob *AppMeshObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateM
 	return value, nil
 }
 
+func (ob *AppMeshObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, appMeshQueries, model)
+}
+
 func (ob *AppMeshObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(appMeshQueries["request-duration"], model)
 	if err != nil {
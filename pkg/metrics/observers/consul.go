@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"fmt"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// consulQueries select the upstream requests sent by the Envoy sidecars
+// to the canary subset of the service defined by the ServiceResolver
+var consulQueries = map[string]string{
+	"request-success-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				consul_destination_service="{{ service }}",
+				consul_destination_service_subset="canary",
+				envoy_response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)
+	/
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				consul_destination_service="{{ service }}",
+				consul_destination_service_subset="canary"
+			}[{{ interval }}]
+		)
+	)
+	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				consul_destination_service="{{ service }}",
+				consul_destination_service_subset="canary"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				consul_destination_service="{{ service }}",
+				consul_destination_service_subset="canary",
+				envoy_response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
+	"request-duration": `
+	histogram_quantile(
+		0.99,
+		sum(
+			rate(
+				envoy_cluster_upstream_rq_time_bucket{
+					consul_destination_service="{{ service }}",
+					consul_destination_service_subset="canary"
+				}[{{ interval }}]
+			)
+		) by (le)
+	)`,
+}
+
+type ConsulObserver struct {
+	client providers.Interface
+}
+
+func (ob *ConsulObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error) {
+	query, err := RenderQuery(consulQueries["request-success-rate"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	return value, nil
+}
+
+func (ob *ConsulObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, consulQueries, model)
+}
+
+func (ob *ConsulObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	query, err := RenderQuery(consulQueries["request-duration"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	ms := time.Duration(int64(value)) * time.Millisecond
+	return ms, nil
+}
@@ -45,6 +45,23 @@ var contourQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				envoy_cluster_name=~"{{ namespace }}_{{ service }}-canary_[0-9a-zA-Z-]+",
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				envoy_cluster_name=~"{{ namespace }}_{{ service }}-canary_[0-9a-zA-Z-]+",
+				envoy_response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -76,6 +93,10 @@ func (ob *ContourObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateM
 	return value, nil
 }
 
+func (ob *ContourObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, contourQueries, model)
+}
+
 func (ob *ContourObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(contourQueries["request-duration"], model)
 	if err != nil {
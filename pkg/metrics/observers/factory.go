@@ -17,17 +17,40 @@ limitations under the License.
 package observers
 
 import (
+	"fmt"
+	"os"
 	"strings"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 	"github.com/fluxcd/flagger/pkg/metrics/providers"
 )
 
+const cloudWatchScheme = "cloudwatch://"
+
 type Factory struct {
 	Client providers.Interface
+	// Region is the AWS region of the CloudWatch metrics used by the ALB observer
+	Region string
 }
 
 func NewFactory(metricsServer string) (*Factory, error) {
+	// the cloudwatch://<region> address selects the AWS CloudWatch metrics
+	if strings.HasPrefix(metricsServer, cloudWatchScheme) {
+		region := strings.TrimPrefix(metricsServer, cloudWatchScheme)
+		client, err := providers.NewCloudWatchProvider("1m", flaggerv1.MetricTemplateProvider{
+			Type:   "cloudwatch",
+			Region: region,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &Factory{
+			Client: client,
+			Region: region,
+		}, nil
+	}
+
 	client, err := providers.NewPrometheusProvider(flaggerv1.MetricTemplateProvider{
 		Type:      "prometheus",
 		Address:   metricsServer,
@@ -39,16 +62,35 @@ func NewFactory(metricsServer string) (*Factory, error) {
 
 	return &Factory{
 		Client: client,
+		Region: os.Getenv("AWS_REGION"),
 	}, nil
 }
 
+// Observers returns an observer that combines the builtin metrics of the given providers
+func (factory Factory) Observers(providers []string) Interface {
+	if len(providers) == 1 {
+		return factory.Observer(providers[0])
+	}
+
+	seen := make(map[string]bool)
+	multi := &MultiObserver{}
+	for _, provider := range providers {
+		if seen[provider] {
+			continue
+		}
+		seen[provider] = true
+		multi.observers = append(multi.observers, factory.Observer(provider))
+	}
+	return multi
+}
+
 func (factory Factory) Observer(provider string) Interface {
 	switch {
 	case strings.HasPrefix(provider, flaggerv1.AppMeshProvider):
 		return &AppMeshObserver{
 			client: factory.Client,
 		}
-	case provider == flaggerv1.LinkerdProvider:
+	case strings.HasPrefix(provider, flaggerv1.LinkerdProvider):
 		return &LinkerdObserver{
 			client: factory.Client,
 		}
@@ -76,7 +118,7 @@ func (factory Factory) Observer(provider string) Interface {
 		return &SkipperObserver{
 			client: factory.Client,
 		}
-	case provider == flaggerv1.TraefikProvider:
+	case strings.HasPrefix(provider, flaggerv1.TraefikProvider):
 		return &TraefikObserver{
 			client: factory.Client,
 		}
@@ -84,17 +126,54 @@ func (factory Factory) Observer(provider string) Interface {
 		return &OsmObserver{
 			client: factory.Client,
 		}
-	case provider == flaggerv1.KumaProvider:
+	case strings.HasPrefix(provider, flaggerv1.KumaProvider):
 		return &KumaObserver{
 			client: factory.Client,
 		}
+	case provider == flaggerv1.ConsulProvider:
+		return &ConsulObserver{
+			client: factory.Client,
+		}
+	case provider == flaggerv1.ALBProvider:
+		return &ALBObserver{
+			newClient: factory.cloudWatchClient,
+		}
+	case provider == flaggerv1.KongProvider:
+		return &KongObserver{
+			client: factory.Client,
+		}
+	case provider == flaggerv1.HAProxyProvider:
+		return &HAProxyObserver{
+			client: factory.Client,
+		}
 	case provider == flaggerv1.ApisixProvider:
 		return &ApisixObserver{
 			client: factory.Client,
 		}
+	case provider == flaggerv1.KnativeProvider:
+		return &KnativeObserver{
+			client: factory.Client,
+		}
 	default:
 		return &IstioObserver{
 			client: factory.Client,
 		}
 	}
 }
+
+// cloudWatchClient returns a CloudWatch client for the factory region
+// that queries the metrics of the given interval
+func (factory Factory) cloudWatchClient(interval string) (providers.Interface, error) {
+	if factory.Region == "" {
+		return nil, fmt.Errorf("AWS region not specified, set the metrics server to %s<region> or the AWS_REGION env var", cloudWatchScheme)
+	}
+
+	client, err := providers.NewCloudWatchProvider(interval, flaggerv1.MetricTemplateProvider{
+		Type:   "cloudwatch",
+		Region: factory.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
@@ -45,6 +45,23 @@ var glooQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				envoy_cluster_name=~"{{ namespace }}-{{ target }}-canaryupstream-[0-9a-zA-Z-]+_[0-9a-zA-Z-]+",
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			envoy_cluster_upstream_rq{
+				envoy_cluster_name=~"{{ namespace }}-{{ target }}-canaryupstream-[0-9a-zA-Z-]+_[0-9a-zA-Z-]+",
+				envoy_response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -76,6 +93,10 @@ func (ob *GlooObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateMode
 	return value, nil
 }
 
+func (ob *GlooObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, glooQueries, model)
+}
+
 func (ob *GlooObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(glooQueries["request-duration"], model)
 	if err != nil {
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"fmt"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// haproxyQueries are using the HAProxy Prometheus exporter server metrics,
+// the servers are named after the pods so the canary pods are matched by the deployment name
+var haproxyQueries = map[string]string{
+	"request-success-rate": `
+	sum(
+		rate(
+			haproxy_server_http_responses_total{
+				proxy=~"{{ namespace }}_.+",
+				server=~"{{ target }}-[a-z0-9]+-[a-z0-9]+",
+				code!="5xx"
+			}[{{ interval }}]
+		)
+	)
+	/
+	sum(
+		rate(
+			haproxy_server_http_responses_total{
+				proxy=~"{{ namespace }}_.+",
+				server=~"{{ target }}-[a-z0-9]+-[a-z0-9]+"
+			}[{{ interval }}]
+		)
+	)
+	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			haproxy_server_http_responses_total{
+				proxy=~"{{ namespace }}_.+",
+				server=~"{{ target }}-[a-z0-9]+-[a-z0-9]+"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			haproxy_server_http_responses_total{
+				proxy=~"{{ namespace }}_.+",
+				server=~"{{ target }}-[a-z0-9]+-[a-z0-9]+",
+				code!="5xx"
+			}[{{ interval }}]
+		)
+	)`,
+	"request-duration": `
+	avg(
+		haproxy_server_total_time_average_seconds{
+			proxy=~"{{ namespace }}_.+",
+			server=~"{{ target }}-[a-z0-9]+-[a-z0-9]+"
+		}
+	)
+	* 1000`,
+}
+
+// HAProxyObserver Implementation for HAProxy Ingress (https://github.com/jcmoraisjr/haproxy-ingress)
+type HAProxyObserver struct {
+	client providers.Interface
+}
+
+func (ob *HAProxyObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error) {
+	query, err := RenderQuery(haproxyQueries["request-success-rate"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	return value, nil
+}
+
+func (ob *HAProxyObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, haproxyQueries, model)
+}
+
+func (ob *HAProxyObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	query, err := RenderQuery(haproxyQueries["request-duration"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	ms := time.Duration(int64(value)) * time.Millisecond
+	return ms, nil
+}
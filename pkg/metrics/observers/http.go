@@ -45,6 +45,25 @@ var httpQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			http_request_duration_seconds_count{
+				kubernetes_namespace="{{ namespace }}",
+				kubernetes_pod_name=~"{{ target }}-[0-9a-zA-Z]+(-[0-9a-zA-Z]+)"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			http_request_duration_seconds_count{
+				kubernetes_namespace="{{ namespace }}",
+				kubernetes_pod_name=~"{{ target }}-[0-9a-zA-Z]+(-[0-9a-zA-Z]+)",
+				status!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -77,6 +96,10 @@ func (ob *HttpObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateMode
 	return value, nil
 }
 
+func (ob *HttpObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, httpQueries, model)
+}
+
 func (ob *HttpObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(httpQueries["request-duration"], model)
 	if err != nil {
@@ -47,6 +47,27 @@ var istioQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			istio_requests_total{
+				reporter="destination",
+				destination_workload_namespace="{{ namespace }}",
+				destination_workload=~"{{ target }}"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			istio_requests_total{
+				reporter="destination",
+				destination_workload_namespace="{{ namespace }}",
+				destination_workload=~"{{ target }}",
+				response_code!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -80,6 +101,10 @@ func (ob *IstioObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateMod
 	return value, nil
 }
 
+func (ob *IstioObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, istioQueries, model)
+}
+
 func (ob *IstioObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(istioQueries["request-duration"], model)
 	if err != nil {
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"fmt"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// knativeQueries select the requests proxied by the queue-proxy containers
+// of the canary revision of a Knative service
+var knativeQueries = map[string]string{
+	"request-success-rate": `
+	sum(
+		rate(
+			revision_request_count{
+				namespace_name="{{ namespace }}",
+				revision_name="{{ revision }}",
+				response_code_class!="5xx"
+			}[{{ interval }}]
+		)
+	)
+	/
+	sum(
+		rate(
+			revision_request_count{
+				namespace_name="{{ namespace }}",
+				revision_name="{{ revision }}"
+			}[{{ interval }}]
+		)
+	)
+	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			revision_request_count{
+				namespace_name="{{ namespace }}",
+				revision_name="{{ revision }}"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			revision_request_count{
+				namespace_name="{{ namespace }}",
+				revision_name="{{ revision }}",
+				response_code_class!="5xx"
+			}[{{ interval }}]
+		)
+	)`,
+	"request-duration": `
+	histogram_quantile(
+		0.99,
+		sum(
+			rate(
+				revision_request_latencies_bucket{
+					namespace_name="{{ namespace }}",
+					revision_name="{{ revision }}"
+				}[{{ interval }}]
+			)
+		) by (le)
+	)`,
+}
+
+type KnativeObserver struct {
+	client providers.Interface
+}
+
+func (ob *KnativeObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error) {
+	query, err := RenderQuery(knativeQueries["request-success-rate"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	return value, nil
+}
+
+func (ob *KnativeObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, knativeQueries, model)
+}
+
+func (ob *KnativeObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	query, err := RenderQuery(knativeQueries["request-duration"], model)
+	if err != nil {
+		return 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	value, err := ob.client.RunQuery(query)
+	if err != nil {
+		return 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	ms := time.Duration(int64(value)) * time.Millisecond
+	return ms, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"errors"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// ErrKongBuiltinMetrics is returned for the builtin checks of the Kong provider, the Kong Prometheus plugin
+// reports the requests of the Kong service generated for an HTTPRoute rule and the weighted backends
+// of the rule share that service, so the canary requests can't be told apart from the primary ones
+var ErrKongBuiltinMetrics = errors.New("the builtin request-success-rate and request-duration checks are not supported for Kong, " +
+	"the Kong metrics don't separate the canary requests from the primary ones, use a metric template based on the application metrics")
+
+// KongObserver Implementation for Kong (https://github.com/Kong/kubernetes-ingress-controller)
+type KongObserver struct {
+	client providers.Interface
+}
+
+func (ob *KongObserver) GetRequestSuccessRate(_ flaggerv1.MetricTemplateModel) (float64, error) {
+	return 0, ErrKongBuiltinMetrics
+}
+
+func (ob *KongObserver) GetRequestRates(_ flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return 0, 0, ErrKongBuiltinMetrics
+}
+
+func (ob *KongObserver) GetRequestDuration(_ flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	return 0, ErrKongBuiltinMetrics
+}
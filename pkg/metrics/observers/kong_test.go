@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestKongObserver_BuiltinMetrics(t *testing.T) {
+	observer := &KongObserver{}
+	model := flaggerv1.MetricTemplateModel{
+		Name:      "podinfo",
+		Namespace: "default",
+		Target:    "podinfo",
+		Service:   "podinfo",
+		Interval:  "1m",
+	}
+
+	// the canary requests can't be told apart from the primary ones
+	_, err := observer.GetRequestSuccessRate(model)
+	assert.ErrorIs(t, err, ErrKongBuiltinMetrics)
+	_, _, err = observer.GetRequestRates(model)
+	assert.ErrorIs(t, err, ErrKongBuiltinMetrics)
+	_, err = observer.GetRequestDuration(model)
+	assert.ErrorIs(t, err, ErrKongBuiltinMetrics)
+}
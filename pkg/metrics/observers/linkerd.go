@@ -47,6 +47,27 @@ var linkerdQueries = map[string]string{
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			response_total{
+				namespace="{{ namespace }}",
+				deployment=~"{{ target }}",
+				direction="inbound"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			response_total{
+				namespace="{{ namespace }}",
+				deployment=~"{{ target }}",
+				classification!="failure",
+				direction="inbound"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -80,6 +101,10 @@ func (ob *LinkerdObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateM
 	return value, nil
 }
 
+func (ob *LinkerdObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, linkerdQueries, model)
+}
+
 func (ob *LinkerdObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(linkerdQueries["request-duration"], model)
 	if err != nil {
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// MultiObserver combines the builtin metrics of several providers,
+// the success rate is computed from the request rates added up across the providers,
+// the durations are 99th percentiles that can't be merged so the highest one is returned
+type MultiObserver struct {
+	observers []Interface
+}
+
+// GetRequestSuccessRate returns the success rate of the requests seen by the observers that found values
+func (ob *MultiObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error) {
+	var total, failed float64
+	found := false
+	for _, observer := range ob.observers {
+		rates, ok := observer.(RequestRateObserver)
+		if !ok {
+			return 0, fmt.Errorf("observer %T doesn't support request rates", observer)
+		}
+		t, f, err := rates.GetRequestRates(model)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				continue
+			}
+			return 0, err
+		}
+		total += t
+		failed += f
+		found = true
+	}
+
+	if !found || total <= 0 {
+		return 0, providers.ErrNoValuesFound
+	}
+	return (total - failed) / total * 100, nil
+}
+
+// GetRequestDuration returns the highest duration of the observers that found values
+func (ob *MultiObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	var result time.Duration
+	found := false
+	for _, observer := range ob.observers {
+		val, err := observer.GetRequestDuration(model)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				continue
+			}
+			return 0, err
+		}
+		if !found || val > result {
+			result = val
+		}
+		found = true
+	}
+
+	if !found {
+		return 0, providers.ErrNoValuesFound
+	}
+	return result, nil
+}
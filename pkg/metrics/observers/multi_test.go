@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+type fakeObserver struct {
+	successRate float64
+	total       float64
+	failed      float64
+	duration    time.Duration
+	err         error
+}
+
+func (ob *fakeObserver) GetRequestSuccessRate(_ flaggerv1.MetricTemplateModel) (float64, error) {
+	return ob.successRate, ob.err
+}
+
+func (ob *fakeObserver) GetRequestRates(_ flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return ob.total, ob.failed, ob.err
+}
+
+func (ob *fakeObserver) GetRequestDuration(_ flaggerv1.MetricTemplateModel) (time.Duration, error) {
+	return ob.duration, ob.err
+}
+
+func TestMultiObserver(t *testing.T) {
+	observer := &MultiObserver{
+		observers: []Interface{
+			&fakeObserver{total: 300, failed: 3, duration: 100 * time.Millisecond},
+			&fakeObserver{total: 100, failed: 5, duration: 50 * time.Millisecond},
+			&fakeObserver{err: fmt.Errorf("running query failed: %w", providers.ErrNoValuesFound)},
+		},
+	}
+
+	// the success rate is computed from the requests of all the observers
+	val, err := observer.GetRequestSuccessRate(flaggerv1.MetricTemplateModel{})
+	require.NoError(t, err)
+	assert.Equal(t, float64(98), val)
+
+	duration, err := observer.GetRequestDuration(flaggerv1.MetricTemplateModel{})
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, duration)
+
+	// no values found by any of the observers
+	observer.observers = observer.observers[2:]
+	_, err = observer.GetRequestSuccessRate(flaggerv1.MetricTemplateModel{})
+	assert.ErrorIs(t, err, providers.ErrNoValuesFound)
+
+	// query errors are returned
+	observer.observers = []Interface{&fakeObserver{total: 100}, &fakeObserver{err: fmt.Errorf("bad query")}}
+	_, err = observer.GetRequestSuccessRate(flaggerv1.MetricTemplateModel{})
+	assert.EqualError(t, err, "bad query")
+}
@@ -30,7 +30,7 @@ var nginxQueries = map[string]string{
 		rate(
 			nginx_ingress_controller_requests{
 				namespace="{{ namespace }}",
-				ingress="{{ ingress }}",
+				ingress=~"{{ ingresses }}",
 				canary!="",
 				status!~"5.*"
 			}[{{ interval }}]
@@ -41,18 +41,39 @@ var nginxQueries = map[string]string{
 		rate(
 			nginx_ingress_controller_requests{
 				namespace="{{ namespace }}",
-				ingress="{{ ingress }}",
+				ingress=~"{{ ingresses }}",
 				canary!=""
 			}[{{ interval }}]
 		)
 	) 
 	* 100`,
+	"request-rate": `
+	sum(
+		rate(
+			nginx_ingress_controller_requests{
+				namespace="{{ namespace }}",
+				ingress=~"{{ ingresses }}",
+				canary!=""
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			nginx_ingress_controller_requests{
+				namespace="{{ namespace }}",
+				ingress=~"{{ ingresses }}",
+				canary!="",
+				status!~"5.*"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	sum(
 		rate(
 			nginx_ingress_controller_ingress_upstream_latency_seconds_sum{
 				namespace="{{ namespace }}",
-				ingress="{{ ingress }}",
+				ingress=~"{{ ingresses }}",
 				canary!=""
 			}[{{ interval }}]
 		)
@@ -62,7 +83,7 @@ var nginxQueries = map[string]string{
 		rate(
 			nginx_ingress_controller_ingress_upstream_latency_seconds_count{
 				namespace="{{ namespace }}",
-				ingress="{{ ingress }}",
+				ingress=~"{{ ingresses }}",
 				canary!=""
 			}[{{ interval }}]
 		)
@@ -88,6 +109,10 @@ func (ob *NginxObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateMod
 	return value, nil
 }
 
+func (ob *NginxObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, nginxQueries, model)
+}
+
 func (ob *NginxObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(nginxQueries["request-duration"], model)
 	if err != nil {
@@ -32,7 +32,7 @@ import (
 
 func TestNginxObserver_GetRequestSuccessRate(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
-		expected := ` sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress="podinfo", canary!="", status!~"5.*" }[1m] ) ) / sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress="podinfo", canary!="" }[1m] ) ) * 100`
+		expected := ` sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress=~"podinfo", canary!="", status!~"5.*" }[1m] ) ) / sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress=~"podinfo", canary!="" }[1m] ) ) * 100`
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			promql := r.URL.Query()["query"][0]
 			assert.Equal(t, expected, promql)
@@ -65,6 +65,41 @@ func TestNginxObserver_GetRequestSuccessRate(t *testing.T) {
 		assert.Equal(t, float64(100), val)
 	})
 
+	t.Run("multiple ingresses", func(t *testing.T) {
+		expected := ` sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress=~"podinfo\\.public|internal", canary!="", status!~"5.*" }[1m] ) ) / sum( rate( nginx_ingress_controller_requests{ namespace="nginx", ingress=~"podinfo\\.public|internal", canary!="" }[1m] ) ) * 100`
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			promql := r.URL.Query()["query"][0]
+			assert.Equal(t, expected, promql)
+
+			json := `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"100"]}]}}`
+			w.Write([]byte(json))
+		}))
+		defer ts.Close()
+
+		client, err := providers.NewPrometheusProvider(flaggerv1.MetricTemplateProvider{
+			Type:      "prometheus",
+			Address:   ts.URL,
+			SecretRef: nil,
+		}, nil)
+		require.NoError(t, err)
+
+		observer := &NginxObserver{
+			client: client,
+		}
+
+		val, err := observer.GetRequestSuccessRate(flaggerv1.MetricTemplateModel{
+			Name:      "podinfo",
+			Namespace: "nginx",
+			Target:    "podinfo",
+			Ingress:   "podinfo.public",
+			Ingresses: []string{"podinfo.public", "internal"},
+			Interval:  "1m",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(100), val)
+	})
+
 	t.Run("no values", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			json := `{"status":"success","data":{"resultType":"vector","result":[]}}`
@@ -89,7 +124,7 @@ func TestNginxObserver_GetRequestSuccessRate(t *testing.T) {
 }
 
 func TestNginxObserver_GetRequestDuration(t *testing.T) {
-	expected := ` sum( rate( nginx_ingress_controller_ingress_upstream_latency_seconds_sum{ namespace="nginx", ingress="podinfo", canary!="" }[1m] ) ) / sum( rate( nginx_ingress_controller_ingress_upstream_latency_seconds_count{ namespace="nginx", ingress="podinfo", canary!="" }[1m] ) ) * 1000`
+	expected := ` sum( rate( nginx_ingress_controller_ingress_upstream_latency_seconds_sum{ namespace="nginx", ingress=~"podinfo", canary!="" }[1m] ) ) / sum( rate( nginx_ingress_controller_ingress_upstream_latency_seconds_count{ namespace="nginx", ingress=~"podinfo", canary!="" }[1m] ) ) * 1000`
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		promql := r.URL.Query()["query"][0]
@@ -26,3 +26,9 @@ type Interface interface {
 	GetRequestSuccessRate(model flaggerv1.MetricTemplateModel) (float64, error)
 	GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error)
 }
+
+// RequestRateObserver is implemented by the observers that can return the request rates
+// their success rate is computed from, in requests per second over the metric interval
+type RequestRateObserver interface {
+	GetRequestRates(model flaggerv1.MetricTemplateModel) (total float64, failed float64, err error)
+}
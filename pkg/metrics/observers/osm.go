@@ -47,6 +47,27 @@ var osmQueries = map[string]string{
         )
     )
 	* 100`,
+	"request-rate": `
+    sum(
+        rate(
+            osm_request_total{
+				destination_namespace="{{ namespace }}",
+				destination_kind="Deployment",
+				destination_name="{{ target }}"
+            }[{{ interval }}]
+        )
+    )`,
+	"successful-request-rate": `
+    sum(
+        rate(
+            osm_request_total{
+				destination_namespace="{{ namespace }}",
+				destination_kind="Deployment",
+				destination_name="{{ target }}",
+				response_code!~"5.*"
+            }[{{ interval }}]
+        )
+    )`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -80,6 +101,10 @@ func (ob *OsmObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateModel
 	return value, nil
 }
 
+func (ob *OsmObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, osmQueries, model)
+}
+
 func (ob *OsmObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(osmQueries["request-duration"], model)
 	if err != nil {
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"errors"
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// getRequestRates runs the request-rate and successful-request-rate queries of an observer
+// and returns the total and the failed request rates
+func getRequestRates(client providers.Interface, queries map[string]string, model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	totalQuery, err := RenderQuery(queries["request-rate"], model)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+	successQuery, err := RenderQuery(queries["successful-request-rate"], model)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rendering query failed: %w", err)
+	}
+
+	total, err := client.RunQuery(totalQuery)
+	if err != nil {
+		return 0, 0, fmt.Errorf("running query failed: %w", err)
+	}
+
+	success, err := client.RunQuery(successQuery)
+	if err != nil {
+		if !errors.Is(err, providers.ErrNoValuesFound) {
+			return 0, 0, fmt.Errorf("running query failed: %w", err)
+		}
+		// no successful requests were recorded
+		success = 0
+	}
+
+	failed := total - success
+	if failed < 0 {
+		failed = 0
+	}
+	return total, failed, nil
+}
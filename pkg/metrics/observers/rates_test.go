@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+func TestRequestRateQueries(t *testing.T) {
+	removeSpaces := func(s string) string {
+		return strings.Join(strings.Fields(s), "")
+	}
+
+	// the request rates are the two sides of the success rate
+	model := flaggerv1.MetricTemplateModel{Name: "podinfo", Namespace: "default", Target: "podinfo", Service: "podinfo", Interval: "1m"}
+	for name, queries := range map[string]map[string]string{
+		"apisix": apisixQueries, "appmesh": appMeshQueries, "consul": consulQueries, "contour": contourQueries,
+		"gloo": glooQueries, "haproxy": haproxyQueries, "http": httpQueries, "istio": istioQueries,
+		"knative": knativeQueries, "kuma": kumaQueries, "linkerd": linkerdQueries,
+		"nginx": nginxQueries, "osm": osmQueries, "skipper": skipperQueries, "traefik": traefikQueries,
+	} {
+		m := encodeModelForSkipper(model)
+		successRate, err := RenderQuery(queries["request-success-rate"], m)
+		require.NoError(t, err, name)
+		total, err := RenderQuery(queries["request-rate"], m)
+		require.NoError(t, err, name)
+		success, err := RenderQuery(queries["successful-request-rate"], m)
+		require.NoError(t, err, name)
+
+		assert.Equal(t, removeSpaces(successRate), removeSpaces(success+"/"+total+"*100"), name)
+	}
+}
+
+func TestGetRequestRates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		promql := r.URL.Query()["query"][0]
+		value := "200"
+		if strings.Contains(promql, "5..") {
+			value = "150"
+		}
+		json := `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"` + value + `"]}]}}`
+		w.Write([]byte(json))
+	}))
+	defer ts.Close()
+
+	client, err := providers.NewPrometheusProvider(flaggerv1.MetricTemplateProvider{
+		Type:    "prometheus",
+		Address: ts.URL,
+	}, nil)
+	require.NoError(t, err)
+
+	queries := map[string]string{
+		"request-rate":            `sum(rate(http_requests_total[{{ interval }}]))`,
+		"successful-request-rate": `sum(rate(http_requests_total{code!~"5.."}[{{ interval }}]))`,
+	}
+	total, failed, err := getRequestRates(client, queries, flaggerv1.MetricTemplateModel{Interval: "1m"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(200), total)
+	assert.Equal(t, float64(50), failed)
+}
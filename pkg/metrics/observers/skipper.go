@@ -33,6 +33,10 @@ var skipperQueries = map[string]string{
 	"request-success-rate": routePattern + `
 	sum(rate(skipper_response_duration_seconds_bucket{route=~"{{ $route }}",code!~"5..",le="+Inf"}[{{ interval }}])) / 
 	sum(rate(skipper_response_duration_seconds_bucket{route=~"{{ $route }}",le="+Inf"}[{{ interval }}])) * 100`,
+	"request-rate": routePattern + `
+	sum(rate(skipper_response_duration_seconds_bucket{route=~"{{ $route }}",le="+Inf"}[{{ interval }}]))`,
+	"successful-request-rate": routePattern + `
+	sum(rate(skipper_response_duration_seconds_bucket{route=~"{{ $route }}",code!~"5..",le="+Inf"}[{{ interval }}]))`,
 	"request-duration": routePattern + `
 	sum(rate(skipper_serve_route_duration_seconds_sum{route=~"{{ $route }}"}[{{ interval }}])) / 
 	sum(rate(skipper_serve_route_duration_seconds_count{route=~"{{ $route }}"}[{{ interval }}])) * 1000`,
@@ -63,6 +67,12 @@ func (ob *SkipperObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateM
 	return value, nil
 }
 
+func (ob *SkipperObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	model = encodeModelForSkipper(model)
+
+	return getRequestRates(ob.client, skipperQueries, model)
+}
+
 // GetRequestDuration return value for Skipper Request Duration
 func (ob *SkipperObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 
@@ -44,6 +44,25 @@ var traefikQueries = map[string]string{
 			}[{{ interval }}]
 		)
 	) * 100`,
+	"request-rate": `
+	sum(
+		rate(
+			traefik_service_request_duration_seconds_bucket{
+				service=~"{{ namespace }}-{{ target }}-canary-[0-9a-zA-Z-]+@kubernetescrd",
+				le="+Inf"
+			}[{{ interval }}]
+		)
+	)`,
+	"successful-request-rate": `
+	sum(
+		rate(
+			traefik_service_request_duration_seconds_bucket{
+				service=~"{{ namespace }}-{{ target }}-canary-[0-9a-zA-Z-]+@kubernetescrd",
+				code!~"5..",
+				le="+Inf"
+			}[{{ interval }}]
+		)
+	)`,
 	"request-duration": `
 	histogram_quantile(
 		0.99,
@@ -76,6 +95,10 @@ func (ob *TraefikObserver) GetRequestSuccessRate(model flaggerv1.MetricTemplateM
 	return value, nil
 }
 
+func (ob *TraefikObserver) GetRequestRates(model flaggerv1.MetricTemplateModel) (float64, float64, error) {
+	return getRequestRates(ob.client, traefikQueries, model)
+}
+
 func (ob *TraefikObserver) GetRequestDuration(model flaggerv1.MetricTemplateModel) (time.Duration, error) {
 	query, err := RenderQuery(traefikQueries["request-duration"], model)
 	if err != nil {
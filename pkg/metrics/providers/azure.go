@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// https://learn.microsoft.com/en-us/azure/active-directory/develop/v2-oauth2-client-creds-grant-flow
+const (
+	azureTenantIDSecretKey        = "azure_tenant_id"
+	azureClientIDSecretKey        = "azure_client_id"
+	azureClientSecretSecretKey    = "azure_client_secret"
+	azureFederatedTokenSecretKey  = "azure_federated_token"
+	azureCloudSecretKey           = "azure_cloud"
+	azureFederatedTokenFileEnvKey = "AZURE_FEDERATED_TOKEN_FILE"
+	azureTenantIDEnvKey           = "AZURE_TENANT_ID"
+	azureClientIDEnvKey           = "AZURE_CLIENT_ID"
+
+	azureClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// refresh the access token ahead of its expiration
+	azureTokenExpiryDelta = 60 * time.Second
+)
+
+// azureCloud holds the endpoints of an Azure cloud environment
+type azureCloud struct {
+	authorityHost        string
+	resourceManager      string
+	logAnalytics         string
+	resourceManagerScope string
+	logAnalyticsScope    string
+}
+
+var azureClouds = map[string]azureCloud{
+	"AzurePublicCloud": {
+		authorityHost:        "https://login.microsoftonline.com",
+		resourceManager:      "https://management.azure.com",
+		logAnalytics:         "https://api.loganalytics.io",
+		resourceManagerScope: "https://management.azure.com/.default",
+		logAnalyticsScope:    "https://api.loganalytics.io/.default",
+	},
+	"AzureUSGovernment": {
+		authorityHost:        "https://login.microsoftonline.us",
+		resourceManager:      "https://management.usgovcloudapi.net",
+		logAnalytics:         "https://api.loganalytics.us",
+		resourceManagerScope: "https://management.usgovcloudapi.net/.default",
+		logAnalyticsScope:    "https://api.loganalytics.us/.default",
+	},
+	"AzureChinaCloud": {
+		authorityHost:        "https://login.chinacloudapi.cn",
+		resourceManager:      "https://management.chinacloudapi.cn",
+		logAnalytics:         "https://api.loganalytics.azure.cn",
+		resourceManagerScope: "https://management.chinacloudapi.cn/.default",
+		logAnalyticsScope:    "https://api.loganalytics.azure.cn/.default",
+	},
+}
+
+// azureCredential acquires Azure AD access tokens with the client credentials flow,
+// using either a client secret or a workload identity federated token
+type azureCredential struct {
+	authorityHost  string
+	tenantID       string
+	clientID       string
+	clientSecret   string
+	federatedToken string
+	tokenFile      string
+	scope          string
+	timeout        time.Duration
+
+	token     string
+	expiresAt time.Time
+}
+
+type azureTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// getAzureCloud returns the cloud environment set in the credentials map,
+// defaulting to the Azure public cloud
+func getAzureCloud(credentials map[string][]byte) (azureCloud, error) {
+	name := "AzurePublicCloud"
+	if b, ok := credentials[azureCloudSecretKey]; ok && len(b) > 0 {
+		name = strings.TrimSpace(string(b))
+	}
+
+	cloud, ok := azureClouds[name]
+	if !ok {
+		return azureCloud{}, fmt.Errorf("azure cloud '%s' is not supported", name)
+	}
+	return cloud, nil
+}
+
+// newAzureCredential reads the tenant and client IDs along with a client secret or a federated token
+// from the credentials map. When the secret does not contain them, the environment variables injected
+// by the Azure workload identity webhook are used.
+func newAzureCredential(cloud azureCloud, scope string, credentials map[string][]byte) (*azureCredential, error) {
+	ac := &azureCredential{
+		authorityHost: cloud.authorityHost,
+		scope:         scope,
+		timeout:       5 * time.Second,
+		tenantID:      os.Getenv(azureTenantIDEnvKey),
+		clientID:      os.Getenv(azureClientIDEnvKey),
+		tokenFile:     os.Getenv(azureFederatedTokenFileEnvKey),
+	}
+
+	if b, ok := credentials[azureTenantIDSecretKey]; ok {
+		ac.tenantID = string(b)
+	}
+	if b, ok := credentials[azureClientIDSecretKey]; ok {
+		ac.clientID = string(b)
+	}
+	if b, ok := credentials[azureClientSecretSecretKey]; ok {
+		ac.clientSecret = string(b)
+	}
+	if b, ok := credentials[azureFederatedTokenSecretKey]; ok {
+		ac.federatedToken = string(b)
+	}
+
+	if ac.tenantID == "" {
+		return nil, fmt.Errorf("azure credentials does not contain the key '%s'", azureTenantIDSecretKey)
+	}
+	if ac.clientID == "" {
+		return nil, fmt.Errorf("azure credentials does not contain the key '%s'", azureClientIDSecretKey)
+	}
+	if ac.clientSecret == "" && ac.federatedToken == "" && ac.tokenFile == "" {
+		return nil, fmt.Errorf("azure credentials does not contain the key '%s' or '%s'",
+			azureClientSecretSecretKey, azureFederatedTokenSecretKey)
+	}
+
+	return ac, nil
+}
+
+// getToken returns a cached access token or requests a new one from Azure AD
+func (ac *azureCredential) getToken() (string, error) {
+	if ac.token != "" && time.Now().Add(azureTokenExpiryDelta).Before(ac.expiresAt) {
+		return ac.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", ac.clientID)
+	form.Set("scope", ac.scope)
+
+	switch {
+	case ac.clientSecret != "":
+		form.Set("client_secret", ac.clientSecret)
+	case ac.federatedToken != "":
+		form.Set("client_assertion_type", azureClientAssertionType)
+		form.Set("client_assertion", ac.federatedToken)
+	default:
+		// the projected service account token is rotated by kubelet, read it on every request
+		b, err := os.ReadFile(ac.tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading federated token file: %w", err)
+		}
+		form.Set("client_assertion_type", azureClientAssertionType)
+		form.Set("client_assertion", strings.TrimSpace(string(b)))
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/oauth2/v2.0/token", ac.authorityHost, ac.tenantID)
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	ctx, cancel := context.WithTimeout(req.Context(), ac.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading body: %w", err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error token response: %s", string(b))
+	}
+
+	var res azureTokenResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return "", fmt.Errorf("error unmarshaling token: %w", err)
+	}
+
+	if res.AccessToken == "" {
+		return "", fmt.Errorf("token response does not contain an access token")
+	}
+
+	ac.token = res.AccessToken
+	ac.expiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	return ac.token, nil
+}
+
+// toISO8601Duration converts a Go duration to the ISO 8601 format used by the Azure APIs
+func toISO8601Duration(d time.Duration) string {
+	seconds := int64(d.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Sprintf("PT%dS", seconds)
+}
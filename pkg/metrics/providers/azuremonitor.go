@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// https://learn.microsoft.com/en-us/rest/api/monitor/metrics/list
+const (
+	azureMonitorAPIVersion              = "2018-01-01"
+	azureMonitorSubscriptionsAPIVersion = "2020-01-01"
+	azureMonitorDefaultAggregation      = "Average"
+)
+
+// AzureMonitorProvider executes Azure Monitor metrics queries
+type AzureMonitorProvider struct {
+	address    string
+	timeout    time.Duration
+	timespan   time.Duration
+	credential *azureCredential
+}
+
+// azureMonitorQuery is the JSON query format accepted by the provider
+type azureMonitorQuery struct {
+	ResourceID      string `json:"resourceId"`
+	MetricNamespace string `json:"metricNamespace,omitempty"`
+	MetricName      string `json:"metricName"`
+	Aggregation     string `json:"aggregation,omitempty"`
+	Interval        string `json:"interval,omitempty"`
+	Filter          string `json:"filter,omitempty"`
+}
+
+type azureMonitorResponse struct {
+	Value []struct {
+		Timeseries []struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"timeseries"`
+	} `json:"value"`
+}
+
+// NewAzureMonitorProvider takes a metric interval, a provider spec and the credentials map, and
+// returns an AzureMonitorProvider ready to execute queries against the Azure Monitor metrics API
+func NewAzureMonitorProvider(metricInterval string,
+	provider flaggerv1.MetricTemplateProvider,
+	credentials map[string][]byte) (*AzureMonitorProvider, error) {
+	cloud, err := getAzureCloud(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	address := provider.Address
+	if address == "" {
+		address = cloud.resourceManager
+	}
+
+	credential, err := newAzureCredential(cloud, cloud.resourceManagerScope, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := time.ParseDuration(metricInterval)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metric interval: %w", err)
+	}
+
+	return &AzureMonitorProvider{
+		address:    strings.TrimSuffix(address, "/"),
+		timeout:    5 * time.Second,
+		timespan:   md,
+		credential: credential,
+	}, nil
+}
+
+// RunQuery executes the Azure Monitor metrics query
+// and returns the latest data point of the first time series as float64
+func (p *AzureMonitorProvider) RunQuery(query string) (float64, error) {
+	var q azureMonitorQuery
+	if err := json.Unmarshal([]byte(query), &q); err != nil {
+		return 0, fmt.Errorf("error unmarshaling query: %w", err)
+	}
+
+	if q.ResourceID == "" || q.MetricName == "" {
+		return 0, fmt.Errorf("query must contain resourceId and metricName")
+	}
+
+	if q.Aggregation == "" {
+		q.Aggregation = azureMonitorDefaultAggregation
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/providers/microsoft.insights/metrics",
+		p.address, strings.TrimPrefix(q.ResourceID, "/"))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-p.timespan)
+	params := req.URL.Query()
+	params.Add("api-version", azureMonitorAPIVersion)
+	params.Add("metricnames", q.MetricName)
+	params.Add("aggregation", q.Aggregation)
+	params.Add("timespan", fmt.Sprintf("%s/%s", start.Format(time.RFC3339), end.Format(time.RFC3339)))
+	if q.MetricNamespace != "" {
+		params.Add("metricnamespace", q.MetricNamespace)
+	}
+	if q.Interval != "" {
+		params.Add("interval", q.Interval)
+	}
+	if q.Filter != "" {
+		params.Add("$filter", q.Filter)
+	}
+	req.URL.RawQuery = params.Encode()
+
+	b, err := p.do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	var res azureMonitorResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
+	}
+
+	if len(res.Value) < 1 || len(res.Value[0].Timeseries) < 1 {
+		return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+	}
+
+	// the response contains a data point for every interval of the timespan,
+	// intervals without samples have no aggregation field
+	key := strings.ToLower(q.Aggregation)
+	data := res.Value[0].Timeseries[0].Data
+	for i := len(data) - 1; i >= 0; i-- {
+		if v, ok := data[i][key].(float64); ok {
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+}
+
+// IsOnline acquires an access token and lists the subscriptions
+// the identity has access to, returning an error if the request is rejected
+func (p *AzureMonitorProvider) IsOnline() (bool, error) {
+	req, err := http.NewRequest("GET", p.address+"/subscriptions", nil)
+	if err != nil {
+		return false, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	params := req.URL.Query()
+	params.Add("api-version", azureMonitorSubscriptionsAPIVersion)
+	req.URL.RawQuery = params.Encode()
+
+	if _, err := p.do(req); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (p *AzureMonitorProvider) do(req *http.Request) ([]byte, error) {
+	token, err := p.credential.getToken()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response: %s", string(b))
+	}
+
+	return b, nil
+}
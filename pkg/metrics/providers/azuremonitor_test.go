@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func newAzureTestTokenServer(t *testing.T, assertion string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		if assertion != "" {
+			assert.Equal(t, assertion, r.Form.Get("client_assertion"))
+			assert.Equal(t, azureClientAssertionType, r.Form.Get("client_assertion_type"))
+		} else {
+			assert.Equal(t, "secret", r.Form.Get("client_secret"))
+		}
+		w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+	}))
+}
+
+func TestNewAzureMonitorProvider(t *testing.T) {
+	cs := map[string][]byte{
+		azureTenantIDSecretKey:     []byte("tenant"),
+		azureClientIDSecretKey:     []byte("client"),
+		azureClientSecretSecretKey: []byte("secret"),
+	}
+
+	p, err := NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://management.azure.com", p.address)
+	assert.Equal(t, "https://login.microsoftonline.com", p.credential.authorityHost)
+	assert.Equal(t, time.Minute, p.timespan)
+
+	cs[azureCloudSecretKey] = []byte("AzureChinaCloud")
+	p, err = NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://management.chinacloudapi.cn", p.address)
+	assert.Equal(t, "https://login.chinacloudapi.cn", p.credential.authorityHost)
+
+	cs[azureCloudSecretKey] = []byte("AzureMoonCloud")
+	_, err = NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.Error(t, err)
+
+	t.Setenv(azureFederatedTokenFileEnvKey, "")
+	_, err = NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{}, map[string][]byte{
+		azureTenantIDSecretKey: []byte("tenant"),
+		azureClientIDSecretKey: []byte("client"),
+	})
+	require.Error(t, err)
+}
+
+func TestAzureMonitorProvider_RunQuery(t *testing.T) {
+	query := `{"resourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/applicationGateways/gw",
+		"metricName": "BackendLastByteResponseTime", "filter": "BackendPool eq 'podinfo-canary'"}`
+
+	t.Run("ok", func(t *testing.T) {
+		tokenServer := newAzureTestTokenServer(t, "federated")
+		defer tokenServer.Close()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.True(t, strings.HasSuffix(r.URL.Path, "/applicationGateways/gw/providers/microsoft.insights/metrics"))
+			assert.Equal(t, "BackendLastByteResponseTime", r.URL.Query().Get("metricnames"))
+			assert.Equal(t, "Average", r.URL.Query().Get("aggregation"))
+			assert.Equal(t, "BackendPool eq 'podinfo-canary'", r.URL.Query().Get("$filter"))
+			w.Write([]byte(`{"value": [{"timeseries": [{"data": [
+				{"timeStamp": "2022-11-01T10:00:00Z", "average": 10.5},
+				{"timeStamp": "2022-11-01T10:01:00Z", "average": 12.5},
+				{"timeStamp": "2022-11-01T10:02:00Z"}
+			]}]}]}`))
+		}))
+		defer ts.Close()
+
+		p, err := NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, map[string][]byte{
+			azureTenantIDSecretKey:       []byte("tenant"),
+			azureClientIDSecretKey:       []byte("client"),
+			azureFederatedTokenSecretKey: []byte("federated"),
+		})
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		f, err := p.RunQuery(query)
+		require.NoError(t, err)
+		assert.Equal(t, 12.5, f)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		tokenServer := newAzureTestTokenServer(t, "")
+		defer tokenServer.Close()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"value": [{"timeseries": [{"data": [{"timeStamp": "2022-11-01T10:00:00Z"}]}]}]}`))
+		}))
+		defer ts.Close()
+
+		p, err := NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, map[string][]byte{
+			azureTenantIDSecretKey:     []byte("tenant"),
+			azureClientIDSecretKey:     []byte("client"),
+			azureClientSecretSecretKey: []byte("secret"),
+		})
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		_, err = p.RunQuery(query)
+		require.True(t, errors.Is(err, ErrNoValuesFound))
+	})
+}
+
+func TestAzureMonitorProvider_IsOnline(t *testing.T) {
+	for _, c := range []struct {
+		code        int
+		errExpected bool
+	}{
+		{code: http.StatusOK, errExpected: false},
+		{code: http.StatusUnauthorized, errExpected: true},
+	} {
+		tokenServer := newAzureTestTokenServer(t, "")
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/subscriptions", r.URL.Path)
+			w.WriteHeader(c.code)
+		}))
+
+		p, err := NewAzureMonitorProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, map[string][]byte{
+			azureTenantIDSecretKey:     []byte("tenant"),
+			azureClientIDSecretKey:     []byte("client"),
+			azureClientSecretSecretKey: []byte("secret"),
+		})
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		_, err = p.IsOnline()
+		if c.errExpected {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		ts.Close()
+		tokenServer.Close()
+	}
+}
@@ -20,7 +20,10 @@ import (
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 )
 
-type Factory struct{}
+type Factory struct {
+	// KubernetesTarget is used by the kubernetes provider to select the canary and primary pods
+	KubernetesTarget *KubernetesTarget
+}
 
 func (factory Factory) Provider(
 	metricInterval string,
@@ -44,6 +47,18 @@ func (factory Factory) Provider(
 		return NewInfluxdbProvider(provider, credentials)
 	case "dynatrace":
 		return NewDynatraceProvider(metricInterval, provider, credentials)
+	case "azuremonitor":
+		return NewAzureMonitorProvider(metricInterval, provider, credentials)
+	case "loganalytics":
+		return NewLogAnalyticsProvider(metricInterval, provider, credentials)
+	case "splunk":
+		return NewSplunkProvider(metricInterval, provider, credentials)
+	case "signalfx":
+		return NewSignalFxProvider(metricInterval, provider, credentials)
+	case "sentry":
+		return NewSentryProvider(metricInterval, provider, credentials)
+	case "kubernetes":
+		return NewKubernetesProvider(factory.KubernetesTarget)
 	default:
 		return NewPrometheusProvider(provider, credentials)
 	}
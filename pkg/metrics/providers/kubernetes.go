@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// kubernetesQueryRegexp matches queries such as `canary_memory`, `max(primary_cpu)` or `avg(memory_ratio)`
+var kubernetesQueryRegexp = regexp.MustCompile(`^(?:(avg|max|min|sum)\(\s*([a-z_]+)\s*\)|([a-z_]+))$`)
+
+// KubernetesTarget selects the canary and primary pods for the kubernetes provider
+type KubernetesTarget struct {
+	// Client for the resource metrics API (metrics.k8s.io)
+	Client metricsv1beta1.PodMetricsesGetter
+
+	// Namespace of the canary and primary pods
+	Namespace string
+
+	// CanarySelector is the label selector of the canary pods
+	CanarySelector string
+
+	// PrimarySelector is the label selector of the primary pods
+	PrimarySelector string
+}
+
+// KubernetesProvider computes the CPU and memory usage of the canary and primary pods
+// from the Kubernetes resource metrics API, without depending on Prometheus
+type KubernetesProvider struct {
+	target  KubernetesTarget
+	timeout time.Duration
+}
+
+// NewKubernetesProvider takes the canary target and returns a KubernetesProvider
+// ready to query the resource metrics API
+func NewKubernetesProvider(target *KubernetesTarget) (*KubernetesProvider, error) {
+	if target == nil || target.Client == nil {
+		return nil, fmt.Errorf("kubernetes provider is not supported for this canary target")
+	}
+
+	if target.CanarySelector == "" || target.PrimarySelector == "" {
+		return nil, fmt.Errorf("kubernetes provider requires the canary and primary pod selectors")
+	}
+
+	return &KubernetesProvider{
+		target:  *target,
+		timeout: 5 * time.Second,
+	}, nil
+}
+
+// RunQuery aggregates the per-pod resource usage of the canary or primary pods.
+// The query has the form `[avg|max|min|sum](<canary|primary>_<cpu|memory>)` or
+// `[avg|max|min|sum](<cpu|memory>_ratio)`, the aggregation defaults to avg.
+// CPU is measured in millicores, memory in mebibytes and ratios divide the canary usage by the primary usage.
+func (p *KubernetesProvider) RunQuery(query string) (float64, error) {
+	m := kubernetesQueryRegexp.FindStringSubmatch(strings.TrimSpace(query))
+	if m == nil {
+		return 0, fmt.Errorf("invalid query '%s'", query)
+	}
+
+	aggregation, metric := m[1], m[2]
+	if metric == "" {
+		aggregation, metric = "avg", m[3]
+	}
+
+	parts := strings.SplitN(metric, "_", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid query '%s'", query)
+	}
+
+	switch {
+	case parts[1] == "ratio" && isKubernetesResource(parts[0]):
+		canary, err := p.usage(p.target.CanarySelector, corev1.ResourceName(parts[0]), aggregation)
+		if err != nil {
+			return 0, err
+		}
+		primary, err := p.usage(p.target.PrimarySelector, corev1.ResourceName(parts[0]), aggregation)
+		if err != nil {
+			return 0, err
+		}
+		if primary == 0 {
+			return 0, fmt.Errorf("primary %s usage is zero: %w", parts[0], ErrNoValuesFound)
+		}
+		return canary / primary, nil
+	case parts[0] == "canary" && isKubernetesResource(parts[1]):
+		return p.usage(p.target.CanarySelector, corev1.ResourceName(parts[1]), aggregation)
+	case parts[0] == "primary" && isKubernetesResource(parts[1]):
+		return p.usage(p.target.PrimarySelector, corev1.ResourceName(parts[1]), aggregation)
+	default:
+		return 0, fmt.Errorf("invalid query '%s'", query)
+	}
+}
+
+// IsOnline lists the canary pod metrics and returns an error
+// if the resource metrics API is not available
+func (p *KubernetesProvider) IsOnline() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	_, err := p.target.Client.PodMetricses(p.target.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: p.target.CanarySelector,
+	})
+	if err != nil {
+		return false, fmt.Errorf("resource metrics API not available: %w", err)
+	}
+	return true, nil
+}
+
+// usage returns the aggregated usage of the pods matching the selector,
+// the usage of a pod is the sum of its containers usage
+func (p *KubernetesProvider) usage(selector string, resource corev1.ResourceName, aggregation string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	list, err := p.target.Client.PodMetricses(p.target.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error listing pod metrics for %s: %w", selector, err)
+	}
+
+	if len(list.Items) < 1 {
+		return 0, fmt.Errorf("no pod metrics found for %s.%s: %w", selector, p.target.Namespace, ErrNoValuesFound)
+	}
+
+	var values []float64
+	for _, pod := range list.Items {
+		var value float64
+		for _, container := range pod.Containers {
+			quantity, ok := container.Usage[resource]
+			if !ok {
+				continue
+			}
+			if resource == corev1.ResourceCPU {
+				value += float64(quantity.MilliValue())
+			} else {
+				value += float64(quantity.Value()) / (1024 * 1024)
+			}
+		}
+		values = append(values, value)
+	}
+
+	result := values[0]
+	switch aggregation {
+	case "max":
+		for _, v := range values {
+			if v > result {
+				result = v
+			}
+		}
+	case "min":
+		for _, v := range values {
+			if v < result {
+				result = v
+			}
+		}
+	default:
+		result = 0
+		for _, v := range values {
+			result += v
+		}
+		if aggregation == "avg" {
+			result = result / float64(len(values))
+		}
+	}
+
+	return result, nil
+}
+
+func isKubernetesResource(name string) bool {
+	return name == string(corev1.ResourceCPU) || name == string(corev1.ResourceMemory)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsapiv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func newKubernetesTestPodMetrics(app, cpu, memory string) metricsapiv1beta1.PodMetrics {
+	return metricsapiv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": app},
+		},
+		Containers: []metricsapiv1beta1.ContainerMetrics{
+			{
+				Name: "podinfo",
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		},
+	}
+}
+
+func newKubernetesTestTarget() *KubernetesTarget {
+	client := metricsfake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Labels.String()
+		list := &metricsapiv1beta1.PodMetricsList{}
+		switch selector {
+		case "app=podinfo":
+			list.Items = []metricsapiv1beta1.PodMetrics{
+				newKubernetesTestPodMetrics("podinfo", "100m", "150Mi"),
+				newKubernetesTestPodMetrics("podinfo", "300m", "250Mi"),
+			}
+		case "app=podinfo-primary":
+			list.Items = []metricsapiv1beta1.PodMetrics{
+				newKubernetesTestPodMetrics("podinfo-primary", "100m", "100Mi"),
+			}
+		}
+		return true, list, nil
+	})
+
+	return &KubernetesTarget{
+		Client:          client.MetricsV1beta1(),
+		Namespace:       "default",
+		CanarySelector:  "app=podinfo",
+		PrimarySelector: "app=podinfo-primary",
+	}
+}
+
+func TestNewKubernetesProvider(t *testing.T) {
+	_, err := NewKubernetesProvider(nil)
+	require.Error(t, err)
+
+	_, err = NewKubernetesProvider(&KubernetesTarget{Client: newKubernetesTestTarget().Client})
+	require.Error(t, err)
+
+	_, err = NewKubernetesProvider(newKubernetesTestTarget())
+	require.NoError(t, err)
+}
+
+func TestKubernetesProvider_RunQuery(t *testing.T) {
+	p, err := NewKubernetesProvider(newKubernetesTestTarget())
+	require.NoError(t, err)
+
+	for query, expected := range map[string]float64{
+		"canary_cpu":          200,
+		"max(canary_cpu)":     300,
+		"sum(canary_memory)":  400,
+		"primary_memory":      100,
+		"memory_ratio":        2,
+		"max( memory_ratio )": 2.5,
+		"min(cpu_ratio)":      1,
+	} {
+		val, err := p.RunQuery(query)
+		require.NoError(t, err, query)
+		assert.Equal(t, expected, val, query)
+	}
+
+	for _, query := range []string{"canary_disk", "p99(canary_cpu)", "memory", "canary_cpu_ratio"} {
+		_, err := p.RunQuery(query)
+		require.Error(t, err, query)
+	}
+
+	p.target.CanarySelector = "app=unknown"
+	_, err = p.RunQuery("canary_cpu")
+	require.True(t, errors.Is(err, ErrNoValuesFound))
+}
+
+func TestKubernetesProvider_IsOnline(t *testing.T) {
+	p, err := NewKubernetesProvider(newKubernetesTestTarget())
+	require.NoError(t, err)
+
+	ok, err := p.IsOnline()
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// https://learn.microsoft.com/en-us/rest/api/loganalytics/dataaccess/query/execute
+const (
+	logAnalyticsWorkspaceIDSecretKey = "azure_workspace_id"
+)
+
+// LogAnalyticsProvider executes Kusto (KQL) queries against an Azure Log Analytics workspace
+type LogAnalyticsProvider struct {
+	queryEndpoint string
+	timeout       time.Duration
+	timespan      string
+	credential    *azureCredential
+}
+
+type logAnalyticsRequest struct {
+	Query    string `json:"query"`
+	Timespan string `json:"timespan,omitempty"`
+}
+
+type logAnalyticsResponse struct {
+	Tables []struct {
+		Rows [][]interface{} `json:"rows"`
+	} `json:"tables"`
+}
+
+// NewLogAnalyticsProvider takes a metric interval, a provider spec and the credentials map, and
+// returns a LogAnalyticsProvider ready to execute queries against the Log Analytics API
+func NewLogAnalyticsProvider(metricInterval string,
+	provider flaggerv1.MetricTemplateProvider,
+	credentials map[string][]byte) (*LogAnalyticsProvider, error) {
+	cloud, err := getAzureCloud(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	address := provider.Address
+	if address == "" {
+		address = cloud.logAnalytics
+	}
+
+	workspaceID, ok := credentials[logAnalyticsWorkspaceIDSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("loganalytics credentials does not contain the key '%s'", logAnalyticsWorkspaceIDSecretKey)
+	}
+
+	credential, err := newAzureCredential(cloud, cloud.logAnalyticsScope, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := time.ParseDuration(metricInterval)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metric interval: %w", err)
+	}
+
+	return &LogAnalyticsProvider{
+		queryEndpoint: fmt.Sprintf("%s/v1/workspaces/%s/query", strings.TrimSuffix(address, "/"), workspaceID),
+		timeout:       5 * time.Second,
+		timespan:      toISO8601Duration(md),
+		credential:    credential,
+	}, nil
+}
+
+// RunQuery executes the KQL query over the metric interval
+// and returns the first column of the first row as float64
+func (p *LogAnalyticsProvider) RunQuery(query string) (float64, error) {
+	b, err := p.query(query)
+	if err != nil {
+		return 0, err
+	}
+
+	var res logAnalyticsResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
+	}
+
+	if len(res.Tables) < 1 || len(res.Tables[0].Rows) < 1 || len(res.Tables[0].Rows[0]) < 1 {
+		return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+	}
+
+	v, ok := res.Tables[0].Rows[0][0].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+	}
+
+	return v, nil
+}
+
+// IsOnline runs a trivial query against the workspace
+// and returns an error if the request is rejected
+func (p *LogAnalyticsProvider) IsOnline() (bool, error) {
+	if _, err := p.query("print 1"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *LogAnalyticsProvider) query(query string) ([]byte, error) {
+	body, err := json.Marshal(logAnalyticsRequest{Query: query, Timespan: p.timespan})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling query: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", p.queryEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	token, err := p.credential.getToken()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response: %s", string(b))
+	}
+
+	return b, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestNewLogAnalyticsProvider(t *testing.T) {
+	cs := map[string][]byte{
+		azureTenantIDSecretKey:     []byte("tenant"),
+		azureClientIDSecretKey:     []byte("client"),
+		azureClientSecretSecretKey: []byte("secret"),
+	}
+
+	_, err := NewLogAnalyticsProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.Error(t, err)
+
+	cs[logAnalyticsWorkspaceIDSecretKey] = []byte("workspace")
+	cs[azureCloudSecretKey] = []byte("AzureUSGovernment")
+	p, err := NewLogAnalyticsProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.loganalytics.us/v1/workspaces/workspace/query", p.queryEndpoint)
+	assert.Equal(t, "https://api.loganalytics.us/.default", p.credential.scope)
+	assert.Equal(t, "PT60S", p.timespan)
+}
+
+func TestLogAnalyticsProvider_RunQuery(t *testing.T) {
+	cs := map[string][]byte{
+		azureTenantIDSecretKey:           []byte("tenant"),
+		azureClientIDSecretKey:           []byte("client"),
+		azureClientSecretSecretKey:       []byte("secret"),
+		logAnalyticsWorkspaceIDSecretKey: []byte("workspace"),
+	}
+	query := `AppRequests | where AppRoleName == "podinfo-canary" | summarize avg(DurationMs)`
+
+	t.Run("ok", func(t *testing.T) {
+		tokenServer := newAzureTestTokenServer(t, "")
+		defer tokenServer.Close()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "/v1/workspaces/workspace/query", r.URL.Path)
+
+			var body logAnalyticsRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, query, body.Query)
+			assert.Equal(t, "PT120S", body.Timespan)
+
+			w.Write([]byte(`{"tables": [{"name": "PrimaryResult",
+				"columns": [{"name": "avg_DurationMs", "type": "real"}], "rows": [[42.5]]}]}`))
+		}))
+		defer ts.Close()
+
+		p, err := NewLogAnalyticsProvider("2m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		f, err := p.RunQuery(query)
+		require.NoError(t, err)
+		assert.Equal(t, 42.5, f)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		tokenServer := newAzureTestTokenServer(t, "")
+		defer tokenServer.Close()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"tables": [{"name": "PrimaryResult", "rows": []}]}`))
+		}))
+		defer ts.Close()
+
+		p, err := NewLogAnalyticsProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		_, err = p.RunQuery(query)
+		require.True(t, errors.Is(err, ErrNoValuesFound))
+	})
+}
+
+func TestLogAnalyticsProvider_IsOnline(t *testing.T) {
+	cs := map[string][]byte{
+		azureTenantIDSecretKey:           []byte("tenant"),
+		azureClientIDSecretKey:           []byte("client"),
+		azureClientSecretSecretKey:       []byte("secret"),
+		logAnalyticsWorkspaceIDSecretKey: []byte("workspace"),
+	}
+
+	for _, c := range []struct {
+		code        int
+		errExpected bool
+	}{
+		{code: http.StatusOK, errExpected: false},
+		{code: http.StatusForbidden, errExpected: true},
+	} {
+		tokenServer := newAzureTestTokenServer(t, "")
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.code)
+		}))
+
+		p, err := NewLogAnalyticsProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+		p.credential.authorityHost = tokenServer.URL
+
+		_, err = p.IsOnline()
+		if c.errExpected {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		ts.Close()
+		tokenServer.Close()
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// https://docs.sentry.io/api/
+const (
+	sentryDefaultHost = "https://sentry.io"
+
+	sentryTokenSecretKey        = "sentry_token"
+	sentryOrganizationSecretKey = "sentry_organization"
+
+	sentrySessionsPath = "sessions/"
+	sentryIssuesPath   = "issues/"
+
+	sentryHitsHeaderKey = "X-Hits"
+)
+
+// SentryProvider executes release health and issues queries against the Sentry API
+type SentryProvider struct {
+	organizationEndpoint string
+
+	timeout     time.Duration
+	token       string
+	statsPeriod string
+}
+
+type sentrySessionsResponse struct {
+	Groups []struct {
+		Totals map[string]*float64 `json:"totals"`
+	} `json:"groups"`
+}
+
+// NewSentryProvider takes a metric interval, a provider spec and the credentials map, and
+// returns a Sentry client ready to execute queries against the organization API
+func NewSentryProvider(metricInterval string,
+	provider flaggerv1.MetricTemplateProvider,
+	credentials map[string][]byte) (*SentryProvider, error) {
+	address := provider.Address
+	if address == "" {
+		address = sentryDefaultHost
+	}
+
+	organization, ok := credentials[sentryOrganizationSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("sentry credentials does not contain the key '%s'", sentryOrganizationSecretKey)
+	}
+
+	sp := SentryProvider{
+		organizationEndpoint: fmt.Sprintf("%s/api/0/organizations/%s/", strings.TrimSuffix(address, "/"), organization),
+		timeout:              5 * time.Second,
+	}
+
+	if b, ok := credentials[sentryTokenSecretKey]; ok {
+		sp.token = string(b)
+	} else {
+		return nil, fmt.Errorf("sentry credentials does not contain the key '%s'", sentryTokenSecretKey)
+	}
+
+	md, err := time.ParseDuration(metricInterval)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metric interval: %w", err)
+	}
+
+	// Sentry accepts stats periods with minute granularity
+	minutes := int64(md.Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	sp.statsPeriod = fmt.Sprintf("%dm", minutes)
+	return &sp, nil
+}
+
+// RunQuery executes the query relative to the organization API endpoint.
+// For `sessions/` queries, it returns the total of the requested field for the first group,
+// e.g. `sessions/?project=1&field=crash_free_rate(session)&query=release:{{ imageTag }}`.
+// For `issues/` queries, it returns the number of issues matching the search query,
+// e.g. `issues/?project=1&query=firstRelease:{{ imageTag }} is:unresolved`.
+// When the query doesn't set a time range, the metric interval is used as stats period.
+func (p *SentryProvider) RunQuery(query string) (float64, error) {
+	u, err := url.Parse(strings.TrimPrefix(strings.TrimSpace(query), "/"))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing query: %w", err)
+	}
+
+	params := u.Query()
+	if params.Get("statsPeriod") == "" && params.Get("start") == "" {
+		params.Set("statsPeriod", p.statsPeriod)
+	}
+
+	switch {
+	case strings.HasPrefix(u.Path, sentrySessionsPath):
+		field := params.Get("field")
+		if field == "" || len(params["field"]) > 1 {
+			return 0, fmt.Errorf("sessions query must contain a single field")
+		}
+
+		b, _, err := p.get(u.Path, params)
+		if err != nil {
+			return 0, err
+		}
+
+		var res sentrySessionsResponse
+		if err := json.Unmarshal(b, &res); err != nil {
+			return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
+		}
+
+		if len(res.Groups) < 1 {
+			return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+		}
+
+		// rates are null when the release has no sessions in the stats period
+		v, ok := res.Groups[0].Totals[field]
+		if !ok || v == nil {
+			return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+		}
+
+		return *v, nil
+	case strings.HasPrefix(u.Path, sentryIssuesPath):
+		b, header, err := p.get(u.Path, params)
+		if err != nil {
+			return 0, err
+		}
+
+		if hits := header.Get(sentryHitsHeaderKey); hits != "" {
+			v, err := strconv.ParseFloat(hits, 64)
+			if err != nil {
+				return 0, fmt.Errorf("error parsing %s header '%s': %w", sentryHitsHeaderKey, hits, err)
+			}
+			return v, nil
+		}
+
+		var issues []json.RawMessage
+		if err := json.Unmarshal(b, &issues); err != nil {
+			return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
+		}
+		return float64(len(issues)), nil
+	default:
+		return 0, fmt.Errorf("unsupported query path '%s', must start with '%s' or '%s'",
+			u.Path, sentrySessionsPath, sentryIssuesPath)
+	}
+}
+
+// IsOnline calls the organization details endpoint
+// and returns an error if the request is rejected
+func (p *SentryProvider) IsOnline() (bool, error) {
+	if _, _, err := p.get("", nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *SentryProvider) get(path string, params url.Values) ([]byte, http.Header, error) {
+	req, err := http.NewRequest("GET", p.organizationEndpoint+path, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if params != nil {
+		req.URL.RawQuery = params.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading body: %w", err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("error response: %s", string(b))
+	}
+
+	return b, r.Header, nil
+}
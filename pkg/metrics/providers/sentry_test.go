@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestNewSentryProvider(t *testing.T) {
+	cs := map[string][]byte{
+		sentryTokenSecretKey:        []byte("token"),
+		sentryOrganizationSecretKey: []byte("acme"),
+	}
+
+	_, err := NewSentryProvider("1m", flaggerv1.MetricTemplateProvider{}, map[string][]byte{
+		sentryTokenSecretKey: []byte("token"),
+	})
+	require.Error(t, err)
+
+	sp, err := NewSentryProvider("1h", flaggerv1.MetricTemplateProvider{}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://sentry.io/api/0/organizations/acme/", sp.organizationEndpoint)
+	assert.Equal(t, "60m", sp.statsPeriod)
+	assert.Equal(t, "token", sp.token)
+}
+
+func TestSentryProvider_RunQuery(t *testing.T) {
+	cs := map[string][]byte{
+		sentryTokenSecretKey:        []byte("token"),
+		sentryOrganizationSecretKey: []byte("acme"),
+	}
+
+	t.Run("sessions", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "/api/0/organizations/acme/sessions/", r.URL.Path)
+			assert.Equal(t, "release:1.2.0", r.URL.Query().Get("query"))
+			assert.Equal(t, "5m", r.URL.Query().Get("statsPeriod"))
+			w.Write([]byte(`{"groups": [{"by": {}, "totals": {"crash_free_rate(session)": 0.9985}}]}`))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSentryProvider("5m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		f, err := sp.RunQuery("sessions/?project=1&field=crash_free_rate(session)&query=release:1.2.0")
+		require.NoError(t, err)
+		assert.Equal(t, 0.9985, f)
+	})
+
+	t.Run("sessions no values", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"groups": [{"by": {}, "totals": {"crash_free_rate(session)": null}}]}`))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSentryProvider("5m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery("sessions/?project=1&field=crash_free_rate(session)&query=release:1.2.0")
+		require.True(t, errors.Is(err, ErrNoValuesFound))
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/0/organizations/acme/issues/", r.URL.Path)
+			assert.Equal(t, "firstRelease:1.2.0 is:unresolved", r.URL.Query().Get("query"))
+			assert.Equal(t, "24h", r.URL.Query().Get("statsPeriod"))
+			w.Write([]byte(`[{"id": "1"}, {"id": "2"}]`))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSentryProvider("5m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		f, err := sp.RunQuery("/issues/?project=1&statsPeriod=24h&query=firstRelease:1.2.0+is:unresolved")
+		require.NoError(t, err)
+		assert.Equal(t, float64(2), f)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		sp, err := NewSentryProvider("5m", flaggerv1.MetricTemplateProvider{}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery("releases/")
+		require.Error(t, err)
+	})
+}
+
+func TestSentryProvider_IsOnline(t *testing.T) {
+	for _, c := range []struct {
+		code        int
+		errExpected bool
+	}{
+		{code: http.StatusOK, errExpected: false},
+		{code: http.StatusUnauthorized, errExpected: true},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/0/organizations/acme/", r.URL.Path)
+			w.WriteHeader(c.code)
+		}))
+
+		sp, err := NewSentryProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, map[string][]byte{
+			sentryTokenSecretKey:        []byte("token"),
+			sentryOrganizationSecretKey: []byte("acme"),
+		})
+		require.NoError(t, err)
+
+		_, err = sp.IsOnline()
+		if c.errExpected {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		ts.Close()
+	}
+}
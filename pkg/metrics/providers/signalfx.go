@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// https://dev.splunk.com/observability/reference/api/signalflow/latest
+const (
+	signalFxDefaultRealm = "us0"
+	signalFxExecutePath  = "/v2/signalflow/execute"
+
+	signalFxTokenSecretKey = "signalfx_token"
+	signalFxTokenHeaderKey = "X-SF-Token"
+)
+
+// SignalFxProvider executes SignalFlow programs against the Splunk Observability Cloud API
+type SignalFxProvider struct {
+	executeEndpoint string
+
+	timeout   time.Duration
+	token     string
+	fromDelta time.Duration
+}
+
+type signalFxDataMessage struct {
+	Data []struct {
+		TsID  string   `json:"tsId"`
+		Value *float64 `json:"value"`
+	} `json:"data"`
+}
+
+type signalFxErrorMessage struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewSignalFxProvider takes a metric interval, a provider spec and the credentials map, and
+// returns a SignalFx client ready to execute SignalFlow programs. When the address is not set,
+// the stream endpoint is derived from the provider region (realm), defaulting to us0.
+func NewSignalFxProvider(metricInterval string,
+	provider flaggerv1.MetricTemplateProvider,
+	credentials map[string][]byte) (*SignalFxProvider, error) {
+	address := provider.Address
+	if address == "" {
+		realm := provider.Region
+		if realm == "" {
+			realm = signalFxDefaultRealm
+		}
+		address = fmt.Sprintf("https://stream.%s.signalfx.com", realm)
+	}
+
+	sp := SignalFxProvider{
+		executeEndpoint: strings.TrimSuffix(address, "/") + signalFxExecutePath,
+		timeout:         30 * time.Second,
+	}
+
+	if b, ok := credentials[signalFxTokenSecretKey]; ok {
+		sp.token = string(b)
+	} else {
+		return nil, fmt.Errorf("signalfx credentials does not contain the key '%s'", signalFxTokenSecretKey)
+	}
+
+	md, err := time.ParseDuration(metricInterval)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metric interval: %w", err)
+	}
+
+	sp.fromDelta = md
+	return &sp, nil
+}
+
+// RunQuery executes the SignalFlow program over the metric interval
+// and returns the last published value as float64
+func (p *SignalFxProvider) RunQuery(query string) (float64, error) {
+	values, err := p.execute(query)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(values) < 1 {
+		return 0, fmt.Errorf("invalid response: program %q published no data: %w", query, ErrNoValuesFound)
+	}
+
+	return values[len(values)-1], nil
+}
+
+// IsOnline executes a constant SignalFlow program
+// and returns an error if the request is rejected
+func (p *SignalFxProvider) IsOnline() (bool, error) {
+	if _, err := p.execute("const(1).publish()"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// execute runs the program in immediate mode and reads the server-sent events stream
+// until the end of channel, collecting the values published in data messages
+func (p *SignalFxProvider) execute(program string) ([]float64, error) {
+	req, err := http.NewRequest("POST", p.executeEndpoint, strings.NewReader(program))
+	if err != nil {
+		return nil, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	req.Header.Set(signalFxTokenHeaderKey, p.token)
+	req.Header.Set("Content-Type", "text/plain")
+
+	now := time.Now()
+	q := req.URL.Query()
+	q.Add("start", strconv.FormatInt(now.Add(-p.fromDelta).UnixMilli(), 10))
+	q.Add("stop", strconv.FormatInt(now.UnixMilli(), 10))
+	q.Add("immediate", "true")
+	req.URL.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(r.Body)
+		return nil, fmt.Errorf("error response: %s", string(b))
+	}
+
+	var values []float64
+	var event string
+	var data []string
+	handle := func() error {
+		defer func() { event, data = "", nil }()
+		payload := []byte(strings.Join(data, "\n"))
+		switch event {
+		case "data":
+			var msg signalFxDataMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return fmt.Errorf("error unmarshaling data message: %w, '%s'", err, string(payload))
+			}
+			for _, d := range msg.Data {
+				if d.Value != nil {
+					values = append(values, *d.Value)
+				}
+			}
+		case "error":
+			var msg signalFxErrorMessage
+			if err := json.Unmarshal(payload, &msg); err == nil && len(msg.Errors) > 0 {
+				return fmt.Errorf("signalflow error %s: %s", msg.Errors[0].Code, msg.Errors[0].Message)
+			}
+			return fmt.Errorf("signalflow error: %s", string(payload))
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := handle(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stream: %w", err)
+	}
+	if err := handle(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
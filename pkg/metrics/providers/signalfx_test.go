@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestNewSignalFxProvider(t *testing.T) {
+	cs := map[string][]byte{
+		signalFxTokenSecretKey: []byte("token"),
+	}
+
+	_, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{}, map[string][]byte{})
+	require.Error(t, err)
+
+	sp, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://stream.us0.signalfx.com/v2/signalflow/execute", sp.executeEndpoint)
+	assert.Equal(t, time.Minute, sp.fromDelta)
+
+	sp, err = NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{Region: "eu0"}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://stream.eu0.signalfx.com/v2/signalflow/execute", sp.executeEndpoint)
+}
+
+func TestSignalFxProvider_RunQuery(t *testing.T) {
+	program := `data('http.server.duration', filter=filter('service', 'podinfo-canary')).mean().publish()`
+	cs := map[string][]byte{
+		signalFxTokenSecretKey: []byte("token"),
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		now := time.Now().UnixMilli()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "token", r.Header.Get(signalFxTokenHeaderKey))
+			assert.Equal(t, "true", r.URL.Query().Get("immediate"))
+
+			start, err := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+			if assert.NoError(t, err) {
+				assert.Less(t, start, now)
+			}
+
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, program, string(b))
+
+			w.Write([]byte(`event: control-message
+data: {
+data:   "event" : "STREAM_START"
+data: }
+
+event: metadata
+data: {"tsId": "AAAAAKdFLb0"}
+
+event: data
+data: {"data": [{"tsId": "AAAAAKdFLb0", "value": 120.5}], "logicalTimestampMs": 1667296800000}
+
+event: data
+data: {"data": [{"tsId": "AAAAAKdFLb0", "value": 132.25}], "logicalTimestampMs": 1667296860000}
+
+event: control-message
+data: {"event": "END_OF_CHANNEL"}
+
+`))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		f, err := sp.RunQuery(program)
+		require.NoError(t, err)
+		assert.Equal(t, 132.25, f)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("event: control-message\ndata: {\"event\": \"END_OF_CHANNEL\"}\n\n"))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery(program)
+		require.True(t, errors.Is(err, ErrNoValuesFound))
+	})
+
+	t.Run("program error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("event: error\ndata: {\"errors\": [{\"code\": \"ANALYTICS_PROGRAM_NAME_ERROR\", \"message\": \"unknown function\"}]}\n\n"))
+		}))
+		defer ts.Close()
+
+		sp, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery(program)
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrNoValuesFound))
+	})
+}
+
+func TestSignalFxProvider_IsOnline(t *testing.T) {
+	for _, c := range []struct {
+		code        int
+		errExpected bool
+	}{
+		{code: http.StatusOK, errExpected: false},
+		{code: http.StatusUnauthorized, errExpected: true},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.code)
+		}))
+
+		sp, err := NewSignalFxProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL},
+			map[string][]byte{signalFxTokenSecretKey: []byte("token")})
+		require.NoError(t, err)
+
+		_, err = sp.IsOnline()
+		if c.errExpected {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		ts.Close()
+	}
+}
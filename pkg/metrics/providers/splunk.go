@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// https://docs.splunk.com/Documentation/Splunk/latest/RESTREF/RESTsearch
+const (
+	splunkSearchJobsPath = "/services/search/jobs"
+	splunkServerInfoPath = "/services/server/info"
+
+	splunkTokenSecretKey = "splunk_token"
+
+	splunkJobPollInterval = 500 * time.Millisecond
+	splunkJobTimeout      = 30 * time.Second
+)
+
+// SplunkProvider executes SPL searches against the Splunk REST API
+type SplunkProvider struct {
+	address      string
+	timeout      time.Duration
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	token        string
+	earliestTime string
+}
+
+type splunkJobResponse struct {
+	Sid string `json:"sid"`
+}
+
+type splunkJobStatusResponse struct {
+	Entry []struct {
+		Content struct {
+			IsDone        bool   `json:"isDone"`
+			IsFailed      bool   `json:"isFailed"`
+			DispatchState string `json:"dispatchState"`
+		} `json:"content"`
+	} `json:"entry"`
+}
+
+type splunkResultsResponse struct {
+	Results []map[string]interface{} `json:"results"`
+}
+
+// NewSplunkProvider takes a metric interval, a provider spec and the credentials map, and
+// returns a Splunk client ready to execute searches against the API
+func NewSplunkProvider(metricInterval string,
+	provider flaggerv1.MetricTemplateProvider,
+	credentials map[string][]byte) (*SplunkProvider, error) {
+	if provider.Address == "" {
+		return nil, fmt.Errorf("splunk endpoint is not set")
+	}
+
+	sp := SplunkProvider{
+		address:      strings.TrimSuffix(provider.Address, "/"),
+		timeout:      5 * time.Second,
+		pollInterval: splunkJobPollInterval,
+		jobTimeout:   splunkJobTimeout,
+	}
+
+	if b, ok := credentials[splunkTokenSecretKey]; ok {
+		sp.token = string(b)
+	} else {
+		return nil, fmt.Errorf("splunk credentials does not contain the key '%s'", splunkTokenSecretKey)
+	}
+
+	md, err := time.ParseDuration(metricInterval)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metric interval: %w", err)
+	}
+
+	sp.earliestTime = fmt.Sprintf("-%ds", int64(md.Seconds()))
+	return &sp, nil
+}
+
+// RunQuery creates a search job for the metric interval, waits for it to finish
+// and returns the single field of the first result as float64
+func (p *SplunkProvider) RunQuery(query string) (float64, error) {
+	query = strings.TrimSpace(query)
+	if !strings.HasPrefix(query, "search") && !strings.HasPrefix(query, "|") {
+		query = "search " + query
+	}
+
+	form := url.Values{}
+	form.Set("search", query)
+	form.Set("earliest_time", p.earliestTime)
+	form.Set("latest_time", "now")
+	form.Set("output_mode", "json")
+
+	b, err := p.do("POST", splunkSearchJobsPath, form)
+	if err != nil {
+		return 0, err
+	}
+
+	var job splunkJobResponse
+	if err := json.Unmarshal(b, &job); err != nil {
+		return 0, fmt.Errorf("error unmarshaling search job: %w, '%s'", err, string(b))
+	}
+	if job.Sid == "" {
+		return 0, fmt.Errorf("invalid search job response: %s", string(b))
+	}
+	// the job is no longer needed once the results have been read
+	defer p.do("DELETE", fmt.Sprintf("%s/%s", splunkSearchJobsPath, job.Sid), nil)
+
+	if err := p.waitForJob(job.Sid); err != nil {
+		return 0, err
+	}
+
+	b, err = p.do("GET", fmt.Sprintf("%s/%s/results", splunkSearchJobsPath, job.Sid),
+		url.Values{"output_mode": []string{"json"}, "count": []string{"1"}})
+	if err != nil {
+		return 0, err
+	}
+
+	var res splunkResultsResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
+	}
+
+	if len(res.Results) < 1 {
+		return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+	}
+
+	// ignore internal fields such as _time
+	var values []interface{}
+	for k, v := range res.Results[0] {
+		if !strings.HasPrefix(k, "_") {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) != 1 {
+		return 0, fmt.Errorf("invalid response: %s: search must return a single field", string(b))
+	}
+
+	s, ok := values[0].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid response: %s: %w", string(b), ErrNoValuesFound)
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing result '%s': %w", s, err)
+	}
+
+	return v, nil
+}
+
+// IsOnline calls the Splunk server info endpoint
+// and returns an error if the request is rejected
+func (p *SplunkProvider) IsOnline() (bool, error) {
+	if _, err := p.do("GET", splunkServerInfoPath, url.Values{"output_mode": []string{"json"}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *SplunkProvider) waitForJob(sid string) error {
+	deadline := time.Now().Add(p.jobTimeout)
+	for {
+		b, err := p.do("GET", fmt.Sprintf("%s/%s", splunkSearchJobsPath, sid),
+			url.Values{"output_mode": []string{"json"}})
+		if err != nil {
+			return err
+		}
+
+		var status splunkJobStatusResponse
+		if err := json.Unmarshal(b, &status); err != nil {
+			return fmt.Errorf("error unmarshaling search job status: %w, '%s'", err, string(b))
+		}
+		if len(status.Entry) < 1 {
+			return fmt.Errorf("invalid search job status: %s", string(b))
+		}
+
+		content := status.Entry[0].Content
+		if content.IsFailed || content.DispatchState == "FAILED" {
+			return fmt.Errorf("search job %s failed: %s", sid, string(b))
+		}
+		if content.IsDone {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("search job %s did not finish in %s", sid, p.jobTimeout)
+		}
+		time.Sleep(p.pollInterval)
+	}
+}
+
+func (p *SplunkProvider) do(method, path string, params url.Values) ([]byte, error) {
+	var body io.Reader
+	endpoint := p.address + path
+	if method == "POST" {
+		body = strings.NewReader(params.Encode())
+	} else if params != nil {
+		endpoint = endpoint + "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error http.NewRequest: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), p.timeout)
+	defer cancel()
+	r, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		return nil, fmt.Errorf("error response: %s", string(b))
+	}
+
+	return b, nil
+}
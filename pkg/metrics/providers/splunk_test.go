@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestNewSplunkProvider(t *testing.T) {
+	cs := map[string][]byte{
+		splunkTokenSecretKey: []byte("token"),
+	}
+
+	_, err := NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{}, cs)
+	require.Error(t, err)
+
+	_, err = NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{Address: "https://splunk:8089"}, map[string][]byte{})
+	require.Error(t, err)
+
+	sp, err := NewSplunkProvider("5m", flaggerv1.MetricTemplateProvider{Address: "https://splunk:8089/"}, cs)
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk:8089", sp.address)
+	assert.Equal(t, "-300s", sp.earliestTime)
+	assert.Equal(t, "token", sp.token)
+}
+
+func newSplunkTestServer(t *testing.T, results string, states ...string) *httptest.Server {
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == "POST" && r.URL.Path == splunkSearchJobsPath:
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "search index=main status>=500 | stats count", r.Form.Get("search"))
+			assert.Equal(t, "-60s", r.Form.Get("earliest_time"))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sid": "1234"}`))
+		case r.Method == "GET" && r.URL.Path == splunkSearchJobsPath+"/1234":
+			state := states[polls]
+			if polls < len(states)-1 {
+				polls++
+			}
+			w.Write([]byte(`{"entry": [{"content": ` + state + `}]}`))
+		case r.Method == "GET" && r.URL.Path == splunkSearchJobsPath+"/1234/results":
+			w.Write([]byte(results))
+		case r.Method == "DELETE":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestSplunkProvider_RunQuery(t *testing.T) {
+	query := "index=main status>=500 | stats count"
+	cs := map[string][]byte{
+		splunkTokenSecretKey: []byte("token"),
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		ts := newSplunkTestServer(t, `{"results": [{"count": "12"}]}`,
+			`{"isDone": false, "dispatchState": "RUNNING"}`,
+			`{"isDone": true, "dispatchState": "DONE"}`)
+		defer ts.Close()
+
+		sp, err := NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+		sp.pollInterval = time.Millisecond
+
+		f, err := sp.RunQuery(query)
+		require.NoError(t, err)
+		assert.Equal(t, float64(12), f)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		ts := newSplunkTestServer(t, `{"results": []}`, `{"isDone": true, "dispatchState": "DONE"}`)
+		defer ts.Close()
+
+		sp, err := NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery(query)
+		require.True(t, errors.Is(err, ErrNoValuesFound))
+	})
+
+	t.Run("failed job", func(t *testing.T) {
+		ts := newSplunkTestServer(t, `{"results": []}`, `{"isDone": true, "isFailed": true, "dispatchState": "FAILED"}`)
+		defer ts.Close()
+
+		sp, err := NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL}, cs)
+		require.NoError(t, err)
+
+		_, err = sp.RunQuery(query)
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrNoValuesFound))
+	})
+}
+
+func TestSplunkProvider_IsOnline(t *testing.T) {
+	for _, c := range []struct {
+		code        int
+		errExpected bool
+	}{
+		{code: http.StatusOK, errExpected: false},
+		{code: http.StatusUnauthorized, errExpected: true},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, splunkServerInfoPath, r.URL.Path)
+			w.WriteHeader(c.code)
+		}))
+
+		sp, err := NewSplunkProvider("1m", flaggerv1.MetricTemplateProvider{Address: ts.URL},
+			map[string][]byte{splunkTokenSecretKey: []byte("token")})
+		require.NoError(t, err)
+
+		_, err = sp.IsOnline()
+		if c.errExpected {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		ts.Close()
+	}
+}
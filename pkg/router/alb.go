@@ -0,0 +1,468 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+const (
+	albAnnotationsPrefix = "alb.ingress.kubernetes.io"
+	albUseAnnotation     = "use-annotation"
+)
+
+// ALBRouter is managing the forward actions of an AWS Load Balancer Controller ingress,
+// the traffic is split between the primary and canary target groups
+type ALBRouter struct {
+	kubeClient kubernetes.Interface
+	logger     *zap.SugaredLogger
+}
+
+// albAction is the alb.ingress.kubernetes.io/actions.<name> annotation value
+type albAction struct {
+	Type          string            `json:"type"`
+	ForwardConfig *albForwardConfig `json:"forwardConfig,omitempty"`
+}
+
+type albForwardConfig struct {
+	TargetGroups                []albTargetGroup          `json:"targetGroups"`
+	TargetGroupStickinessConfig *albTargetGroupStickiness `json:"targetGroupStickinessConfig,omitempty"`
+}
+
+type albTargetGroup struct {
+	ServiceName string `json:"serviceName"`
+	ServicePort string `json:"servicePort"`
+	Weight      int    `json:"weight"`
+}
+
+type albTargetGroupStickiness struct {
+	Enabled         bool `json:"enabled"`
+	DurationSeconds int  `json:"durationSeconds,omitempty"`
+}
+
+// albCondition is an item of the alb.ingress.kubernetes.io/conditions.<name> annotation value
+type albCondition struct {
+	Field                   string                      `json:"field"`
+	HTTPHeaderConfig        *albHTTPHeaderConfig        `json:"httpHeaderConfig,omitempty"`
+	HTTPRequestMethodConfig *albHTTPRequestMethodConfig `json:"httpRequestMethodConfig,omitempty"`
+	PathPatternConfig       *albPathPatternConfig       `json:"pathPatternConfig,omitempty"`
+	QueryStringConfig       *albQueryStringConfig       `json:"queryStringConfig,omitempty"`
+}
+
+type albHTTPHeaderConfig struct {
+	HTTPHeaderName string   `json:"httpHeaderName"`
+	Values         []string `json:"values"`
+}
+
+type albHTTPRequestMethodConfig struct {
+	Values []string `json:"values"`
+}
+
+type albPathPatternConfig struct {
+	Values []string `json:"values"`
+}
+
+type albQueryStringConfig struct {
+	Values []albQueryStringKeyValuePair `json:"values"`
+}
+
+type albQueryStringKeyValuePair struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// Reconcile sets the forward action for the apex service and, for A/B testing,
+// adds the rules that route the requests matching the analysis conditions to the canary
+func (ar *ALBRouter) Reconcile(canary *flaggerv1.Canary) error {
+	if canary.Spec.IngressRef == nil || canary.Spec.IngressRef.Name == "" {
+		return fmt.Errorf("ingress selector is empty")
+	}
+
+	apexName, _, _ := canary.GetServiceNames()
+	ingressName := canary.Spec.IngressRef.Name
+
+	ingress, err := ar.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+	}
+
+	clone := ingress.DeepCopy()
+	if clone.Annotations == nil {
+		clone.Annotations = make(map[string]string)
+	}
+
+	// keep the current weights
+	primaryWeight, canaryWeight := int(initialPrimaryWeight), int(initialCanaryWeight)
+	if action, err := ar.getAction(ingress, ar.weightedActionName(canary)); err == nil {
+		primaryWeight, canaryWeight = ar.getWeights(canary, action)
+	}
+
+	rules, err := ar.makeRules(canary, clone.Spec.Rules)
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s: %w", ingressName, canary.Namespace, err)
+	}
+	clone.Spec.Rules = rules
+
+	if err := ar.setActions(canary, clone, primaryWeight, canaryWeight); err != nil {
+		return err
+	}
+
+	if cmp.Diff(ingress.Spec, clone.Spec) == "" && cmp.Diff(ingress.Annotations, clone.Annotations) == "" {
+		return nil
+	}
+
+	_, err = ar.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s update error: %w", ingressName, canary.Namespace, err)
+	}
+
+	ar.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+		Infof("Ingress %s.%s actions for %s updated", ingressName, canary.Namespace, apexName)
+	return nil
+}
+
+// GetRoutes returns the destinations weight for primary and canary
+func (ar *ALBRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := ar.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+		return
+	}
+
+	action, err := ar.getAction(ingress, ar.weightedActionName(canary))
+	if err != nil {
+		return
+	}
+
+	primaryWeight, canaryWeight = ar.getWeights(canary, action)
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary
+func (ar *ALBRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := ar.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+	}
+
+	clone := ingress.DeepCopy()
+	if clone.Annotations == nil {
+		clone.Annotations = make(map[string]string)
+	}
+	if err := ar.setActions(canary, clone, primaryWeight, canaryWeight); err != nil {
+		return err
+	}
+
+	_, err = ar.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s update error: %w", ingressName, canary.Namespace, err)
+	}
+
+	return nil
+}
+
+func (ar *ALBRouter) Finalize(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+func (ar *ALBRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// actionName returns the name of the action referenced by the ingress rules for the apex service
+func (ar *ALBRouter) actionName(canary *flaggerv1.Canary) string {
+	apexName, _, _ := canary.GetServiceNames()
+	return apexName
+}
+
+// abActionName returns the name of the action used for the A/B testing rules
+func (ar *ALBRouter) abActionName(canary *flaggerv1.Canary) string {
+	return fmt.Sprintf("%s-ab", ar.actionName(canary))
+}
+
+// weightedActionName returns the name of the action that splits the traffic during the analysis
+func (ar *ALBRouter) weightedActionName(canary *flaggerv1.Canary) string {
+	if len(canary.GetAnalysis().Match) > 0 {
+		return ar.abActionName(canary)
+	}
+	return ar.actionName(canary)
+}
+
+func (ar *ALBRouter) annotation(kind string, name string) string {
+	return fmt.Sprintf("%s/%s.%s", albAnnotationsPrefix, kind, name)
+}
+
+// makeRules removes the A/B testing paths from the ingress rules and,
+// if the analysis has match conditions, adds them back in front of the apex paths
+func (ar *ALBRouter) makeRules(canary *flaggerv1.Canary, rules []netv1.IngressRule) ([]netv1.IngressRule, error) {
+	actionName := ar.actionName(canary)
+	abActionName := ar.abActionName(canary)
+	abTesting := len(canary.GetAnalysis().Match) > 0
+
+	found := false
+	result := make([]netv1.IngressRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.HTTP == nil {
+			result = append(result, rule)
+			continue
+		}
+
+		var paths []netv1.HTTPIngressPath
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				paths = append(paths, path)
+				continue
+			}
+			switch path.Backend.Service.Name {
+			case abActionName:
+				continue
+			case actionName:
+				if path.Backend.Service.Port.Name != albUseAnnotation {
+					return nil, fmt.Errorf("backend %s must use the port name %s", actionName, albUseAnnotation)
+				}
+				found = true
+				if abTesting {
+					abPath := *path.DeepCopy()
+					abPath.Backend.Service.Name = abActionName
+					paths = append(paths, abPath)
+				}
+			}
+			paths = append(paths, path)
+		}
+
+		clone := *rule.DeepCopy()
+		clone.HTTP.Paths = paths
+		result = append(result, clone)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("backend %s not found", actionName)
+	}
+
+	return result, nil
+}
+
+// setActions writes the forward actions and the A/B testing conditions to the ingress annotations
+func (ar *ALBRouter) setActions(canary *flaggerv1.Canary, ingress *netv1.Ingress, primaryWeight int, canaryWeight int) error {
+	actionName := ar.actionName(canary)
+	abActionName := ar.abActionName(canary)
+
+	delete(ingress.Annotations, ar.annotation("actions", abActionName))
+	delete(ingress.Annotations, ar.annotation("conditions", abActionName))
+
+	if len(canary.GetAnalysis().Match) > 0 {
+		conditions, err := ar.makeConditions(canary.GetAnalysis().Match)
+		if err != nil {
+			return fmt.Errorf("invalid request matching selectors: %w", err)
+		}
+		conditionsJSON, err := json.Marshal(conditions)
+		if err != nil {
+			return fmt.Errorf("conditions %s marshal error: %w", abActionName, err)
+		}
+		ingress.Annotations[ar.annotation("conditions", abActionName)] = string(conditionsJSON)
+
+		abAction, err := json.Marshal(ar.makeAction(canary, primaryWeight, canaryWeight))
+		if err != nil {
+			return fmt.Errorf("action %s marshal error: %w", abActionName, err)
+		}
+		ingress.Annotations[ar.annotation("actions", abActionName)] = string(abAction)
+
+		// the requests that don't match the conditions are routed to the primary
+		primaryWeight, canaryWeight = int(initialPrimaryWeight), int(initialCanaryWeight)
+	}
+
+	action, err := json.Marshal(ar.makeAction(canary, primaryWeight, canaryWeight))
+	if err != nil {
+		return fmt.Errorf("action %s marshal error: %w", actionName, err)
+	}
+	ingress.Annotations[ar.annotation("actions", actionName)] = string(action)
+
+	return nil
+}
+
+func (ar *ALBRouter) makeAction(canary *flaggerv1.Canary, primaryWeight int, canaryWeight int) albAction {
+	_, primaryName, canaryName := canary.GetServiceNames()
+	port := strconv.Itoa(int(canary.Spec.Service.Port))
+
+	action := albAction{
+		Type: "forward",
+		ForwardConfig: &albForwardConfig{
+			TargetGroups: []albTargetGroup{
+				{
+					ServiceName: primaryName,
+					ServicePort: port,
+					Weight:      primaryWeight,
+				},
+				{
+					ServiceName: canaryName,
+					ServicePort: port,
+					Weight:      canaryWeight,
+				},
+			},
+		},
+	}
+
+	if sa := canary.GetAnalysis().SessionAffinity; sa != nil {
+		action.ForwardConfig.TargetGroupStickinessConfig = &albTargetGroupStickiness{
+			Enabled:         true,
+			DurationSeconds: sa.GetMaxAge(),
+		}
+	}
+
+	return action
+}
+
+func (ar *ALBRouter) getAction(ingress *netv1.Ingress, name string) (*albAction, error) {
+	value, ok := ingress.Annotations[ar.annotation("actions", name)]
+	if !ok {
+		return nil, fmt.Errorf("ingress %s.%s action %s not found", ingress.Name, ingress.Namespace, name)
+	}
+
+	action := &albAction{}
+	if err := json.Unmarshal([]byte(value), action); err != nil {
+		return nil, fmt.Errorf("ingress %s.%s action %s unmarshal error: %w", ingress.Name, ingress.Namespace, name, err)
+	}
+	if action.ForwardConfig == nil {
+		return nil, fmt.Errorf("ingress %s.%s action %s has no forward config", ingress.Name, ingress.Namespace, name)
+	}
+
+	return action, nil
+}
+
+func (ar *ALBRouter) getWeights(canary *flaggerv1.Canary, action *albAction) (primaryWeight int, canaryWeight int) {
+	_, primaryName, canaryName := canary.GetServiceNames()
+	for _, tg := range action.ForwardConfig.TargetGroups {
+		switch tg.ServiceName {
+		case primaryName:
+			primaryWeight = tg.Weight
+		case canaryName:
+			canaryWeight = tg.Weight
+		}
+	}
+	return
+}
+
+// makeConditions maps the analysis match conditions to ALB rule conditions,
+// all conditions belong to the same rule so a request must satisfy every one of them,
+// a cookie match is translated to a Cookie header wildcard like the NGINX canary-by-cookie
+func (ar *ALBRouter) makeConditions(requestMatches []v1alpha3.HTTPMatchRequest) ([]albCondition, error) {
+	var conditions []albCondition
+
+	for _, requestMatch := range requestMatches {
+		if requestMatch.Uri != nil {
+			var value string
+			switch {
+			case requestMatch.Uri.Exact != "":
+				value = requestMatch.Uri.Exact
+			case requestMatch.Uri.Prefix != "":
+				value = requestMatch.Uri.Prefix + "*"
+			default:
+				return nil, fmt.Errorf("ALB doesn't support the specified path matching selector: %+v", requestMatch.Uri)
+			}
+			conditions = append(conditions, albCondition{
+				Field:             "path-pattern",
+				PathPatternConfig: &albPathPatternConfig{Values: []string{value}},
+			})
+		}
+
+		if requestMatch.Method != nil {
+			if requestMatch.Method.Exact == "" {
+				return nil, fmt.Errorf("ALB doesn't support the specified method matching selector: %+v", requestMatch.Method)
+			}
+			conditions = append(conditions, albCondition{
+				Field:                   "http-request-method",
+				HTTPRequestMethodConfig: &albHTTPRequestMethodConfig{Values: []string{strings.ToUpper(requestMatch.Method.Exact)}},
+			})
+		}
+
+		// sort the headers to generate a stable annotation
+		headers := make([]string, 0, len(requestMatch.Headers))
+		for key := range requestMatch.Headers {
+			headers = append(headers, key)
+		}
+		sort.Strings(headers)
+		for _, key := range headers {
+			val := requestMatch.Headers[key]
+			var value string
+			switch {
+			case strings.EqualFold(key, "cookie") && val.Exact != "":
+				value = fmt.Sprintf("*%s=always*", val.Exact)
+			case val.Exact != "":
+				value = val.Exact
+			case val.Prefix != "":
+				value = val.Prefix + "*"
+			case val.Suffix != "":
+				value = "*" + val.Suffix
+			default:
+				return nil, fmt.Errorf("ALB doesn't support the specified header matching selector: %+v", val)
+			}
+			conditions = append(conditions, albCondition{
+				Field: "http-header",
+				HTTPHeaderConfig: &albHTTPHeaderConfig{
+					HTTPHeaderName: key,
+					Values:         []string{value},
+				},
+			})
+		}
+
+		params := make([]string, 0, len(requestMatch.QueryParams))
+		for key := range requestMatch.QueryParams {
+			params = append(params, key)
+		}
+		sort.Strings(params)
+		for _, key := range params {
+			val := requestMatch.QueryParams[key]
+			if val.Exact == "" {
+				return nil, fmt.Errorf("ALB doesn't support the specified query matching selector: %+v", val)
+			}
+			conditions = append(conditions, albCondition{
+				Field: "query-string",
+				QueryStringConfig: &albQueryStringConfig{
+					Values: []albQueryStringKeyValuePair{{Key: key, Value: val.Exact}},
+				},
+			})
+		}
+	}
+
+	return conditions, nil
+}
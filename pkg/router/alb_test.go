@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+func newTestALBRouter(t *testing.T, mocks fixture) *ALBRouter {
+	ingress, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port = netv1.ServiceBackendPort{Name: albUseAnnotation}
+	_, err = mocks.kubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	return &ALBRouter{
+		logger:     mocks.logger,
+		kubeClient: mocks.kubeClient,
+	}
+}
+
+func TestALBRouter_Reconcile(t *testing.T) {
+	mocks := newFixture(nil)
+	router := newTestALBRouter(t, mocks)
+
+	err := router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingress, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	action := &albAction{}
+	require.NoError(t, json.Unmarshal([]byte(ingress.Annotations["alb.ingress.kubernetes.io/actions.podinfo"]), action))
+	assert.Equal(t, "forward", action.Type)
+	require.Len(t, action.ForwardConfig.TargetGroups, 2)
+	assert.Equal(t, "podinfo-primary", action.ForwardConfig.TargetGroups[0].ServiceName)
+	assert.Equal(t, "9898", action.ForwardConfig.TargetGroups[0].ServicePort)
+	assert.Equal(t, 100, action.ForwardConfig.TargetGroups[0].Weight)
+	assert.Equal(t, "podinfo-canary", action.ForwardConfig.TargetGroups[1].ServiceName)
+	assert.Equal(t, 0, action.ForwardConfig.TargetGroups[1].Weight)
+	assert.Nil(t, action.ForwardConfig.TargetGroupStickinessConfig)
+
+	// weights are kept when the spec changes
+	err = router.SetRoutes(mocks.ingressCanary, 70, 30, false)
+	require.NoError(t, err)
+
+	mocks.ingressCanary.Spec.Analysis.SessionAffinity = &flaggerv1.SessionAffinity{
+		CookieName: "flagger-cookie",
+		MaxAge:     120,
+	}
+	err = router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingress, err = mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	action = &albAction{}
+	require.NoError(t, json.Unmarshal([]byte(ingress.Annotations["alb.ingress.kubernetes.io/actions.podinfo"]), action))
+	assert.Equal(t, 70, action.ForwardConfig.TargetGroups[0].Weight)
+	assert.Equal(t, 30, action.ForwardConfig.TargetGroups[1].Weight)
+	require.NotNil(t, action.ForwardConfig.TargetGroupStickinessConfig)
+	assert.True(t, action.ForwardConfig.TargetGroupStickinessConfig.Enabled)
+	assert.Equal(t, 120, action.ForwardConfig.TargetGroupStickinessConfig.DurationSeconds)
+}
+
+func TestALBRouter_Routes(t *testing.T) {
+	mocks := newFixture(nil)
+	router := newTestALBRouter(t, mocks)
+
+	err := router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(mocks.ingressCanary, 50, 50, false)
+	require.NoError(t, err)
+
+	p, c, m, err := router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 50, p)
+	assert.Equal(t, 50, c)
+	assert.False(t, m)
+}
+
+func TestALBRouter_ABTest(t *testing.T) {
+	mocks := newFixture(nil)
+	router := newTestALBRouter(t, mocks)
+
+	mocks.ingressCanary.Spec.Analysis.Iterations = 10
+	mocks.ingressCanary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-user-type": {Exact: "insider"},
+				"cookie":      {Exact: "canary"},
+			},
+		},
+	}
+
+	err := router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(mocks.ingressCanary, 0, 100, false)
+	require.NoError(t, err)
+
+	ingress, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the A/B testing path is evaluated before the apex path
+	paths := ingress.Spec.Rules[0].HTTP.Paths
+	require.Len(t, paths, 2)
+	assert.Equal(t, "podinfo-ab", paths[0].Backend.Service.Name)
+	assert.Equal(t, albUseAnnotation, paths[0].Backend.Service.Port.Name)
+	assert.Equal(t, "podinfo", paths[1].Backend.Service.Name)
+
+	var conditions []albCondition
+	require.NoError(t, json.Unmarshal([]byte(ingress.Annotations["alb.ingress.kubernetes.io/conditions.podinfo-ab"]), &conditions))
+	require.Len(t, conditions, 2)
+	assert.Equal(t, "cookie", conditions[0].HTTPHeaderConfig.HTTPHeaderName)
+	assert.Equal(t, []string{"*canary=always*"}, conditions[0].HTTPHeaderConfig.Values)
+	assert.Equal(t, "x-user-type", conditions[1].HTTPHeaderConfig.HTTPHeaderName)
+	assert.Equal(t, []string{"insider"}, conditions[1].HTTPHeaderConfig.Values)
+
+	// the requests that don't match the conditions are routed to the primary
+	action := &albAction{}
+	require.NoError(t, json.Unmarshal([]byte(ingress.Annotations["alb.ingress.kubernetes.io/actions.podinfo"]), action))
+	assert.Equal(t, 100, action.ForwardConfig.TargetGroups[0].Weight)
+
+	p, c, _, err := router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 0, p)
+	assert.Equal(t, 100, c)
+
+	// the A/B testing rule is removed when the analysis has no conditions
+	mocks.ingressCanary.Spec.Analysis.Match = nil
+	err = router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingress, err = mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, ingress.Spec.Rules[0].HTTP.Paths, 1)
+	assert.NotContains(t, ingress.Annotations, "alb.ingress.kubernetes.io/actions.podinfo-ab")
+	assert.NotContains(t, ingress.Annotations, "alb.ingress.kubernetes.io/conditions.podinfo-ab")
+}
@@ -227,3 +227,7 @@ func (ar *ApisixRouter) SetRoutes(
 func (ar *ApisixRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (ar *ApisixRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
@@ -535,6 +535,10 @@ func (ar *AppMeshRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
 
+func (ar *AppMeshRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
 func int64p(i int64) *int64 {
 	return &i
 }
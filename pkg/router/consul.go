@@ -0,0 +1,452 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	consulv1alpha1 "github.com/fluxcd/flagger/pkg/apis/consul/v1alpha1"
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+const (
+	consulPrimarySubset = "primary"
+	consulCanarySubset  = "canary"
+)
+
+// ConsulRouter is managing Consul service-resolver, service-splitter and service-router config entries
+type ConsulRouter struct {
+	kubeClient   kubernetes.Interface
+	consulClient clientset.Interface
+	logger       *zap.SugaredLogger
+	setOwnerRefs bool
+}
+
+// Reconcile creates or updates the Consul config entries
+func (cr *ConsulRouter) Reconcile(canary *flaggerv1.Canary) error {
+	if err := cr.reconcileServiceResolver(canary); err != nil {
+		return err
+	}
+	if err := cr.reconcileServiceSplitter(canary); err != nil {
+		return err
+	}
+	return cr.reconcileServiceRouter(canary)
+}
+
+// reconcileServiceResolver defines the primary and canary subsets of the apex service,
+// the instances are selected by the Kubernetes service name recorded by consul-k8s
+func (cr *ConsulRouter) reconcileServiceResolver(canary *flaggerv1.Canary) error {
+	apexName, primaryName, canaryName := canary.GetServiceNames()
+
+	newSpec := consulv1alpha1.ServiceResolverSpec{
+		DefaultSubset: consulPrimarySubset,
+		Subsets: map[string]consulv1alpha1.ServiceResolverSubset{
+			consulPrimarySubset: {
+				Filter:      fmt.Sprintf(`Service.Meta.k8s-service-name == "%s"`, primaryName),
+				OnlyPassing: true,
+			},
+			consulCanarySubset: {
+				Filter:      fmt.Sprintf(`Service.Meta.k8s-service-name == "%s"`, canaryName),
+				OnlyPassing: true,
+			},
+		},
+	}
+
+	resolver, err := cr.consulClient.ConsulV1alpha1().ServiceResolvers(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		resolver = &consulv1alpha1.ServiceResolver{
+			ObjectMeta: cr.makeObjectMeta(canary),
+			Spec:       newSpec,
+		}
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceResolvers(canary.Namespace).Create(context.TODO(), resolver, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceResolver %s.%s create error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceResolver %s.%s created", apexName, canary.Namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("ServiceResolver %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	if diff := cmp.Diff(newSpec, resolver.Spec); diff != "" {
+		clone := resolver.DeepCopy()
+		clone.Spec = newSpec
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceResolvers(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceResolver %s.%s update error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceResolver %s.%s updated", apexName, canary.Namespace)
+	}
+
+	return nil
+}
+
+// reconcileServiceSplitter creates the splitter that shifts traffic between subsets,
+// the weights of an existing splitter are managed by SetRoutes
+func (cr *ConsulRouter) reconcileServiceSplitter(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	newSpec := cr.makeSplitterSpec(100, 0)
+
+	splitter, err := cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		splitter = &consulv1alpha1.ServiceSplitter{
+			ObjectMeta: cr.makeObjectMeta(canary),
+			Spec:       newSpec,
+		}
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Create(context.TODO(), splitter, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceSplitter %s.%s create error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceSplitter %s.%s created", apexName, canary.Namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("ServiceSplitter %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	if diff := cmp.Diff(
+		newSpec,
+		splitter.Spec,
+		cmpopts.IgnoreFields(consulv1alpha1.ServiceSplit{}, "Weight"),
+	); diff != "" {
+		clone := splitter.DeepCopy()
+		clone.Spec = newSpec
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceSplitter %s.%s update error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceSplitter %s.%s updated", apexName, canary.Namespace)
+	}
+
+	return nil
+}
+
+// reconcileServiceRouter creates a router for A/B testing that sends the matching requests
+// through the splitter and all the other requests to the primary subset,
+// the router is removed when the analysis has no match conditions
+func (cr *ConsulRouter) reconcileServiceRouter(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	router, err := cr.consulClient.ConsulV1alpha1().ServiceRouters(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("ServiceRouter %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+	exists := err == nil
+
+	if len(canary.GetAnalysis().Match) == 0 {
+		if !exists {
+			return nil
+		}
+		err = cr.consulClient.ConsulV1alpha1().ServiceRouters(canary.Namespace).Delete(context.TODO(), apexName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("ServiceRouter %s.%s delete error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceRouter %s.%s deleted", apexName, canary.Namespace)
+		return nil
+	}
+
+	newSpec, err := cr.makeRouterSpec(canary)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		router = &consulv1alpha1.ServiceRouter{
+			ObjectMeta: cr.makeObjectMeta(canary),
+			Spec:       newSpec,
+		}
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceRouters(canary.Namespace).Create(context.TODO(), router, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceRouter %s.%s create error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceRouter %s.%s created", apexName, canary.Namespace)
+		return nil
+	}
+
+	if diff := cmp.Diff(newSpec, router.Spec); diff != "" {
+		clone := router.DeepCopy()
+		clone.Spec = newSpec
+		_, err = cr.consulClient.ConsulV1alpha1().ServiceRouters(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("ServiceRouter %s.%s update error: %w", apexName, canary.Namespace, err)
+		}
+		cr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("ServiceRouter %s.%s updated", apexName, canary.Namespace)
+	}
+
+	return nil
+}
+
+// GetRoutes returns the destinations weight for primary and canary
+func (cr *ConsulRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	apexName, _, _ := canary.GetServiceNames()
+
+	splitter, err := cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("ServiceSplitter %s.%s get query error: %w", apexName, canary.Namespace, err)
+		return
+	}
+
+	primaryWeight, canaryWeight = -1, -1
+	for _, split := range splitter.Spec.Splits {
+		switch split.ServiceSubset {
+		case consulPrimarySubset:
+			primaryWeight = int(split.Weight)
+		case consulCanarySubset:
+			canaryWeight = int(split.Weight)
+		}
+	}
+
+	if primaryWeight < 0 || canaryWeight < 0 {
+		err = fmt.Errorf("ServiceSplitter %s.%s does not contain splits for the %s and %s subsets",
+			apexName, canary.Namespace, consulPrimarySubset, consulCanarySubset)
+		return 0, 0, false, err
+	}
+
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary
+func (cr *ConsulRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	splitter, err := cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ServiceSplitter %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	clone := splitter.DeepCopy()
+	clone.Spec = cr.makeSplitterSpec(primaryWeight, canaryWeight)
+
+	_, err = cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ServiceSplitter %s.%s update error: %w", apexName, canary.Namespace, err)
+	}
+
+	return nil
+}
+
+func (cr *ConsulRouter) Finalize(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// IsRouteReady checks if the ServiceSplitter was synced to Consul
+func (cr *ConsulRouter) IsRouteReady(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	splitter, err := cr.consulClient.ConsulV1alpha1().ServiceSplitters(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ServiceSplitter %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	for _, condition := range splitter.Status.Conditions {
+		if condition.Type != consulv1alpha1.ConditionSynced {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+		return fmt.Errorf("ServiceSplitter %s.%s not synced with Consul: %s %s: %w",
+			apexName, canary.Namespace, condition.Reason, condition.Message, ErrRouteNotReady)
+	}
+
+	return fmt.Errorf("ServiceSplitter %s.%s not synced with Consul: %w", apexName, canary.Namespace, ErrRouteNotReady)
+}
+
+func (cr *ConsulRouter) makeSplitterSpec(primaryWeight int, canaryWeight int) consulv1alpha1.ServiceSplitterSpec {
+	return consulv1alpha1.ServiceSplitterSpec{
+		Splits: []consulv1alpha1.ServiceSplit{
+			{
+				Weight:        float32(primaryWeight),
+				ServiceSubset: consulPrimarySubset,
+			},
+			{
+				Weight:        float32(canaryWeight),
+				ServiceSubset: consulCanarySubset,
+			},
+		},
+	}
+}
+
+func (cr *ConsulRouter) makeRouterSpec(canary *flaggerv1.Canary) (consulv1alpha1.ServiceRouterSpec, error) {
+	apexName, _, _ := canary.GetServiceNames()
+
+	matches, err := cr.mapRouteMatches(canary.GetAnalysis().Match)
+	if err != nil {
+		return consulv1alpha1.ServiceRouterSpec{}, fmt.Errorf("invalid request matching selectors: %w", err)
+	}
+
+	var routes []consulv1alpha1.ServiceRoute
+	for _, match := range matches {
+		match := match
+		routes = append(routes, consulv1alpha1.ServiceRoute{
+			Match: &consulv1alpha1.ServiceRouteMatch{HTTP: &match},
+			Destination: &consulv1alpha1.ServiceRouteDestination{
+				Service: apexName,
+			},
+		})
+	}
+
+	// the requests that don't match are sent to the primary subset bypassing the splitter
+	routes = append(routes, consulv1alpha1.ServiceRoute{
+		Match: &consulv1alpha1.ServiceRouteMatch{
+			HTTP: &consulv1alpha1.ServiceRouteHTTPMatch{PathPrefix: "/"},
+		},
+		Destination: &consulv1alpha1.ServiceRouteDestination{
+			Service:       apexName,
+			ServiceSubset: consulPrimarySubset,
+		},
+	})
+
+	return consulv1alpha1.ServiceRouterSpec{Routes: routes}, nil
+}
+
+func (cr *ConsulRouter) mapRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]consulv1alpha1.ServiceRouteHTTPMatch, error) {
+	var matches []consulv1alpha1.ServiceRouteHTTPMatch
+
+	for _, requestMatch := range requestMatches {
+		match := consulv1alpha1.ServiceRouteHTTPMatch{}
+		if requestMatch.Uri != nil {
+			switch {
+			case requestMatch.Uri.Exact != "":
+				match.PathExact = requestMatch.Uri.Exact
+			case requestMatch.Uri.Prefix != "":
+				match.PathPrefix = requestMatch.Uri.Prefix
+			case requestMatch.Uri.Regex != "":
+				match.PathRegex = requestMatch.Uri.Regex
+			default:
+				return nil, fmt.Errorf("Consul doesn't support the specified path matching selector: %+v", requestMatch.Uri)
+			}
+		}
+
+		if requestMatch.Method != nil {
+			if requestMatch.Method.Exact == "" {
+				return nil, fmt.Errorf("Consul doesn't support the specified method matching selector: %+v", requestMatch.Method)
+			}
+			match.Methods = []string{strings.ToUpper(requestMatch.Method.Exact)}
+		}
+
+		// sort the headers to generate a stable spec
+		headers := make([]string, 0, len(requestMatch.Headers))
+		for key := range requestMatch.Headers {
+			headers = append(headers, key)
+		}
+		sort.Strings(headers)
+		for _, key := range headers {
+			val := requestMatch.Headers[key]
+			header := consulv1alpha1.ServiceRouteHTTPMatchHeader{Name: key}
+			switch {
+			case val.Exact != "":
+				header.Exact = val.Exact
+			case val.Prefix != "":
+				header.Prefix = val.Prefix
+			case val.Suffix != "":
+				header.Suffix = val.Suffix
+			case val.Regex != "":
+				header.Regex = val.Regex
+			default:
+				return nil, fmt.Errorf("Consul doesn't support the specified header matching selector: %+v", val)
+			}
+			match.Header = append(match.Header, header)
+		}
+
+		params := make([]string, 0, len(requestMatch.QueryParams))
+		for key := range requestMatch.QueryParams {
+			params = append(params, key)
+		}
+		sort.Strings(params)
+		for _, key := range params {
+			val := requestMatch.QueryParams[key]
+			param := consulv1alpha1.ServiceRouteHTTPMatchQueryParam{Name: key}
+			switch {
+			case val.Exact != "":
+				param.Exact = val.Exact
+			case val.Regex != "":
+				param.Regex = val.Regex
+			default:
+				return nil, fmt.Errorf("Consul doesn't support the specified query matching selector: %+v", val)
+			}
+			match.QueryParam = append(match.QueryParam, param)
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+func (cr *ConsulRouter) makeObjectMeta(canary *flaggerv1.Canary) metav1.ObjectMeta {
+	apexName, _, _ := canary.GetServiceNames()
+
+	metadata := canary.Spec.Service.Apex
+	if metadata == nil {
+		metadata = &flaggerv1.CustomMetadata{}
+	}
+	if metadata.Labels == nil {
+		metadata.Labels = make(map[string]string)
+	}
+	if metadata.Annotations == nil {
+		metadata.Annotations = make(map[string]string)
+	}
+
+	objectMeta := metav1.ObjectMeta{
+		Name:        apexName,
+		Namespace:   canary.Namespace,
+		Labels:      metadata.Labels,
+		Annotations: filterMetadata(metadata.Annotations),
+	}
+	if cr.setOwnerRefs {
+		objectMeta.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(canary, schema.GroupVersionKind{
+				Group:   flaggerv1.SchemeGroupVersion.Group,
+				Version: flaggerv1.SchemeGroupVersion.Version,
+				Kind:    flaggerv1.CanaryKind,
+			}),
+		}
+	}
+	return objectMeta
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	consulv1alpha1 "github.com/fluxcd/flagger/pkg/apis/consul/v1alpha1"
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+func TestConsulRouter_Reconcile(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+	router := &ConsulRouter{
+		logger:       mocks.logger,
+		consulClient: mocks.meshClient,
+		kubeClient:   mocks.kubeClient,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	resolver, err := router.consulClient.ConsulV1alpha1().ServiceResolvers("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "primary", resolver.Spec.DefaultSubset)
+	require.Len(t, resolver.Spec.Subsets, 2)
+	assert.Equal(t, `Service.Meta.k8s-service-name == "podinfo-canary"`, resolver.Spec.Subsets["canary"].Filter)
+
+	splitter, err := router.consulClient.ConsulV1alpha1().ServiceSplitters("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, splitter.Spec.Splits, 2)
+	assert.Equal(t, float32(100), splitter.Spec.Splits[0].Weight)
+	assert.Equal(t, "canary", splitter.Spec.Splits[1].ServiceSubset)
+
+	// the router is only created for A/B testing
+	_, err = router.consulClient.ConsulV1alpha1().ServiceRouters("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// weights are kept when the canary is progressing
+	err = router.SetRoutes(canary, 60, 40, false)
+	require.NoError(t, err)
+
+	canary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "insider"},
+			},
+		},
+	}
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+
+	serviceRouter, err := router.consulClient.ConsulV1alpha1().ServiceRouters("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	routes := serviceRouter.Spec.Routes
+	require.Len(t, routes, 2)
+	assert.Equal(t, "x-canary", routes[0].Match.HTTP.Header[0].Name)
+	assert.Equal(t, "insider", routes[0].Match.HTTP.Header[0].Exact)
+	assert.Empty(t, routes[0].Destination.ServiceSubset)
+	assert.Equal(t, "primary", routes[1].Destination.ServiceSubset)
+
+	// the router is removed when A/B testing is disabled
+	canary.Spec.Analysis.Match = nil
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	_, err = router.consulClient.ConsulV1alpha1().ServiceRouters("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestConsulRouter_Routes(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+	router := &ConsulRouter{
+		logger:       mocks.logger,
+		consulClient: mocks.meshClient,
+		kubeClient:   mocks.kubeClient,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(canary, 50, 50, false)
+	require.NoError(t, err)
+
+	p, c, m, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 50, p)
+	assert.Equal(t, 50, c)
+	assert.False(t, m)
+}
+
+func TestConsulRouter_IsRouteReady(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+	router := &ConsulRouter{
+		logger:       mocks.logger,
+		consulClient: mocks.meshClient,
+		kubeClient:   mocks.kubeClient,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+
+	splitter, err := router.consulClient.ConsulV1alpha1().ServiceSplitters("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	splitter.Status.Conditions = []consulv1alpha1.Condition{
+		{Type: consulv1alpha1.ConditionSynced, Status: corev1.ConditionFalse, Reason: "ConsulAgentError"},
+	}
+	splitter, err = router.consulClient.ConsulV1alpha1().ServiceSplitters("default").UpdateStatus(context.TODO(), splitter, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+	assert.Contains(t, err.Error(), "ConsulAgentError")
+
+	splitter.Status.Conditions[0].Status = corev1.ConditionTrue
+	_, err = router.consulClient.ConsulV1alpha1().ServiceSplitters("default").UpdateStatus(context.TODO(), splitter, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.NoError(t, err)
+}
@@ -476,3 +476,17 @@ func (cr *ContourRouter) makeLinkerdHeaderValue(canary *flaggerv1.Canary, servic
 func (cr *ContourRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+// IsRouteReady checks if the HTTPProxy was marked as invalid by Contour
+func (cr *ContourRouter) IsRouteReady(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+	proxy, err := cr.contourClient.ProjectcontourV1().HTTPProxies(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("HTTPProxy %s.%s get error: %w", apexName, canary.Namespace, err)
+	}
+
+	if proxy.Status.CurrentStatus == "invalid" {
+		return fmt.Errorf("HTTPProxy %s.%s is invalid: %s: %w", apexName, canary.Namespace, proxy.Status.Description, ErrRouteNotReady)
+	}
+	return nil
+}
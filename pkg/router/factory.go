@@ -74,6 +74,55 @@ func (factory *Factory) KubernetesRouter(kind string, labelSelector string, labe
 	}
 }
 
+// MeshRouters returns a router that shifts the traffic on all the given providers
+func (factory *Factory) MeshRouters(providers []string, labelSelector string) Interface {
+	if len(providers) == 1 {
+		return factory.MeshRouter(providers[0], labelSelector)
+	}
+
+	routers := make([]providerRouter, 0, len(providers))
+	for _, provider := range providers {
+		routers = append(routers, providerRouter{
+			provider: provider,
+			router:   factory.MeshRouter(provider, labelSelector),
+		})
+	}
+	return &MultiRouter{
+		logger:  factory.logger,
+		routers: routers,
+	}
+}
+
+// CanaryRouters returns the mesh routers of the providers, when a DaemonSet canary
+// is rolled out to a subset of nodes the node router takes the place of the Kubernetes provider
+func (factory *Factory) CanaryRouters(canary *flaggerv1.Canary, providers []string, labelSelector string) Interface {
+	if !canary.HasNodeRollout() {
+		return factory.MeshRouters(providers, labelSelector)
+	}
+
+	nodeRouter := &NodeRouter{
+		kubeClient: factory.kubeClient,
+		logger:     factory.logger,
+	}
+	routers := []providerRouter{{provider: "nodes", router: nodeRouter}}
+	for _, provider := range providers {
+		if provider == flaggerv1.KubernetesProvider {
+			continue
+		}
+		routers = append(routers, providerRouter{
+			provider: provider,
+			router:   factory.MeshRouter(provider, labelSelector),
+		})
+	}
+	if len(routers) == 1 {
+		return nodeRouter
+	}
+	return &MultiRouter{
+		logger:  factory.logger,
+		routers: routers,
+	}
+}
+
 // MeshRouter returns a service mesh router
 func (factory *Factory) MeshRouter(provider string, labelSelector string) Interface {
 	switch {
@@ -94,6 +143,14 @@ func (factory *Factory) MeshRouter(provider string, labelSelector string) Interf
 			appmeshClient: factory.meshClient,
 			setOwnerRefs:  factory.setOwnerRefs,
 		}
+	case provider == flaggerv1.LinkerdProvider+":httproute":
+		return &GatewayAPIV1Beta1Router{
+			logger:           factory.logger,
+			kubeClient:       factory.kubeClient,
+			gatewayAPIClient: factory.meshClient,
+			setOwnerRefs:     factory.setOwnerRefs,
+			serviceParentRef: true,
+		}
 	case provider == flaggerv1.LinkerdProvider:
 		return &SmiRouter{
 			logger:        factory.logger,
@@ -165,12 +222,36 @@ func (factory *Factory) MeshRouter(provider string, labelSelector string) Interf
 			annotationsPrefix: factory.ingressAnnotationsPrefix,
 			setOwnerRefs:      factory.setOwnerRefs,
 		}
+	case provider == flaggerv1.ALBProvider:
+		return &ALBRouter{
+			logger:     factory.logger,
+			kubeClient: factory.kubeClient,
+		}
+	case provider == flaggerv1.HAProxyProvider:
+		return &HAProxyRouter{
+			logger:        factory.logger,
+			kubeClient:    factory.kubeClient,
+			labelSelector: labelSelector,
+		}
+	case provider == flaggerv1.KongProvider:
+		return &GatewayAPIV1Beta1Router{
+			logger:           factory.logger,
+			kubeClient:       factory.kubeClient,
+			gatewayAPIClient: factory.meshClient,
+			setOwnerRefs:     factory.setOwnerRefs,
+		}
 	case provider == flaggerv1.SkipperProvider:
 		return &SkipperRouter{
 			logger:       factory.logger,
 			kubeClient:   factory.kubeClient,
 			setOwnerRefs: factory.setOwnerRefs,
 		}
+	case provider == flaggerv1.TraefikProvider+":v3":
+		return &TraefikV3Router{
+			logger:        factory.logger,
+			traefikClient: factory.meshClient,
+			setOwnerRefs:  factory.setOwnerRefs,
+		}
 	case provider == flaggerv1.TraefikProvider:
 		return &TraefikRouter{
 			logger:        factory.logger,
@@ -192,6 +273,13 @@ func (factory *Factory) MeshRouter(provider string, labelSelector string) Interf
 			targetMesh:    flaggerv1.OsmProvider,
 			setOwnerRefs:  factory.setOwnerRefs,
 		}
+	case provider == flaggerv1.KumaProvider+":meshhttproute":
+		return &KumaMeshHTTPRouteRouter{
+			logger:       factory.logger,
+			kubeClient:   factory.kubeClient,
+			kumaClient:   factory.meshClient,
+			setOwnerRefs: factory.setOwnerRefs,
+		}
 	case provider == flaggerv1.KumaProvider:
 		return &KumaRouter{
 			logger:        factory.logger,
@@ -213,6 +301,25 @@ func (factory *Factory) MeshRouter(provider string, labelSelector string) Interf
 			gatewayAPIClient: factory.meshClient,
 			setOwnerRefs:     factory.setOwnerRefs,
 		}
+	case strings.HasPrefix(provider, flaggerv1.GatewayAPIProvider+":v1"):
+		return &GatewayAPIV1Router{
+			logger:           factory.logger,
+			kubeClient:       factory.kubeClient,
+			gatewayAPIClient: factory.meshClient,
+			setOwnerRefs:     factory.setOwnerRefs,
+		}
+	case provider == flaggerv1.ConsulProvider:
+		return &ConsulRouter{
+			logger:       factory.logger,
+			kubeClient:   factory.kubeClient,
+			consulClient: factory.meshClient,
+			setOwnerRefs: factory.setOwnerRefs,
+		}
+	case provider == flaggerv1.KnativeProvider:
+		return &KnativeRouter{
+			logger:        factory.logger,
+			knativeClient: factory.flaggerClient,
+		}
 	case provider == flaggerv1.KubernetesProvider:
 		return &NopRouter{}
 	default:
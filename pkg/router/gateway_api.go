@@ -295,6 +295,10 @@ func (gwr *GatewayAPIRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
 
+func (gwr *GatewayAPIRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
 func (gwr *GatewayAPIRouter) mapRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]v1alpha2.HTTPRouteMatch, error) {
 	matches := []v1alpha2.HTTPRouteMatch{}
 
@@ -386,25 +390,16 @@ func (gwr *GatewayAPIRouter) makeBackendRef(svcName string, weight, port int32)
 }
 
 func (gwr *GatewayAPIRouter) mergeMatchConditions(analysis, service []v1alpha2.HTTPRouteMatch) []v1alpha2.HTTPRouteMatch {
-	if len(analysis) == 0 {
-		return service
-	}
-
-	merged := make([]v1alpha2.HTTPRouteMatch, len(service)*len(analysis))
-	num := 0
-	for _, a := range analysis {
-		for _, s := range service {
-			merged[num] = *s.DeepCopy()
-			if len(a.Headers) > 0 {
-				merged[num].Headers = a.Headers
-			}
-			if len(a.QueryParams) > 0 {
-				merged[num].QueryParams = a.QueryParams
-			}
-			num++
+	return mergeRouteMatches(analysis, service, func(a, s v1alpha2.HTTPRouteMatch) v1alpha2.HTTPRouteMatch {
+		merged := *s.DeepCopy()
+		if len(a.Headers) > 0 {
+			merged.Headers = a.Headers
 		}
-	}
-	return merged
+		if len(a.QueryParams) > 0 {
+			merged.QueryParams = a.QueryParams
+		}
+		return merged
+	})
 }
 
 func toV1alpha2ParentRefs(gatewayRefs []v1beta1.ParentReference) []v1alpha2.ParentReference {
@@ -0,0 +1,859 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1"
+	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1alpha2"
+	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+const (
+	httpRouteKind = "HTTPRoute"
+	grpcRouteKind = "GRPCRoute"
+	tcpRouteKind  = "TCPRoute"
+	tlsRouteKind  = "TLSRoute"
+)
+
+var (
+	v1PathMatchType    = gatewayapiv1.PathMatchPathPrefix
+	v1PathMatchRegex   = gatewayapiv1.PathMatchRegularExpression
+	v1PathMatchExact   = gatewayapiv1.PathMatchExact
+	v1PathMatchPrefix  = gatewayapiv1.PathMatchPathPrefix
+	v1HeaderMatchExact = gatewayapiv1.HeaderMatchExact
+	v1HeaderMatchRegex = gatewayapiv1.HeaderMatchRegularExpression
+	v1QueryMatchExact  = gatewayapiv1.QueryParamMatchExact
+	v1QueryMatchRegex  = gatewayapiv1.QueryParamMatchRegularExpression
+	v1GRPCMethodExact  = gatewayapiv1.GRPCMethodMatchExact
+)
+
+// GatewayAPIV1Router is managing the Gateway API v1 HTTPRoutes and GRPCRoutes
+// and the v1alpha2 TCPRoutes and TLSRoutes, the route kind is set with CanaryService.RouteKind
+type GatewayAPIV1Router struct {
+	gatewayAPIClient clientset.Interface
+	kubeClient       kubernetes.Interface
+	logger           *zap.SugaredLogger
+	setOwnerRefs     bool
+}
+
+func (gwr *GatewayAPIV1Router) Reconcile(canary *flaggerv1.Canary) error {
+	if len(canary.Spec.Service.GatewayRefs) == 0 {
+		return fmt.Errorf("GatewayRefs must be specified when using Gateway API as a provider.")
+	}
+
+	switch kind := routeKind(canary); kind {
+	case httpRouteKind:
+		return gwr.reconcileHTTPRoute(canary)
+	case grpcRouteKind:
+		return gwr.reconcileGRPCRoute(canary)
+	case tcpRouteKind, tlsRouteKind:
+		if len(canary.GetAnalysis().Match) > 0 {
+			return fmt.Errorf("%s doesn't support A/B testing, remove the analysis match conditions", kind)
+		}
+		if kind == tcpRouteKind {
+			return gwr.reconcileTCPRoute(canary)
+		}
+		return gwr.reconcileTLSRoute(canary)
+	default:
+		return fmt.Errorf("route kind %s is not supported by the Gateway API provider", kind)
+	}
+}
+
+func (gwr *GatewayAPIV1Router) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	apexSvcName, primarySvcName, canarySvcName := canary.GetServiceNames()
+	namespace := canary.Namespace
+	kind := routeKind(canary)
+
+	// backendRefs collects the backends of the rules that split the traffic,
+	// the A/B testing rule that routes only to the primary is skipped
+	var backendRefs []gatewayapiv1.BackendRef
+	switch kind {
+	case httpRouteKind:
+		route, e := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if e != nil {
+			err = fmt.Errorf("HTTPRoute %s.%s get error: %w", apexSvcName, namespace, e)
+			return
+		}
+		for _, rule := range route.Spec.Rules {
+			if len(rule.BackendRefs) == 2 {
+				for _, ref := range rule.BackendRefs {
+					backendRefs = append(backendRefs, ref.BackendRef)
+				}
+			}
+		}
+	case grpcRouteKind:
+		route, e := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if e != nil {
+			err = fmt.Errorf("GRPCRoute %s.%s get error: %w", apexSvcName, namespace, e)
+			return
+		}
+		for _, rule := range route.Spec.Rules {
+			if len(rule.BackendRefs) == 2 {
+				for _, ref := range rule.BackendRefs {
+					backendRefs = append(backendRefs, ref.BackendRef)
+				}
+			}
+		}
+	case tcpRouteKind:
+		route, e := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if e != nil {
+			err = fmt.Errorf("TCPRoute %s.%s get error: %w", apexSvcName, namespace, e)
+			return
+		}
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, fromV1alpha2BackendRefs(rule.BackendRefs)...)
+		}
+	case tlsRouteKind:
+		route, e := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if e != nil {
+			err = fmt.Errorf("TLSRoute %s.%s get error: %w", apexSvcName, namespace, e)
+			return
+		}
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, fromV1alpha2BackendRefs(rule.BackendRefs)...)
+		}
+	default:
+		err = fmt.Errorf("route kind %s is not supported by the Gateway API provider", kind)
+		return
+	}
+
+	for _, ref := range backendRefs {
+		if ref.Weight == nil {
+			continue
+		}
+		if ref.Name == gatewayapiv1.ObjectName(primarySvcName) {
+			primaryWeight = int(*ref.Weight)
+		}
+		if ref.Name == gatewayapiv1.ObjectName(canarySvcName) {
+			canaryWeight = int(*ref.Weight)
+		}
+	}
+	return
+}
+
+func (gwr *GatewayAPIV1Router) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+) error {
+	pWeight := int32(primaryWeight)
+	cWeight := int32(canaryWeight)
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+
+	switch kind := routeKind(canary); kind {
+	case httpRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("HTTPRoute %s.%s get error: %w", apexSvcName, namespace, err)
+		}
+		routeClone := route.DeepCopy()
+		routeClone.Spec, err = gwr.makeHTTPRouteSpec(canary, pWeight, cWeight)
+		if err != nil {
+			return err
+		}
+		_, err = gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("HTTPRoute %s.%s update error: %w while setting weights", apexSvcName, namespace, err)
+		}
+		return nil
+	case grpcRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("GRPCRoute %s.%s get error: %w", apexSvcName, namespace, err)
+		}
+		routeClone := route.DeepCopy()
+		routeClone.Spec, err = gwr.makeGRPCRouteSpec(canary, pWeight, cWeight)
+		if err != nil {
+			return err
+		}
+		_, err = gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("GRPCRoute %s.%s update error: %w while setting weights", apexSvcName, namespace, err)
+		}
+		return nil
+	case tcpRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("TCPRoute %s.%s get error: %w", apexSvcName, namespace, err)
+		}
+		routeClone := route.DeepCopy()
+		routeClone.Spec = gwr.makeTCPRouteSpec(canary, pWeight, cWeight)
+		_, err = gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("TCPRoute %s.%s update error: %w while setting weights", apexSvcName, namespace, err)
+		}
+		return nil
+	case tlsRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("TLSRoute %s.%s get error: %w", apexSvcName, namespace, err)
+		}
+		routeClone := route.DeepCopy()
+		routeClone.Spec = gwr.makeTLSRouteSpec(canary, pWeight, cWeight)
+		_, err = gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("TLSRoute %s.%s update error: %w while setting weights", apexSvcName, namespace, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("route kind %s is not supported by the Gateway API provider", kind)
+	}
+}
+
+func (gwr *GatewayAPIV1Router) Finalize(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// IsRouteReady checks if the current generation of the route was accepted by all its parents
+func (gwr *GatewayAPIV1Router) IsRouteReady(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+	name := fmt.Sprintf("%s.%s", apexSvcName, namespace)
+
+	switch kind := routeKind(canary); kind {
+	case httpRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("HTTPRoute %s get error: %w", name, err)
+		}
+		return checkGatewayRouteStatus(kind, name, newV1RouteStatus(route.ObjectMeta, route.Spec.ParentRefs, route.Status.Parents))
+	case grpcRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("GRPCRoute %s get error: %w", name, err)
+		}
+		return checkGatewayRouteStatus(kind, name, newV1RouteStatus(route.ObjectMeta, route.Spec.ParentRefs, route.Status.Parents))
+	case tcpRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("TCPRoute %s get error: %w", name, err)
+		}
+		return checkGatewayRouteStatus(kind, name, newV1alpha2RouteStatus(route.ObjectMeta, route.Spec.ParentRefs, route.Status.Parents))
+	case tlsRouteKind:
+		route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("TLSRoute %s get error: %w", name, err)
+		}
+		return checkGatewayRouteStatus(kind, name, newV1alpha2RouteStatus(route.ObjectMeta, route.Spec.ParentRefs, route.Status.Parents))
+	default:
+		return fmt.Errorf("route kind %s is not supported by the Gateway API provider", kind)
+	}
+}
+
+func (gwr *GatewayAPIV1Router) reconcileHTTPRoute(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+
+	routeSpec, err := gwr.makeHTTPRouteSpec(canary, initialPrimaryWeight, initialCanaryWeight)
+	if err != nil {
+		return err
+	}
+
+	route, err := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		route = &gatewayapiv1.HTTPRoute{
+			ObjectMeta: gwr.makeObjectMeta(canary),
+			Spec:       routeSpec,
+		}
+		_, err := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("HTTPRoute %s.%s create error: %w", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("HTTPRoute %s.%s created", apexSvcName, namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("HTTPRoute %s.%s get error: %w", apexSvcName, namespace, err)
+	}
+
+	diff := cmp.Diff(route.Spec, routeSpec, cmpopts.IgnoreFields(gatewayapiv1.BackendRef{}, "Weight"))
+	if diff != "" && route.Name != "" {
+		routeClone := route.DeepCopy()
+		routeClone.Spec = routeSpec
+		_, err := gwr.gatewayAPIClient.GatewayapiV1().HTTPRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("HTTPRoute %s.%s update error: %w while reconciling", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("HTTPRoute %s.%s updated", apexSvcName, namespace)
+	}
+
+	return nil
+}
+
+func (gwr *GatewayAPIV1Router) reconcileGRPCRoute(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+
+	routeSpec, err := gwr.makeGRPCRouteSpec(canary, initialPrimaryWeight, initialCanaryWeight)
+	if err != nil {
+		return err
+	}
+
+	route, err := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		route = &gatewayapiv1.GRPCRoute{
+			ObjectMeta: gwr.makeObjectMeta(canary),
+			Spec:       routeSpec,
+		}
+		_, err := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("GRPCRoute %s.%s create error: %w", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("GRPCRoute %s.%s created", apexSvcName, namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("GRPCRoute %s.%s get error: %w", apexSvcName, namespace, err)
+	}
+
+	diff := cmp.Diff(route.Spec, routeSpec, cmpopts.IgnoreFields(gatewayapiv1.BackendRef{}, "Weight"))
+	if diff != "" && route.Name != "" {
+		routeClone := route.DeepCopy()
+		routeClone.Spec = routeSpec
+		_, err := gwr.gatewayAPIClient.GatewayapiV1().GRPCRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("GRPCRoute %s.%s update error: %w while reconciling", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("GRPCRoute %s.%s updated", apexSvcName, namespace)
+	}
+
+	return nil
+}
+
+func (gwr *GatewayAPIV1Router) reconcileTCPRoute(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+	routeSpec := gwr.makeTCPRouteSpec(canary, initialPrimaryWeight, initialCanaryWeight)
+
+	route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		route = &v1alpha2.TCPRoute{
+			ObjectMeta: gwr.makeObjectMeta(canary),
+			Spec:       routeSpec,
+		}
+		_, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("TCPRoute %s.%s create error: %w", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TCPRoute %s.%s created", apexSvcName, namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("TCPRoute %s.%s get error: %w", apexSvcName, namespace, err)
+	}
+
+	diff := cmp.Diff(route.Spec, routeSpec, cmpopts.IgnoreFields(v1alpha2.BackendRef{}, "Weight"))
+	if diff != "" && route.Name != "" {
+		routeClone := route.DeepCopy()
+		routeClone.Spec = routeSpec
+		_, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("TCPRoute %s.%s update error: %w while reconciling", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TCPRoute %s.%s updated", apexSvcName, namespace)
+	}
+
+	return nil
+}
+
+func (gwr *GatewayAPIV1Router) reconcileTLSRoute(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	namespace := canary.Namespace
+	routeSpec := gwr.makeTLSRouteSpec(canary, initialPrimaryWeight, initialCanaryWeight)
+
+	route, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		route = &v1alpha2.TLSRoute{
+			ObjectMeta: gwr.makeObjectMeta(canary),
+			Spec:       routeSpec,
+		}
+		_, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("TLSRoute %s.%s create error: %w", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TLSRoute %s.%s created", apexSvcName, namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("TLSRoute %s.%s get error: %w", apexSvcName, namespace, err)
+	}
+
+	diff := cmp.Diff(route.Spec, routeSpec, cmpopts.IgnoreFields(v1alpha2.BackendRef{}, "Weight"))
+	if diff != "" && route.Name != "" {
+		routeClone := route.DeepCopy()
+		routeClone.Spec = routeSpec
+		_, err := gwr.gatewayAPIClient.GatewayapiV1alpha2().TLSRoutes(namespace).Update(context.TODO(), routeClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("TLSRoute %s.%s update error: %w while reconciling", apexSvcName, namespace, err)
+		}
+		gwr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TLSRoute %s.%s updated", apexSvcName, namespace)
+	}
+
+	return nil
+}
+
+func (gwr *GatewayAPIV1Router) makeHTTPRouteSpec(canary *flaggerv1.Canary, primaryWeight, canaryWeight int32) (gatewayapiv1.HTTPRouteSpec, error) {
+	_, primarySvcName, canarySvcName := canary.GetServiceNames()
+	port := canary.Spec.Service.Port
+
+	matches, err := gwr.mapRouteMatches(canary.Spec.Service.Match)
+	if err != nil {
+		return gatewayapiv1.HTTPRouteSpec{}, fmt.Errorf("Invalid request matching selectors: %w", err)
+	}
+	if len(matches) == 0 {
+		matches = append(matches, gatewayapiv1.HTTPRouteMatch{
+			Path: &gatewayapiv1.HTTPPathMatch{
+				Type:  &v1PathMatchType,
+				Value: &pathMatchValue,
+			},
+		})
+	}
+
+	spec := gatewayapiv1.HTTPRouteSpec{
+		CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+			ParentRefs: toV1ParentRefs(canary.Spec.Service.GatewayRefs),
+		},
+		Hostnames: toV1Hostnames(canary.Spec.Service.Hosts),
+		Rules: []gatewayapiv1.HTTPRouteRule{
+			{
+				Matches: matches,
+				BackendRefs: []gatewayapiv1.HTTPBackendRef{
+					{BackendRef: gwr.makeBackendRef(primarySvcName, primaryWeight, port)},
+					{BackendRef: gwr.makeBackendRef(canarySvcName, canaryWeight, port)},
+				},
+			},
+		},
+	}
+
+	// A/B testing
+	if len(canary.GetAnalysis().Match) > 0 {
+		analysisMatches, err := gwr.mapRouteMatches(canary.GetAnalysis().Match)
+		if err != nil {
+			return gatewayapiv1.HTTPRouteSpec{}, fmt.Errorf("Invalid request matching selectors: %w", err)
+		}
+		spec.Rules[0].Matches = gwr.mergeMatchConditions(analysisMatches, matches)
+		spec.Rules = append(spec.Rules, gatewayapiv1.HTTPRouteRule{
+			Matches: matches,
+			BackendRefs: []gatewayapiv1.HTTPBackendRef{
+				{BackendRef: gwr.makeBackendRef(primarySvcName, initialPrimaryWeight, port)},
+			},
+		})
+	}
+
+	return spec, nil
+}
+
+func (gwr *GatewayAPIV1Router) makeGRPCRouteSpec(canary *flaggerv1.Canary, primaryWeight, canaryWeight int32) (gatewayapiv1.GRPCRouteSpec, error) {
+	_, primarySvcName, canarySvcName := canary.GetServiceNames()
+	port := canary.Spec.Service.Port
+
+	matches, err := gwr.mapGRPCRouteMatches(canary.Spec.Service.Match)
+	if err != nil {
+		return gatewayapiv1.GRPCRouteSpec{}, fmt.Errorf("Invalid request matching selectors: %w", err)
+	}
+
+	spec := gatewayapiv1.GRPCRouteSpec{
+		CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+			ParentRefs: toV1ParentRefs(canary.Spec.Service.GatewayRefs),
+		},
+		Hostnames: toV1Hostnames(canary.Spec.Service.Hosts),
+		Rules: []gatewayapiv1.GRPCRouteRule{
+			{
+				Matches: matches,
+				BackendRefs: []gatewayapiv1.GRPCBackendRef{
+					{BackendRef: gwr.makeBackendRef(primarySvcName, primaryWeight, port)},
+					{BackendRef: gwr.makeBackendRef(canarySvcName, canaryWeight, port)},
+				},
+			},
+		},
+	}
+
+	// A/B testing on gRPC metadata
+	if len(canary.GetAnalysis().Match) > 0 {
+		analysisMatches, err := gwr.mapGRPCRouteMatches(canary.GetAnalysis().Match)
+		if err != nil {
+			return gatewayapiv1.GRPCRouteSpec{}, fmt.Errorf("Invalid request matching selectors: %w", err)
+		}
+		spec.Rules[0].Matches = gwr.mergeGRPCMatchConditions(analysisMatches, matches)
+		spec.Rules = append(spec.Rules, gatewayapiv1.GRPCRouteRule{
+			Matches: matches,
+			BackendRefs: []gatewayapiv1.GRPCBackendRef{
+				{BackendRef: gwr.makeBackendRef(primarySvcName, initialPrimaryWeight, port)},
+			},
+		})
+	}
+
+	return spec, nil
+}
+
+func (gwr *GatewayAPIV1Router) makeTCPRouteSpec(canary *flaggerv1.Canary, primaryWeight, canaryWeight int32) v1alpha2.TCPRouteSpec {
+	_, primarySvcName, canarySvcName := canary.GetServiceNames()
+	return v1alpha2.TCPRouteSpec{
+		CommonRouteSpec: v1alpha2.CommonRouteSpec{
+			ParentRefs: toV1alpha2ParentRefs(canary.Spec.Service.GatewayRefs),
+		},
+		Rules: []v1alpha2.TCPRouteRule{
+			{
+				BackendRefs: []v1alpha2.BackendRef{
+					toV1alpha2BackendRef(gwr.makeBackendRef(primarySvcName, primaryWeight, canary.Spec.Service.Port)),
+					toV1alpha2BackendRef(gwr.makeBackendRef(canarySvcName, canaryWeight, canary.Spec.Service.Port)),
+				},
+			},
+		},
+	}
+}
+
+func (gwr *GatewayAPIV1Router) makeTLSRouteSpec(canary *flaggerv1.Canary, primaryWeight, canaryWeight int32) v1alpha2.TLSRouteSpec {
+	_, primarySvcName, canarySvcName := canary.GetServiceNames()
+	var hostNames []v1alpha2.Hostname
+	for _, host := range canary.Spec.Service.Hosts {
+		hostNames = append(hostNames, v1alpha2.Hostname(host))
+	}
+	return v1alpha2.TLSRouteSpec{
+		CommonRouteSpec: v1alpha2.CommonRouteSpec{
+			ParentRefs: toV1alpha2ParentRefs(canary.Spec.Service.GatewayRefs),
+		},
+		Hostnames: hostNames,
+		Rules: []v1alpha2.TLSRouteRule{
+			{
+				BackendRefs: []v1alpha2.BackendRef{
+					toV1alpha2BackendRef(gwr.makeBackendRef(primarySvcName, primaryWeight, canary.Spec.Service.Port)),
+					toV1alpha2BackendRef(gwr.makeBackendRef(canarySvcName, canaryWeight, canary.Spec.Service.Port)),
+				},
+			},
+		},
+	}
+}
+
+func (gwr *GatewayAPIV1Router) makeObjectMeta(canary *flaggerv1.Canary) metav1.ObjectMeta {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	metadata := canary.Spec.Service.Apex
+	if metadata == nil {
+		metadata = &flaggerv1.CustomMetadata{}
+	}
+	if metadata.Labels == nil {
+		metadata.Labels = make(map[string]string)
+	}
+	if metadata.Annotations == nil {
+		metadata.Annotations = make(map[string]string)
+	}
+
+	objectMeta := metav1.ObjectMeta{
+		Name:        apexSvcName,
+		Namespace:   canary.Namespace,
+		Labels:      metadata.Labels,
+		Annotations: filterMetadata(metadata.Annotations),
+	}
+	if gwr.setOwnerRefs {
+		objectMeta.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(canary, schema.GroupVersionKind{
+				Group:   flaggerv1.SchemeGroupVersion.Group,
+				Version: flaggerv1.SchemeGroupVersion.Version,
+				Kind:    flaggerv1.CanaryKind,
+			}),
+		}
+	}
+	return objectMeta
+}
+
+func (gwr *GatewayAPIV1Router) mapRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]gatewayapiv1.HTTPRouteMatch, error) {
+	matches := []gatewayapiv1.HTTPRouteMatch{}
+
+	for _, requestMatch := range requestMatches {
+		match := gatewayapiv1.HTTPRouteMatch{}
+		if requestMatch.Uri != nil {
+			if requestMatch.Uri.Regex != "" {
+				match.Path = &gatewayapiv1.HTTPPathMatch{
+					Type:  &v1PathMatchRegex,
+					Value: &requestMatch.Uri.Regex,
+				}
+			} else if requestMatch.Uri.Exact != "" {
+				match.Path = &gatewayapiv1.HTTPPathMatch{
+					Type:  &v1PathMatchExact,
+					Value: &requestMatch.Uri.Exact,
+				}
+			} else if requestMatch.Uri.Prefix != "" {
+				match.Path = &gatewayapiv1.HTTPPathMatch{
+					Type:  &v1PathMatchPrefix,
+					Value: &requestMatch.Uri.Prefix,
+				}
+			} else {
+				return nil, fmt.Errorf("Gateway API doesn't support the specified path matching selector: %+v\n", requestMatch.Uri)
+			}
+		}
+		if requestMatch.Method != nil {
+			if requestMatch.Method.Exact != "" {
+				method := gatewayapiv1.HTTPMethod(requestMatch.Method.Exact)
+				match.Method = &method
+			} else {
+				return nil, fmt.Errorf("Gateway API doesn't support the specified method matching selector: %+v\n", requestMatch.Method)
+			}
+		}
+		for key, val := range requestMatch.Headers {
+			headerMatch := gatewayapiv1.HTTPHeaderMatch{Name: gatewayapiv1.HTTPHeaderName(key)}
+			if val.Exact != "" {
+				headerMatch.Type = &v1HeaderMatchExact
+				headerMatch.Value = val.Exact
+			} else if val.Regex != "" {
+				headerMatch.Type = &v1HeaderMatchRegex
+				headerMatch.Value = val.Regex
+			} else {
+				return nil, fmt.Errorf("Gateway API doesn't support the specified header matching selector: %+v\n", requestMatch.Headers)
+			}
+			match.Headers = append(match.Headers, headerMatch)
+		}
+		for key, val := range requestMatch.QueryParams {
+			queryMatch := gatewayapiv1.HTTPQueryParamMatch{Name: key}
+			if val.Exact != "" {
+				queryMatch.Type = &v1QueryMatchExact
+				queryMatch.Value = val.Exact
+			} else if val.Regex != "" {
+				queryMatch.Type = &v1QueryMatchRegex
+				queryMatch.Value = val.Regex
+			} else {
+				return nil, fmt.Errorf("Gateway API doesn't support the specified query matching selector: %+v\n", requestMatch.QueryParams)
+			}
+			match.QueryParams = append(match.QueryParams, queryMatch)
+		}
+
+		if !reflect.DeepEqual(match, gatewayapiv1.HTTPRouteMatch{}) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+// mapGRPCRouteMatches converts the request matches to GRPCRoute matches,
+// the URI is matched against the gRPC `/<service>/<method>` path
+// and the headers are matched against the gRPC metadata
+func (gwr *GatewayAPIV1Router) mapGRPCRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]gatewayapiv1.GRPCRouteMatch, error) {
+	matches := []gatewayapiv1.GRPCRouteMatch{}
+
+	for _, requestMatch := range requestMatches {
+		match := gatewayapiv1.GRPCRouteMatch{}
+		if requestMatch.Uri != nil {
+			var service, method string
+			if requestMatch.Uri.Exact != "" {
+				parts := strings.Split(strings.Trim(requestMatch.Uri.Exact, "/"), "/")
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("gRPC path must be in the /<service>/<method> format: %s", requestMatch.Uri.Exact)
+				}
+				service, method = parts[0], parts[1]
+			} else if requestMatch.Uri.Prefix != "" {
+				service = strings.Trim(requestMatch.Uri.Prefix, "/")
+			} else {
+				return nil, fmt.Errorf("GRPCRoute doesn't support the specified path matching selector: %+v\n", requestMatch.Uri)
+			}
+			match.Method = &gatewayapiv1.GRPCMethodMatch{
+				Type:    &v1GRPCMethodExact,
+				Service: &service,
+			}
+			if method != "" {
+				match.Method.Method = &method
+			}
+		}
+		if requestMatch.Method != nil || len(requestMatch.QueryParams) > 0 {
+			return nil, fmt.Errorf("GRPCRoute doesn't support HTTP method and query matching selectors")
+		}
+		for key, val := range requestMatch.Headers {
+			headerMatch := gatewayapiv1.GRPCHeaderMatch{Name: gatewayapiv1.GRPCHeaderName(key)}
+			if val.Exact != "" {
+				headerMatch.Type = &v1HeaderMatchExact
+				headerMatch.Value = val.Exact
+			} else if val.Regex != "" {
+				headerMatch.Type = &v1HeaderMatchRegex
+				headerMatch.Value = val.Regex
+			} else {
+				return nil, fmt.Errorf("GRPCRoute doesn't support the specified header matching selector: %+v\n", requestMatch.Headers)
+			}
+			match.Headers = append(match.Headers, headerMatch)
+		}
+
+		if !reflect.DeepEqual(match, gatewayapiv1.GRPCRouteMatch{}) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+func (gwr *GatewayAPIV1Router) makeBackendRef(svcName string, weight, port int32) gatewayapiv1.BackendRef {
+	return gatewayapiv1.BackendRef{
+		BackendObjectReference: gatewayapiv1.BackendObjectReference{
+			Group: (*gatewayapiv1.Group)(&backendRefGroup),
+			Kind:  (*gatewayapiv1.Kind)(&backendRefKind),
+			Name:  gatewayapiv1.ObjectName(svcName),
+			Port:  (*gatewayapiv1.PortNumber)(&port),
+		},
+		Weight: &weight,
+	}
+}
+
+func (gwr *GatewayAPIV1Router) mergeMatchConditions(analysis, service []gatewayapiv1.HTTPRouteMatch) []gatewayapiv1.HTTPRouteMatch {
+	return mergeRouteMatches(analysis, service, func(a, s gatewayapiv1.HTTPRouteMatch) gatewayapiv1.HTTPRouteMatch {
+		merged := *s.DeepCopy()
+		if len(a.Headers) > 0 {
+			merged.Headers = a.Headers
+		}
+		if len(a.QueryParams) > 0 {
+			merged.QueryParams = a.QueryParams
+		}
+		return merged
+	})
+}
+
+func (gwr *GatewayAPIV1Router) mergeGRPCMatchConditions(analysis, service []gatewayapiv1.GRPCRouteMatch) []gatewayapiv1.GRPCRouteMatch {
+	return mergeRouteMatches(analysis, service, func(a, s gatewayapiv1.GRPCRouteMatch) gatewayapiv1.GRPCRouteMatch {
+		merged := *s.DeepCopy()
+		if len(a.Headers) > 0 {
+			merged.Headers = a.Headers
+		}
+		return merged
+	})
+}
+
+// mergeRouteMatches returns the product of the analysis and service matches,
+// merge copies the analysis conditions onto the service match.
+// The analysis matches are returned as they are if the service has no matches.
+func mergeRouteMatches[M any](analysis, service []M, merge func(analysis, service M) M) []M {
+	if len(analysis) == 0 {
+		return service
+	}
+	if len(service) == 0 {
+		return analysis
+	}
+
+	merged := make([]M, 0, len(service)*len(analysis))
+	for _, a := range analysis {
+		for _, s := range service {
+			merged = append(merged, merge(a, s))
+		}
+	}
+	return merged
+}
+
+// routeKind returns the Gateway API route kind of the canary, defaults to HTTPRoute
+func routeKind(canary *flaggerv1.Canary) string {
+	if canary.Spec.Service.RouteKind == "" {
+		return httpRouteKind
+	}
+	return canary.Spec.Service.RouteKind
+}
+
+func toV1ParentRefs(gatewayRefs []v1beta1.ParentReference) []gatewayapiv1.ParentReference {
+	parentRefs := make([]gatewayapiv1.ParentReference, 0, len(gatewayRefs))
+	for _, gatewayRef := range gatewayRefs {
+		parentRefs = append(parentRefs, gatewayapiv1.ParentReference{
+			Group:       (*gatewayapiv1.Group)(gatewayRef.Group),
+			Kind:        (*gatewayapiv1.Kind)(gatewayRef.Kind),
+			Namespace:   (*gatewayapiv1.Namespace)(gatewayRef.Namespace),
+			Name:        gatewayapiv1.ObjectName(gatewayRef.Name),
+			SectionName: (*gatewayapiv1.SectionName)(gatewayRef.SectionName),
+			Port:        (*gatewayapiv1.PortNumber)(gatewayRef.Port),
+		})
+	}
+	return parentRefs
+}
+
+func toV1Hostnames(hosts []string) []gatewayapiv1.Hostname {
+	var hostNames []gatewayapiv1.Hostname
+	for _, host := range hosts {
+		hostNames = append(hostNames, gatewayapiv1.Hostname(host))
+	}
+	return hostNames
+}
+
+func toV1alpha2BackendRef(ref gatewayapiv1.BackendRef) v1alpha2.BackendRef {
+	return v1alpha2.BackendRef{
+		BackendObjectReference: v1alpha2.BackendObjectReference{
+			Group: (*v1alpha2.Group)(ref.Group),
+			Kind:  (*v1alpha2.Kind)(ref.Kind),
+			Name:  v1alpha2.ObjectName(ref.Name),
+			Port:  (*v1alpha2.PortNumber)(ref.Port),
+		},
+		Weight: ref.Weight,
+	}
+}
+
+func fromV1alpha2BackendRefs(refs []v1alpha2.BackendRef) []gatewayapiv1.BackendRef {
+	backendRefs := make([]gatewayapiv1.BackendRef, 0, len(refs))
+	for _, ref := range refs {
+		backendRefs = append(backendRefs, gatewayapiv1.BackendRef{
+			BackendObjectReference: gatewayapiv1.BackendObjectReference{
+				Name: gatewayapiv1.ObjectName(ref.Name),
+			},
+			Weight: ref.Weight,
+		})
+	}
+	return backendRefs
+}
+
+func newV1RouteStatus(meta metav1.ObjectMeta, refs []gatewayapiv1.ParentReference, parents []gatewayapiv1.RouteParentStatus) gatewayRouteStatus {
+	status := gatewayRouteStatus{generation: meta.Generation}
+	for _, ref := range refs {
+		status.parentRefs = append(status.parentRefs, makeRouteParent(meta.Namespace, (*string)(ref.Namespace), string(ref.Name)))
+	}
+	for _, parent := range parents {
+		status.parents = append(status.parents, routeParentConditions{
+			routeParent: makeRouteParent(meta.Namespace, (*string)(parent.ParentRef.Namespace), string(parent.ParentRef.Name)),
+			conditions:  parent.Conditions,
+		})
+	}
+	return status
+}
+
+func newV1alpha2RouteStatus(meta metav1.ObjectMeta, refs []v1alpha2.ParentReference, parents []v1alpha2.RouteParentStatus) gatewayRouteStatus {
+	status := gatewayRouteStatus{generation: meta.Generation}
+	for _, ref := range refs {
+		status.parentRefs = append(status.parentRefs, makeRouteParent(meta.Namespace, (*string)(ref.Namespace), string(ref.Name)))
+	}
+	for _, parent := range parents {
+		status.parents = append(status.parents, routeParentConditions{
+			routeParent: makeRouteParent(meta.Namespace, (*string)(parent.ParentRef.Namespace), string(parent.ParentRef.Name)),
+			conditions:  parent.Conditions,
+		})
+	}
+	return status
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayapiv1 "github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1"
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+func TestGatewayAPIV1Router_Routes(t *testing.T) {
+	canary := newTestGatewayAPICanary()
+	mocks := newFixture(canary)
+	router := &GatewayAPIV1Router{
+		gatewayAPIClient: mocks.meshClient,
+		kubeClient:       mocks.kubeClient,
+		logger:           mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	httpRoute, err := router.gatewayAPIClient.GatewayapiV1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, httpRoute.Spec.Rules, 1)
+	require.Len(t, httpRoute.Spec.Rules[0].BackendRefs, 2)
+	assert.Equal(t, int32(100), *httpRoute.Spec.Rules[0].BackendRefs[0].Weight)
+
+	httpRoute.Status.RouteStatus = newTestGatewayAPIV1RouteStatus()
+	_, err = router.gatewayAPIClient.GatewayapiV1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, router.IsRouteReady(canary))
+
+	err = router.SetRoutes(canary, 60, 40, false)
+	require.NoError(t, err)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+}
+
+func TestGatewayAPIV1Router_GRPCRoute(t *testing.T) {
+	canary := newTestGatewayAPICanary()
+	canary.Spec.Service.RouteKind = "GRPCRoute"
+	canary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "insider"},
+			},
+		},
+	}
+	mocks := newFixture(canary)
+	router := &GatewayAPIV1Router{
+		gatewayAPIClient: mocks.meshClient,
+		kubeClient:       mocks.kubeClient,
+		logger:           mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	grpcRoute, err := router.gatewayAPIClient.GatewayapiV1().GRPCRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, grpcRoute.Spec.Rules, 2)
+	require.Len(t, grpcRoute.Spec.Rules[0].Matches, 1)
+	assert.Equal(t, "insider", grpcRoute.Spec.Rules[0].Matches[0].Headers[0].Value)
+	assert.Len(t, grpcRoute.Spec.Rules[1].BackendRefs, 1)
+
+	grpcRoute.Status.RouteStatus = newTestGatewayAPIV1RouteStatus()
+	_, err = router.gatewayAPIClient.GatewayapiV1().GRPCRoutes("default").UpdateStatus(context.TODO(), grpcRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.SetRoutes(canary, 0, 100, false)
+	require.NoError(t, err)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 0, p)
+	assert.Equal(t, 100, c)
+}
+
+func TestGatewayAPIV1Router_TCPRoute(t *testing.T) {
+	canary := newTestGatewayAPICanary()
+	canary.Spec.Service.RouteKind = "TCPRoute"
+	mocks := newFixture(canary)
+	router := &GatewayAPIV1Router{
+		gatewayAPIClient: mocks.meshClient,
+		kubeClient:       mocks.kubeClient,
+		logger:           mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	tcpRoute, err := router.gatewayAPIClient.GatewayapiV1alpha2().TCPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, tcpRoute.Spec.Rules, 1)
+	assert.Equal(t, int32(80), int32(*tcpRoute.Spec.Rules[0].BackendRefs[1].Port))
+
+	// weights are set even if the route is not accepted
+	err = router.SetRoutes(canary, 70, 30, false)
+	require.NoError(t, err)
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 70, p)
+	assert.Equal(t, 30, c)
+
+	// A/B testing is not supported for TCP
+	canary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{{Headers: map[string]istiov1alpha1.StringMatch{"x-canary": {Exact: "insider"}}}}
+	err = router.Reconcile(canary)
+	require.Error(t, err)
+}
+
+func newTestGatewayAPIV1RouteStatus() gatewayapiv1.RouteStatus {
+	return gatewayapiv1.RouteStatus{
+		Parents: []gatewayapiv1.RouteParentStatus{
+			{
+				ParentRef: gatewayapiv1.ParentReference{Name: "podinfo"},
+				Conditions: []metav1.Condition{
+					{Type: string(gatewayapiv1.RouteConditionAccepted), Status: metav1.ConditionTrue, Reason: string(gatewayapiv1.RouteReasonAccepted)},
+				},
+			},
+		},
+	}
+}
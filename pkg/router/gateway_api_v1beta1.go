@@ -29,6 +29,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
@@ -50,10 +51,13 @@ type GatewayAPIV1Beta1Router struct {
 	kubeClient       kubernetes.Interface
 	logger           *zap.SugaredLogger
 	setOwnerRefs     bool
+	// serviceParentRef attaches the HTTPRoute to the apex Service instead of the Gateways,
+	// this is used by service meshes that implement GAMMA like Linkerd
+	serviceParentRef bool
 }
 
 func (gwr *GatewayAPIV1Beta1Router) Reconcile(canary *flaggerv1.Canary) error {
-	if len(canary.Spec.Service.GatewayRefs) == 0 {
+	if !gwr.serviceParentRef && len(canary.Spec.Service.GatewayRefs) == 0 {
 		return fmt.Errorf("GatewayRefs must be specified when using Gateway API as a provider.")
 	}
 
@@ -80,7 +84,7 @@ func (gwr *GatewayAPIV1Beta1Router) Reconcile(canary *flaggerv1.Canary) error {
 
 	httpRouteSpec := v1beta1.HTTPRouteSpec{
 		CommonRouteSpec: v1beta1.CommonRouteSpec{
-			ParentRefs: canary.Spec.Service.GatewayRefs,
+			ParentRefs: gwr.makeParentRefs(canary),
 		},
 		Hostnames: hostNames,
 		Rules: []v1beta1.HTTPRouteRule{
@@ -244,7 +248,7 @@ func (gwr *GatewayAPIV1Beta1Router) SetRoutes(
 	}
 	httpRouteSpec := v1beta1.HTTPRouteSpec{
 		CommonRouteSpec: v1beta1.CommonRouteSpec{
-			ParentRefs: canary.Spec.Service.GatewayRefs,
+			ParentRefs: gwr.makeParentRefs(canary),
 		},
 		Hostnames: hostNames,
 		Rules: []v1beta1.HTTPRouteRule{
@@ -289,6 +293,18 @@ func (gwr *GatewayAPIV1Beta1Router) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
 
+// IsRouteReady checks if the current generation of the HTTPRoute was accepted by all its parents
+func (gwr *GatewayAPIV1Beta1Router) IsRouteReady(canary *flaggerv1.Canary) error {
+	apexSvcName, _, _ := canary.GetServiceNames()
+	hrNamespace := canary.Namespace
+	httpRoute, err := gwr.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes(hrNamespace).Get(context.TODO(), apexSvcName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("HTTPRoute %s.%s get error: %w", apexSvcName, hrNamespace, err)
+	}
+
+	return checkGatewayRouteStatus("HTTPRoute", fmt.Sprintf("%s.%s", apexSvcName, hrNamespace), newV1beta1RouteStatus(httpRoute.ObjectMeta, httpRoute.Spec.ParentRefs, httpRoute.Status.Parents))
+}
+
 func (gwr *GatewayAPIV1Beta1Router) mapRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]v1beta1.HTTPRouteMatch, error) {
 	matches := []v1beta1.HTTPRouteMatch{}
 
@@ -367,6 +383,27 @@ func (gwr *GatewayAPIV1Beta1Router) mapRouteMatches(requestMatches []v1alpha3.HT
 	return matches, nil
 }
 
+// makeParentRefs returns the Gateway references from the canary spec or
+// a reference to the apex Service when the route is managed by a service mesh
+func (gwr *GatewayAPIV1Beta1Router) makeParentRefs(canary *flaggerv1.Canary) []v1beta1.ParentReference {
+	if !gwr.serviceParentRef {
+		return canary.Spec.Service.GatewayRefs
+	}
+
+	apexSvcName, _, _ := canary.GetServiceNames()
+	group := v1beta1.Group("core")
+	kind := v1beta1.Kind("Service")
+	port := v1beta1.PortNumber(canary.Spec.Service.Port)
+	return []v1beta1.ParentReference{
+		{
+			Group: &group,
+			Kind:  &kind,
+			Name:  v1beta1.ObjectName(apexSvcName),
+			Port:  &port,
+		},
+	}
+}
+
 func (gwr *GatewayAPIV1Beta1Router) makeBackendRef(svcName string, weight, port int32) v1beta1.BackendRef {
 	return v1beta1.BackendRef{
 		BackendObjectReference: v1beta1.BackendObjectReference{
@@ -380,23 +417,112 @@ func (gwr *GatewayAPIV1Beta1Router) makeBackendRef(svcName string, weight, port
 }
 
 func (gwr *GatewayAPIV1Beta1Router) mergeMatchConditions(analysis, service []v1beta1.HTTPRouteMatch) []v1beta1.HTTPRouteMatch {
-	if len(analysis) == 0 {
-		return service
+	return mergeRouteMatches(analysis, service, func(a, s v1beta1.HTTPRouteMatch) v1beta1.HTTPRouteMatch {
+		merged := *s.DeepCopy()
+		if len(a.Headers) > 0 {
+			merged.Headers = a.Headers
+		}
+		if len(a.QueryParams) > 0 {
+			merged.QueryParams = a.QueryParams
+		}
+		return merged
+	})
+}
+
+// routeParent identifies a parent of a route, e.g. a Gateway, by namespace and name
+type routeParent struct {
+	namespace string
+	name      string
+}
+
+func (p routeParent) String() string {
+	return fmt.Sprintf("%s/%s", p.namespace, p.name)
+}
+
+// routeParentConditions holds the conditions set by a parent on a route
+type routeParentConditions struct {
+	routeParent
+	conditions []metav1.Condition
+}
+
+// gatewayRouteStatus holds the generation of a route, the parents referenced
+// in its spec and the conditions set by the parents in its status
+type gatewayRouteStatus struct {
+	generation int64
+	parentRefs []routeParent
+	parents    []routeParentConditions
+}
+
+func newV1beta1RouteStatus(meta metav1.ObjectMeta, refs []v1beta1.ParentReference, parents []v1beta1.RouteParentStatus) gatewayRouteStatus {
+	status := gatewayRouteStatus{generation: meta.Generation}
+	for _, ref := range refs {
+		status.parentRefs = append(status.parentRefs, makeRouteParent(meta.Namespace, (*string)(ref.Namespace), string(ref.Name)))
+	}
+	for _, parent := range parents {
+		status.parents = append(status.parents, routeParentConditions{
+			routeParent: makeRouteParent(meta.Namespace, (*string)(parent.ParentRef.Namespace), string(parent.ParentRef.Name)),
+			conditions:  parent.Conditions,
+		})
+	}
+	return status
+}
+
+// makeRouteParent returns the parent of a route, defaulting to the route namespace
+func makeRouteParent(routeNamespace string, namespace *string, name string) routeParent {
+	if namespace != nil && *namespace != "" {
+		routeNamespace = *namespace
+	}
+	return routeParent{namespace: routeNamespace, name: name}
+}
+
+// checkGatewayRouteStatus returns an error wrapping ErrRouteNotReady if a parent referenced
+// by the route didn't accept the current generation of the route
+// or couldn't resolve the route backend references
+func checkGatewayRouteStatus(kind string, name string, status gatewayRouteStatus) error {
+	if len(status.parentRefs) == 0 {
+		return fmt.Errorf("%s %s has no parent references: %w", kind, name, ErrRouteNotReady)
 	}
 
-	merged := make([]v1beta1.HTTPRouteMatch, len(service)*len(analysis))
-	num := 0
-	for _, a := range analysis {
-		for _, s := range service {
-			merged[num] = *s.DeepCopy()
-			if len(a.Headers) > 0 {
-				merged[num].Headers = a.Headers
+	for _, ref := range status.parentRefs {
+		var conditions []metav1.Condition
+		found := false
+		for _, parent := range status.parents {
+			if parent.routeParent == ref {
+				conditions = parent.conditions
+				found = true
+				break
 			}
-			if len(a.QueryParams) > 0 {
-				merged[num].QueryParams = a.QueryParams
+		}
+		if !found {
+			return fmt.Errorf("%s %s has not been accepted by %s: %w", kind, name, ref, ErrRouteNotReady)
+		}
+
+		accepted := meta.FindStatusCondition(conditions, string(v1beta1.RouteConditionAccepted))
+		if accepted == nil || accepted.Status != metav1.ConditionTrue {
+			reason := string(v1beta1.RouteReasonPending)
+			if accepted != nil {
+				reason = fmt.Sprintf("%s %s", accepted.Reason, accepted.Message)
+			}
+			return fmt.Errorf("%s %s has not been accepted by %s: %s: %w",
+				kind, name, ref, reason, ErrRouteNotReady)
+		}
+		if accepted.ObservedGeneration != status.generation {
+			return fmt.Errorf("%s %s generation %d has not been observed by %s: %w",
+				kind, name, status.generation, ref, ErrRouteNotReady)
+		}
+
+		resolved := meta.FindStatusCondition(conditions, string(v1beta1.RouteConditionResolvedRefs))
+		if resolved != nil {
+			if resolved.ObservedGeneration != status.generation {
+				return fmt.Errorf("%s %s generation %d references have not been resolved by %s: %w",
+					kind, name, status.generation, ref, ErrRouteNotReady)
+			}
+			if resolved.Status != metav1.ConditionTrue {
+				return fmt.Errorf("%s %s references can't be resolved by %s: %s %s: %w",
+					kind, name, ref, resolved.Reason, resolved.Message, ErrRouteNotReady)
 			}
-			num++
 		}
 	}
-	return merged
+
+	return nil
 }
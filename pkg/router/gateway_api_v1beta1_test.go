@@ -23,6 +23,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
 )
 
 func TestGatewayAPIV1Beta1Router_Reconcile(t *testing.T) {
@@ -61,6 +65,7 @@ func TestGatewayAPIV1Beta1Router_Routes(t *testing.T) {
 	err := router.Reconcile(canary)
 	require.NoError(t, err)
 
+	// weights are set even if the route is not yet accepted by the gateway
 	err = router.SetRoutes(canary, 50, 50, false)
 	require.NoError(t, err)
 
@@ -70,3 +75,124 @@ func TestGatewayAPIV1Beta1Router_Routes(t *testing.T) {
 	primary := httpRoute.Spec.Rules[0].BackendRefs[0]
 	assert.Equal(t, int32(50), *primary.Weight)
 }
+
+func TestGatewayAPIV1Beta1Router_IsRouteReady(t *testing.T) {
+	canary := newTestGatewayAPICanary()
+	mocks := newFixture(canary)
+	router := &GatewayAPIV1Beta1Router{
+		gatewayAPIClient: mocks.meshClient,
+		kubeClient:       mocks.kubeClient,
+		logger:           mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+
+	httpRoute, err := router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	httpRoute.Generation = 2
+	httpRoute.Status = newTestGatewayAPIV1Beta1RouteStatus(metav1.ConditionFalse, 2)
+	httpRoute, err = router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+	assert.Contains(t, err.Error(), "NotAllowedByListeners")
+
+	// the conditions refer to a previous generation of the route
+	httpRoute.Status = newTestGatewayAPIV1Beta1RouteStatus(metav1.ConditionTrue, 1)
+	httpRoute, err = router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+	assert.Contains(t, err.Error(), "generation 2 has not been observed")
+
+	// the route was accepted by a gateway with the same name in another namespace
+	otherNamespace := v1beta1.Namespace("other")
+	httpRoute.Status = newTestGatewayAPIV1Beta1RouteStatus(metav1.ConditionTrue, 2)
+	httpRoute.Status.Parents[0].ParentRef.Namespace = &otherNamespace
+	httpRoute, err = router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+
+	httpRoute.Status = newTestGatewayAPIV1Beta1RouteStatus(metav1.ConditionTrue, 2)
+	_, err = router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.NoError(t, err)
+}
+
+func TestGatewayAPIV1Beta1Router_ServiceParentRef(t *testing.T) {
+	canary := newTestGatewayAPICanary()
+	canary.Spec.Service.GatewayRefs = nil
+	canary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "insider"},
+			},
+		},
+	}
+	mocks := newFixture(canary)
+	router := &GatewayAPIV1Beta1Router{
+		gatewayAPIClient: mocks.meshClient,
+		kubeClient:       mocks.kubeClient,
+		logger:           mocks.logger,
+		serviceParentRef: true,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	httpRoute, err := router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, httpRoute.Spec.ParentRefs, 1)
+	parentRef := httpRoute.Spec.ParentRefs[0]
+	assert.Equal(t, v1beta1.ObjectName("podinfo"), parentRef.Name)
+	assert.Equal(t, v1beta1.Kind("Service"), *parentRef.Kind)
+	assert.Equal(t, v1beta1.Group("core"), *parentRef.Group)
+	assert.Equal(t, v1beta1.PortNumber(canary.Spec.Service.Port), *parentRef.Port)
+
+	// A/B testing
+	require.Len(t, httpRoute.Spec.Rules, 2)
+	assert.Equal(t, "x-canary", string(httpRoute.Spec.Rules[0].Matches[0].Headers[0].Name))
+	assert.Len(t, httpRoute.Spec.Rules[1].BackendRefs, 1)
+
+	httpRoute.Status = newTestGatewayAPIV1Beta1RouteStatus(metav1.ConditionTrue, 0)
+	_, err = router.gatewayAPIClient.GatewayapiV1beta1().HTTPRoutes("default").UpdateStatus(context.TODO(), httpRoute, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.SetRoutes(canary, 0, 100, false)
+	require.NoError(t, err)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 0, p)
+	assert.Equal(t, 100, c)
+}
+
+func newTestGatewayAPIV1Beta1RouteStatus(accepted metav1.ConditionStatus, generation int64) v1beta1.HTTPRouteStatus {
+	reason := string(v1beta1.RouteReasonAccepted)
+	if accepted != metav1.ConditionTrue {
+		reason = string(v1beta1.RouteReasonNotAllowedByListeners)
+	}
+	return v1beta1.HTTPRouteStatus{
+		RouteStatus: v1beta1.RouteStatus{
+			Parents: []v1beta1.RouteParentStatus{
+				{
+					ParentRef: v1beta1.ParentReference{Name: "podinfo"},
+					Conditions: []metav1.Condition{
+						{Type: string(v1beta1.RouteConditionAccepted), Status: accepted, Reason: reason, ObservedGeneration: generation},
+						{Type: string(v1beta1.RouteConditionResolvedRefs), Status: metav1.ConditionTrue, Reason: string(v1beta1.RouteReasonResolvedRefs), ObservedGeneration: generation},
+					},
+				},
+			},
+		},
+	}
+}
@@ -258,6 +258,10 @@ func (gr *GlooRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
 
+func (gr *GlooRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
 func (gr *GlooRouter) createFlaggerUpstream(canary *flaggerv1.Canary, upstreamName string, isCanary bool) error {
 	_, primaryName, canaryName := canary.GetServiceNames()
 	upstreamClient := gr.glooClient.GlooV1().Upstreams(canary.Namespace)
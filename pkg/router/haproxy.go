@@ -0,0 +1,313 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+/*
+HAProxy Ingress Principles:
+* the ingress backend service selects the pods of both the primary and canary workloads
+* the blue-green balance annotation sets the weight of each group of pods, a group is matched by the workload selector label
+* the blue-green header annotation routes the requests to the group whose label value matches the header value
+
+Implementation:
+* apex Ingress is managed in place, only the blue-green annotations are changed
+* the backend servers are named after the pods so that the canary metrics can be told apart
+*/
+
+const (
+	haproxyAnnotationsPrefix         = "haproxy-ingress.github.io"
+	haproxyBlueGreenBalanceKey       = haproxyAnnotationsPrefix + "/blue-green-balance"
+	haproxyBlueGreenModeKey          = haproxyAnnotationsPrefix + "/blue-green-mode"
+	haproxyBlueGreenHeaderKey        = haproxyAnnotationsPrefix + "/blue-green-header"
+	haproxyBlueGreenCookieKey        = haproxyAnnotationsPrefix + "/blue-green-cookie"
+	haproxyBackendServerNamingKey    = haproxyAnnotationsPrefix + "/backend-server-naming"
+	haproxyBlueGreenModeDeploy       = "deploy"
+	haproxyBackendServerNamingPod    = "pod"
+	haproxyBlueGreenBalanceSeparator = ","
+)
+
+// HAProxyRouter is managing the blue-green annotations of an HAProxy Ingress
+type HAProxyRouter struct {
+	kubeClient    kubernetes.Interface
+	logger        *zap.SugaredLogger
+	labelSelector string
+}
+
+// Reconcile sets the blue-green annotations on the ingress while keeping the current weights
+func (hr *HAProxyRouter) Reconcile(canary *flaggerv1.Canary) error {
+	if canary.Spec.IngressRef == nil || canary.Spec.IngressRef.Name == "" {
+		return fmt.Errorf("ingress selector is empty")
+	}
+
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+	}
+
+	primaryWeight, canaryWeight := int(initialPrimaryWeight), int(initialCanaryWeight)
+	if _, ok := ingress.Annotations[haproxyBlueGreenBalanceKey]; ok {
+		primaryWeight, canaryWeight, _, err = hr.GetRoutes(canary)
+		if err != nil {
+			return err
+		}
+	}
+
+	clone := ingress.DeepCopy()
+	if err := hr.setAnnotations(canary, clone, primaryWeight, canaryWeight); err != nil {
+		return err
+	}
+
+	if cmp.Diff(ingress.Annotations, clone.Annotations) == "" {
+		return nil
+	}
+
+	_, err = hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s update error: %w", ingressName, canary.Namespace, err)
+	}
+
+	hr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+		Infof("Ingress %s.%s blue-green annotations updated", ingressName, canary.Namespace)
+	return nil
+}
+
+// GetRoutes returns the destinations weight for primary and canary
+func (hr *HAProxyRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+		return
+	}
+
+	// A/B testing
+	if len(canary.GetAnalysis().Match) > 0 {
+		_, hasHeader := ingress.Annotations[haproxyBlueGreenHeaderKey]
+		_, hasCookie := ingress.Annotations[haproxyBlueGreenCookieKey]
+		if hasHeader || hasCookie {
+			return 0, 100, false, nil
+		}
+		return 100, 0, false, nil
+	}
+
+	balance, ok := ingress.Annotations[haproxyBlueGreenBalanceKey]
+	if !ok {
+		err = fmt.Errorf("ingress %s.%s annotation %s not found", ingressName, canary.Namespace, haproxyBlueGreenBalanceKey)
+		return
+	}
+
+	primaryLabel, canaryLabel, err := hr.getGroupLabels(canary)
+	if err != nil {
+		return
+	}
+
+	for _, group := range strings.Split(balance, haproxyBlueGreenBalanceSeparator) {
+		idx := strings.LastIndex(group, "=")
+		if idx < 0 {
+			continue
+		}
+		weight, errAtoi := strconv.Atoi(group[idx+1:])
+		if errAtoi != nil {
+			err = fmt.Errorf("ingress %s.%s failed to convert %s to int: %w", ingressName, canary.Namespace, group, errAtoi)
+			return
+		}
+		switch group[:idx] {
+		case primaryLabel:
+			primaryWeight = weight
+		case canaryLabel:
+			canaryWeight = weight
+		}
+	}
+
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary,
+// for A/B testing the header and cookie routing is enabled when the canary weight is greater than zero
+func (hr *HAProxyRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+	}
+
+	clone := ingress.DeepCopy()
+	if err := hr.setAnnotations(canary, clone, primaryWeight, canaryWeight); err != nil {
+		return err
+	}
+
+	_, err = hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s update error: %w", ingressName, canary.Namespace, err)
+	}
+
+	return nil
+}
+
+// Finalize removes the blue-green annotations from the ingress
+func (hr *HAProxyRouter) Finalize(canary *flaggerv1.Canary) error {
+	if canary.Spec.IngressRef == nil || canary.Spec.IngressRef.Name == "" {
+		return nil
+	}
+
+	ingressName := canary.Spec.IngressRef.Name
+	ingress, err := hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
+	}
+
+	clone := ingress.DeepCopy()
+	for _, key := range []string{haproxyBlueGreenBalanceKey, haproxyBlueGreenModeKey, haproxyBlueGreenHeaderKey, haproxyBlueGreenCookieKey} {
+		delete(clone.Annotations, key)
+	}
+
+	_, err = hr.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("ingress %s.%s update error: %w", ingressName, canary.Namespace, err)
+	}
+
+	return nil
+}
+
+func (hr *HAProxyRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+func (hr *HAProxyRouter) setAnnotations(canary *flaggerv1.Canary, ingress *netv1.Ingress, primaryWeight int, canaryWeight int) error {
+	primaryLabel, canaryLabel, err := hr.getGroupLabels(canary)
+	if err != nil {
+		return err
+	}
+
+	if ingress.Annotations == nil {
+		ingress.Annotations = make(map[string]string)
+	}
+	delete(ingress.Annotations, haproxyBlueGreenHeaderKey)
+	delete(ingress.Annotations, haproxyBlueGreenCookieKey)
+
+	// A/B testing
+	if len(canary.GetAnalysis().Match) > 0 {
+		if canaryWeight > 0 {
+			if err := hr.setMatchAnnotations(canary, ingress.Annotations); err != nil {
+				return err
+			}
+		}
+		// the requests that don't match the conditions are routed to the primary
+		primaryWeight, canaryWeight = int(initialPrimaryWeight), int(initialCanaryWeight)
+	}
+
+	ingress.Annotations[haproxyBlueGreenModeKey] = haproxyBlueGreenModeDeploy
+	ingress.Annotations[haproxyBackendServerNamingKey] = haproxyBackendServerNamingPod
+	ingress.Annotations[haproxyBlueGreenBalanceKey] = strings.Join([]string{
+		fmt.Sprintf("%s=%d", primaryLabel, primaryWeight),
+		fmt.Sprintf("%s=%d", canaryLabel, canaryWeight),
+	}, haproxyBlueGreenBalanceSeparator)
+
+	return nil
+}
+
+// setMatchAnnotations maps the analysis match conditions to the blue-green header and cookie annotations,
+// HAProxy Ingress selects the group whose label value is equal to the header or cookie value
+// hence the match value must be the canary label value
+func (hr *HAProxyRouter) setMatchAnnotations(canary *flaggerv1.Canary, annotations map[string]string) error {
+	canaryValue, err := hr.getLabelValue(canary, false)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range canary.GetAnalysis().Match {
+		for k, v := range m.Headers {
+			if k == "cookie" {
+				if v.Exact == "" {
+					return fmt.Errorf("HAProxy doesn't support the specified cookie matching selector: %+v", v)
+				}
+				annotations[haproxyBlueGreenCookieKey] = fmt.Sprintf("%s:%s", v.Exact, hr.labelSelector)
+				continue
+			}
+			if v.Exact != canaryValue {
+				return fmt.Errorf("HAProxy requires the header %s to match exactly the canary label value %s", k, canaryValue)
+			}
+			annotations[haproxyBlueGreenHeaderKey] = fmt.Sprintf("%s:%s", k, hr.labelSelector)
+		}
+	}
+
+	return nil
+}
+
+// getGroupLabels returns the label name/value pairs that select the primary and canary pods
+func (hr *HAProxyRouter) getGroupLabels(canary *flaggerv1.Canary) (primaryLabel string, canaryLabel string, err error) {
+	primaryValue, err := hr.getLabelValue(canary, true)
+	if err != nil {
+		return
+	}
+	canaryValue, err := hr.getLabelValue(canary, false)
+	if err != nil {
+		return
+	}
+
+	return fmt.Sprintf("%s=%s", hr.labelSelector, primaryValue), fmt.Sprintf("%s=%s", hr.labelSelector, canaryValue), nil
+}
+
+// getLabelValue returns the selector label value of the primary or canary ClusterIP service
+func (hr *HAProxyRouter) getLabelValue(canary *flaggerv1.Canary, primary bool) (string, error) {
+	if hr.labelSelector == "" {
+		return "", fmt.Errorf("label selector is empty")
+	}
+
+	_, primaryName, canaryName := canary.GetServiceNames()
+	name := canaryName
+	if primary {
+		name = primaryName
+	}
+
+	svc, err := hr.kubeClient.CoreV1().Services(canary.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("service %s.%s get query error: %w", name, canary.Namespace, err)
+	}
+
+	value, ok := svc.Spec.Selector[hr.labelSelector]
+	if !ok {
+		return "", fmt.Errorf("service %s.%s selector %s not found", name, canary.Namespace, hr.labelSelector)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+func newTestHAProxyRouter(t *testing.T, mocks fixture) *HAProxyRouter {
+	for name, value := range map[string]string{"podinfo-primary": "podinfo-primary", "podinfo-canary": "podinfo"} {
+		_, err := mocks.kubeClient.CoreV1().Services("default").Create(context.TODO(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": value}},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	return &HAProxyRouter{
+		logger:        mocks.logger,
+		kubeClient:    mocks.kubeClient,
+		labelSelector: "app",
+	}
+}
+
+func TestHAProxyRouter_Reconcile(t *testing.T) {
+	mocks := newFixture(nil)
+	router := newTestHAProxyRouter(t, mocks)
+
+	err := router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingress, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "app=podinfo-primary=100,app=podinfo=0", ingress.Annotations[haproxyBlueGreenBalanceKey])
+	assert.Equal(t, "deploy", ingress.Annotations[haproxyBlueGreenModeKey])
+	assert.Equal(t, "pod", ingress.Annotations[haproxyBackendServerNamingKey])
+	assert.Equal(t, "nginx", ingress.Annotations["kubernetes.io/ingress.class"])
+
+	// weights are kept on reconciliation
+	err = router.SetRoutes(mocks.ingressCanary, 80, 20, false)
+	require.NoError(t, err)
+	err = router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	p, c, m, err := router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 80, p)
+	assert.Equal(t, 20, c)
+	assert.False(t, m)
+
+	err = router.Finalize(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingress, err = mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, ingress.Annotations, haproxyBlueGreenBalanceKey)
+	assert.NotContains(t, ingress.Annotations, haproxyBlueGreenModeKey)
+}
+
+func TestHAProxyRouter_ABTest(t *testing.T) {
+	mocks := newFixture(nil)
+	router := newTestHAProxyRouter(t, mocks)
+
+	mocks.ingressCanary.Spec.Analysis.Iterations = 10
+	mocks.ingressCanary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "podinfo"},
+			},
+		},
+	}
+
+	err := router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(mocks.ingressCanary, 0, 100, false)
+	require.NoError(t, err)
+
+	ingress, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "x-canary:app", ingress.Annotations[haproxyBlueGreenHeaderKey])
+	// the requests without the header are routed to the primary
+	assert.Equal(t, "app=podinfo-primary=100,app=podinfo=0", ingress.Annotations[haproxyBlueGreenBalanceKey])
+
+	p, c, _, err := router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 0, p)
+	assert.Equal(t, 100, c)
+
+	err = router.SetRoutes(mocks.ingressCanary, 100, 0, false)
+	require.NoError(t, err)
+
+	ingress, err = mocks.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, ingress.Annotations, haproxyBlueGreenHeaderKey)
+
+	// the header value must select the canary pods
+	mocks.ingressCanary.Spec.Analysis.Match[0].Headers["x-canary"] = istiov1alpha1.StringMatch{Exact: "insider"}
+	err = router.SetRoutes(mocks.ingressCanary, 0, 100, false)
+	require.Error(t, err)
+}
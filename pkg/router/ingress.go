@@ -40,18 +40,30 @@ type IngressRouter struct {
 	setOwnerRefs      bool
 }
 
+// Reconcile creates or updates the canary ingresses for all the ingresses referenced by the canary
 func (i *IngressRouter) Reconcile(canary *flaggerv1.Canary) error {
-	if canary.Spec.IngressRef == nil || canary.Spec.IngressRef.Name == "" {
+	refs := canary.GetIngressRefs()
+	if len(refs) == 0 {
 		return fmt.Errorf("ingress selector is empty")
 	}
 
+	for _, ref := range refs {
+		if err := i.reconcileIngress(canary, ref.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *IngressRouter) reconcileIngress(canary *flaggerv1.Canary, ingressName string) error {
 	apexName, _, _ := canary.GetServiceNames()
 	canaryName := fmt.Sprintf("%s-canary", apexName)
-	canaryIngressName := fmt.Sprintf("%s-canary", canary.Spec.IngressRef.Name)
+	canaryIngressName := fmt.Sprintf("%s-canary", ingressName)
 
-	ingress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), canary.Spec.IngressRef.Name, metav1.GetOptions{})
+	ingress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("ingress %s.%s get query error: %w", canary.Spec.IngressRef.Name, canary.Namespace, err)
+		return fmt.Errorf("ingress %s.%s get query error: %w", ingressName, canary.Namespace, err)
 	}
 
 	ingressClone := ingress.DeepCopy()
@@ -68,7 +80,7 @@ func (i *IngressRouter) Reconcile(canary *flaggerv1.Canary) error {
 	}
 
 	if !backendExists {
-		return fmt.Errorf("backend %s not found in ingress %s", apexName, canary.Spec.IngressRef.Name)
+		return fmt.Errorf("backend %s not found in ingress %s", apexName, ingressName)
 	}
 
 	canaryIngress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), canaryIngressName, metav1.GetOptions{})
@@ -121,24 +133,41 @@ func (i *IngressRouter) Reconcile(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+// GetRoutes returns the destinations weight for primary and canary,
+// if the canary ingresses are out of sync the lowest canary weight is returned
 func (i *IngressRouter) GetRoutes(canary *flaggerv1.Canary) (
 	primaryWeight int,
 	canaryWeight int,
 	mirrored bool,
 	err error,
 ) {
-	canaryIngressName := fmt.Sprintf("%s-canary", canary.Spec.IngressRef.Name)
-	canaryIngress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), canaryIngressName, metav1.GetOptions{})
+	canaryIngresses, err := i.getCanaryIngresses(canary)
 	if err != nil {
-		err = fmt.Errorf("ingress %s.%s get query error: %w", canaryIngressName, canary.Namespace, err)
 		return
 	}
 
+	for n, canaryIngress := range canaryIngresses {
+		weight, errWeight := i.getCanaryWeight(canary, canaryIngress)
+		if errWeight != nil {
+			err = fmt.Errorf("ingress %s.%s: %w", canaryIngress.Name, canaryIngress.Namespace, errWeight)
+			return
+		}
+		if n == 0 || weight < canaryWeight {
+			canaryWeight = weight
+		}
+	}
+
+	primaryWeight = 100 - canaryWeight
+	mirrored = false
+	return
+}
+
+func (i *IngressRouter) getCanaryWeight(canary *flaggerv1.Canary, canaryIngress *netv1.Ingress) (int, error) {
 	// A/B testing
 	if len(canary.GetAnalysis().Match) > 0 {
 		for k := range canaryIngress.Annotations {
 			if k == i.GetAnnotationWithPrefix("canary-by-cookie") || k == i.GetAnnotationWithPrefix("canary-by-header") {
-				return 0, 100, false, nil
+				return 100, nil
 			}
 		}
 	}
@@ -146,70 +175,88 @@ func (i *IngressRouter) GetRoutes(canary *flaggerv1.Canary) (
 	// Canary
 	for k, v := range canaryIngress.Annotations {
 		if k == i.GetAnnotationWithPrefix("canary-weight") {
-			val, errAtoi := strconv.Atoi(v)
-			if errAtoi != nil {
-				err = fmt.Errorf("failed to convert %s to int: %w", v, errAtoi)
-				return
+			val, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("failed to convert %s to int: %w", v, err)
 			}
-
-			canaryWeight = val
-			break
+			return val, nil
 		}
 	}
 
-	primaryWeight = 100 - canaryWeight
-	mirrored = false
-	return
+	return 0, nil
 }
 
+// SetRoutes updates the canary weight on all the canary ingresses,
+// the canary ingresses are fetched before any of them is updated
 func (i *IngressRouter) SetRoutes(
 	canary *flaggerv1.Canary,
 	_ int,
 	canaryWeight int,
 	_ bool,
 ) error {
-	canaryIngressName := fmt.Sprintf("%s-canary", canary.Spec.IngressRef.Name)
-	canaryIngress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), canaryIngressName, metav1.GetOptions{})
+	canaryIngresses, err := i.getCanaryIngresses(canary)
 	if err != nil {
-		return fmt.Errorf("ingress %s.%s get query error: %w", canaryIngressName, canary.Namespace, err)
+		return err
 	}
 
-	iClone := canaryIngress.DeepCopy()
+	for _, canaryIngress := range canaryIngresses {
+		iClone := canaryIngress.DeepCopy()
 
-	// A/B testing
-	if len(canary.GetAnalysis().Match) > 0 {
-		var cookie, header, headerValue, headerRegex string
-		for _, m := range canary.GetAnalysis().Match {
-			for k, v := range m.Headers {
-				if k == "cookie" {
-					cookie = v.Exact
-				} else {
-					header = k
-					headerRegex = v.Regex
-					headerValue = v.Exact
+		// A/B testing
+		if len(canary.GetAnalysis().Match) > 0 {
+			var cookie, header, headerValue, headerRegex string
+			for _, m := range canary.GetAnalysis().Match {
+				for k, v := range m.Headers {
+					if k == "cookie" {
+						cookie = v.Exact
+					} else {
+						header = k
+						headerRegex = v.Regex
+						headerValue = v.Exact
+					}
 				}
 			}
+
+			iClone.Annotations = i.makeHeaderAnnotations(iClone.Annotations, header, headerValue, headerRegex, cookie)
+		} else {
+			// canary
+			iClone.Annotations[i.GetAnnotationWithPrefix("canary-weight")] = fmt.Sprintf("%v", canaryWeight)
+		}
+
+		// toggle canary
+		if canaryWeight > 0 {
+			iClone.Annotations[i.GetAnnotationWithPrefix("canary")] = "true"
+		} else {
+			iClone.Annotations = i.makeAnnotations(iClone.Annotations)
 		}
 
-		iClone.Annotations = i.makeHeaderAnnotations(iClone.Annotations, header, headerValue, headerRegex, cookie)
-	} else {
-		// canary
-		iClone.Annotations[i.GetAnnotationWithPrefix("canary-weight")] = fmt.Sprintf("%v", canaryWeight)
+		_, err = i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), iClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("ingress %s.%s update error %v", iClone.Name, iClone.Namespace, err)
+		}
 	}
 
-	// toggle canary
-	if canaryWeight > 0 {
-		iClone.Annotations[i.GetAnnotationWithPrefix("canary")] = "true"
-	} else {
-		iClone.Annotations = i.makeAnnotations(iClone.Annotations)
+	return nil
+}
+
+// getCanaryIngresses returns the canary ingresses generated for the ingresses referenced by the canary
+func (i *IngressRouter) getCanaryIngresses(canary *flaggerv1.Canary) ([]*netv1.Ingress, error) {
+	refs := canary.GetIngressRefs()
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("ingress selector is empty")
 	}
 
-	_, err = i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Update(context.TODO(), iClone, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("ingress %s.%s update error %v", iClone.Name, iClone.Namespace, err)
+	canaryIngresses := make([]*netv1.Ingress, 0, len(refs))
+	for _, ref := range refs {
+		canaryIngressName := fmt.Sprintf("%s-canary", ref.Name)
+		canaryIngress, err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Get(context.TODO(), canaryIngressName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("ingress %s.%s get query error: %w", canaryIngressName, canary.Namespace, err)
+		}
+		canaryIngresses = append(canaryIngresses, canaryIngress)
 	}
 
-	return nil
+	return canaryIngresses, nil
 }
 
 func (i *IngressRouter) makeAnnotations(annotations map[string]string) map[string]string {
@@ -266,6 +313,19 @@ func (i *IngressRouter) GetAnnotationWithPrefix(suffix string) string {
 	return fmt.Sprintf("%v/%v", i.annotationsPrefix, suffix)
 }
 
-func (i *IngressRouter) Finalize(_ *flaggerv1.Canary) error {
+// Finalize removes the canary ingresses generated for ingressRef and the ingressRefs entries
+func (i *IngressRouter) Finalize(canary *flaggerv1.Canary) error {
+	for _, ref := range canary.GetIngressRefs() {
+		canaryIngressName := fmt.Sprintf("%s-canary", ref.Name)
+		err := i.kubeClient.NetworkingV1().Ingresses(canary.Namespace).Delete(context.TODO(), canaryIngressName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("ingress %s.%s delete error: %w", canaryIngressName, canary.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *IngressRouter) IsRouteReady(_ *flaggerv1.Canary) error {
 	return nil
 }
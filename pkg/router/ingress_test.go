@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -183,3 +184,68 @@ func TestIngressRouter_ABTest(t *testing.T) {
 		assert.Equal(t, "test", inCanary.Annotations[table.annotation])
 	}
 }
+
+func TestIngressRouter_MultipleIngresses(t *testing.T) {
+	mocks := newFixture(nil)
+	router := &IngressRouter{
+		logger:            mocks.logger,
+		kubeClient:        mocks.kubeClient,
+		annotationsPrefix: "nginx.ingress.kubernetes.io",
+	}
+
+	internal := newTestIngress()
+	internal.Name = "podinfo-internal"
+	internal.Spec.Rules[0].Host = "podinfo.internal"
+	_, err := mocks.kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), internal, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	mocks.ingressCanary.Spec.IngressRefs = []flaggerv1.LocalObjectReference{
+		{
+			Name:       "podinfo-internal",
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+		},
+	}
+
+	err = router.Reconcile(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(mocks.ingressCanary, 60, 40, false)
+	require.NoError(t, err)
+
+	for _, name := range []string{"podinfo-canary", "podinfo-internal-canary"} {
+		inCanary, err := router.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "40", inCanary.Annotations["nginx.ingress.kubernetes.io/canary-weight"])
+		assert.Equal(t, "podinfo-canary", inCanary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	}
+
+	p, c, _, err := router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+
+	// the lowest canary weight is returned when the ingresses are out of sync
+	inCanary, err := router.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "podinfo-internal-canary", metav1.GetOptions{})
+	require.NoError(t, err)
+	inCanary.Annotations["nginx.ingress.kubernetes.io/canary-weight"] = "20"
+	_, err = router.kubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), inCanary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	p, c, _, err = router.GetRoutes(mocks.ingressCanary)
+	require.NoError(t, err)
+	assert.Equal(t, 80, p)
+	assert.Equal(t, 20, c)
+
+	// the canary ingresses of ingressRef and the ingressRefs entries are removed
+	err = router.Finalize(mocks.ingressCanary)
+	require.NoError(t, err)
+
+	ingresses, err := router.kubeClient.NetworkingV1().Ingresses("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, ingresses.Items, 2)
+	for _, name := range []string{"podinfo-canary", "podinfo-internal-canary"} {
+		_, err = router.kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), name, metav1.GetOptions{})
+		assert.True(t, errors.IsNotFound(err))
+	}
+}
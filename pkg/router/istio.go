@@ -583,6 +583,10 @@ func (ir *IstioRouter) Finalize(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+func (ir *IstioRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
 // mergeMatchConditions appends the URI match rules to canary conditions
 func mergeMatchConditions(canary, defaults []istiov1alpha3.HTTPMatchRequest) []istiov1alpha3.HTTPMatchRequest {
 	if len(defaults) == 0 {
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	knativev1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+const (
+	knativePrimaryTag = "primary"
+	knativeCanaryTag  = "canary"
+)
+
+// KnativeRouter is managing the traffic split of Knative services,
+// the primary target is pinned to the promoted revision and
+// the canary target follows the latest revision of the service
+type KnativeRouter struct {
+	knativeClient clientset.Interface
+	logger        *zap.SugaredLogger
+}
+
+// Reconcile routes all the traffic to the primary revision
+// if the service traffic targets are not managed by Flagger
+func (kr *KnativeRouter) Reconcile(canary *flaggerv1.Canary) error {
+	service, err := kr.getService(canary)
+	if err != nil {
+		return err
+	}
+
+	primaryRevision, err := kr.getPrimaryRevision(service)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range service.Spec.Traffic {
+		if target.Tag == knativePrimaryTag && target.RevisionName == primaryRevision {
+			if _, _, _, err := kr.GetRoutes(canary); err == nil {
+				return nil
+			}
+		}
+	}
+
+	if err := kr.SetRoutes(canary, 100, 0, false); err != nil {
+		return err
+	}
+
+	kr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+		Infof("Service %s.%s traffic pinned to revision %s", service.Name, service.Namespace, primaryRevision)
+	return nil
+}
+
+// SetRoutes updates the traffic percentages of the primary and latest revisions
+func (kr *KnativeRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := kr.getService(canary)
+		if err != nil {
+			return err
+		}
+
+		primaryRevision, err := kr.getPrimaryRevision(service)
+		if err != nil {
+			return err
+		}
+
+		serviceCopy := service.DeepCopy()
+		serviceCopy.Spec.Traffic = []knativev1.TrafficTarget{
+			{
+				Tag:          knativePrimaryTag,
+				RevisionName: primaryRevision,
+				Percent:      int64p(int64(primaryWeight)),
+			},
+			{
+				Tag:            knativeCanaryTag,
+				LatestRevision: boolp(true),
+				Percent:        int64p(int64(canaryWeight)),
+			},
+		}
+
+		_, err = kr.knativeClient.ServingV1().Services(canary.Namespace).Update(context.TODO(), serviceCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("service %s.%s update error: %w", canary.Spec.TargetRef.Name, canary.Namespace, err)
+	}
+	return nil
+}
+
+// GetRoutes returns the traffic percentages of the primary and latest revisions
+func (kr *KnativeRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	service, err := kr.getService(canary)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	primaryWeight, canaryWeight = -1, -1
+	for _, target := range service.Spec.Traffic {
+		if target.Percent == nil {
+			continue
+		}
+		switch target.Tag {
+		case knativePrimaryTag:
+			primaryWeight = int(*target.Percent)
+		case knativeCanaryTag:
+			canaryWeight = int(*target.Percent)
+		}
+	}
+
+	if primaryWeight < 0 || canaryWeight < 0 {
+		err = fmt.Errorf("service %s.%s does not contain traffic targets tagged %s and %s",
+			service.Name, service.Namespace, knativePrimaryTag, knativeCanaryTag)
+		return 0, 0, false, err
+	}
+	return primaryWeight, canaryWeight, false, nil
+}
+
+// Finalize routes all the traffic to the latest revision of the service
+func (kr *KnativeRouter) Finalize(canary *flaggerv1.Canary) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := kr.getService(canary)
+		if err != nil {
+			return err
+		}
+
+		serviceCopy := service.DeepCopy()
+		serviceCopy.Spec.Traffic = []knativev1.TrafficTarget{
+			{
+				LatestRevision: boolp(true),
+				Percent:        int64p(100),
+			},
+		}
+
+		_, err = kr.knativeClient.ServingV1().Services(canary.Namespace).Update(context.TODO(), serviceCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("service %s.%s update error: %w", canary.Spec.TargetRef.Name, canary.Namespace, err)
+	}
+	return nil
+}
+
+// IsRouteReady returns an error if Knative hasn't programmed the traffic split yet
+func (kr *KnativeRouter) IsRouteReady(canary *flaggerv1.Canary) error {
+	service, err := kr.getService(canary)
+	if err != nil {
+		return err
+	}
+
+	if service.Generation > service.Status.ObservedGeneration {
+		return fmt.Errorf("service %s.%s traffic update not observed: %w", service.Name, service.Namespace, ErrRouteNotReady)
+	}
+
+	condition := service.Status.GetCondition(knativev1.ConditionRoutesReady)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		reason := ""
+		if condition != nil {
+			reason = fmt.Sprintf(": %s %s", condition.Reason, condition.Message)
+		}
+		return fmt.Errorf("service %s.%s routes not ready%s: %w", service.Name, service.Namespace, reason, ErrRouteNotReady)
+	}
+	return nil
+}
+
+func (kr *KnativeRouter) getService(canary *flaggerv1.Canary) (*knativev1.Service, error) {
+	service, err := kr.knativeClient.ServingV1().Services(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("service %s.%s get query error: %w", canary.Spec.TargetRef.Name, canary.Namespace, err)
+	}
+	return service, nil
+}
+
+func (kr *KnativeRouter) getPrimaryRevision(service *knativev1.Service) (string, error) {
+	revision := service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation]
+	if revision == "" {
+		return "", fmt.Errorf("service %s.%s has no primary revision", service.Name, service.Namespace)
+	}
+	return revision, nil
+}
+
+func boolp(b bool) *bool {
+	return &b
+}
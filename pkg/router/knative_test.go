@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	knativev1 "github.com/fluxcd/flagger/pkg/apis/knative/v1"
+)
+
+func TestKnativeRouter_Reconcile(t *testing.T) {
+	canary := newTestKnativeCanary()
+	mocks := newFixture(canary)
+	router := &KnativeRouter{
+		logger:        mocks.logger,
+		knativeClient: mocks.flaggerClient,
+	}
+
+	service := newTestKnativeService()
+	_, err := mocks.flaggerClient.ServingV1().Services("default").Create(context.TODO(), service, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	service, err = mocks.flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, service.Spec.Traffic, 2)
+	assert.Equal(t, "podinfo-00001", service.Spec.Traffic[0].RevisionName)
+	assert.Equal(t, int64(100), *service.Spec.Traffic[0].Percent)
+	assert.True(t, *service.Spec.Traffic[1].LatestRevision)
+	assert.Equal(t, int64(0), *service.Spec.Traffic[1].Percent)
+
+	// weights are kept when the canary is progressing
+	err = router.SetRoutes(canary, 60, 40, false)
+	require.NoError(t, err)
+
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	p, c, m, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+	assert.False(t, m)
+
+	// the traffic is reset when a new revision is promoted
+	service, err = mocks.flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	service.Annotations[flaggerv1.KnativePrimaryRevisionAnnotation] = "podinfo-00002"
+	_, err = mocks.flaggerClient.ServingV1().Services("default").Update(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	service, err = mocks.flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-00002", service.Spec.Traffic[0].RevisionName)
+	assert.Equal(t, int64(100), *service.Spec.Traffic[0].Percent)
+}
+
+func TestKnativeRouter_Finalize(t *testing.T) {
+	canary := newTestKnativeCanary()
+	mocks := newFixture(canary)
+	router := &KnativeRouter{
+		logger:        mocks.logger,
+		knativeClient: mocks.flaggerClient,
+	}
+
+	_, err := mocks.flaggerClient.ServingV1().Services("default").Create(context.TODO(), newTestKnativeService(), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.Finalize(canary)
+	require.NoError(t, err)
+
+	service, err := mocks.flaggerClient.ServingV1().Services("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, service.Spec.Traffic, 1)
+	assert.True(t, *service.Spec.Traffic[0].LatestRevision)
+	assert.Equal(t, int64(100), *service.Spec.Traffic[0].Percent)
+
+	_, _, _, err = router.GetRoutes(canary)
+	require.Error(t, err)
+}
+
+func TestKnativeRouter_IsRouteReady(t *testing.T) {
+	canary := newTestKnativeCanary()
+	mocks := newFixture(canary)
+	router := &KnativeRouter{
+		logger:        mocks.logger,
+		knativeClient: mocks.flaggerClient,
+	}
+
+	service := newTestKnativeService()
+	service.Generation = 2
+	service, err := mocks.flaggerClient.ServingV1().Services("default").Create(context.TODO(), service, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+
+	service.Status.ObservedGeneration = 2
+	service.Status.Conditions = []knativev1.Condition{
+		{Type: knativev1.ConditionRoutesReady, Status: corev1.ConditionUnknown, Reason: "IngressNotConfigured"},
+	}
+	service, err = mocks.flaggerClient.ServingV1().Services("default").UpdateStatus(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+	assert.Contains(t, err.Error(), "IngressNotConfigured")
+
+	service.Status.Conditions[0].Status = corev1.ConditionTrue
+	_, err = mocks.flaggerClient.ServingV1().Services("default").UpdateStatus(context.TODO(), service, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = router.IsRouteReady(canary)
+	require.NoError(t, err)
+}
+
+func newTestKnativeCanary() *flaggerv1.Canary {
+	canary := newTestCanary()
+	canary.Spec.Provider = flaggerv1.KnativeProvider
+	canary.Spec.TargetRef = flaggerv1.LocalObjectReference{
+		Name:       "podinfo",
+		APIVersion: "serving.knative.dev/v1",
+		Kind:       "Service",
+	}
+	return canary
+}
+
+func newTestKnativeService() *knativev1.Service {
+	return &knativev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: knativev1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "podinfo",
+			Annotations: map[string]string{
+				flaggerv1.KnativePrimaryRevisionAnnotation: "podinfo-00001",
+			},
+		},
+		Status: knativev1.ServiceStatus{
+			LatestReadyRevisionName:   "podinfo-00002",
+			LatestCreatedRevisionName: "podinfo-00002",
+		},
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	istiov1alpha3 "github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+)
+
+func TestKongRouter_Routes(t *testing.T) {
+	canary := newTestKongCanary()
+	mocks := newFixture(canary)
+	factory := NewFactory(nil, mocks.kubeClient, mocks.flaggerClient, "", "", mocks.logger, mocks.meshClient, false)
+
+	// Kong is driven through the Gateway API HTTPRoute
+	router := factory.MeshRouter(flaggerv1.KongProvider, "")
+	require.IsType(t, &GatewayAPIV1Beta1Router{}, router)
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	httpRoute, err := mocks.meshClient.GatewayapiV1beta1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, httpRoute.Spec.ParentRefs, 1)
+	assert.Equal(t, v1beta1.ObjectName("kong"), httpRoute.Spec.ParentRefs[0].Name)
+	assert.Equal(t, v1beta1.Namespace("kong"), *httpRoute.Spec.ParentRefs[0].Namespace)
+	assert.Equal(t, []v1beta1.Hostname{"app.example.com"}, httpRoute.Spec.Hostnames)
+
+	err = router.SetRoutes(canary, 90, 10, false)
+	require.NoError(t, err)
+
+	httpRoute, err = mocks.meshClient.GatewayapiV1beta1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, httpRoute.Spec.Rules, 1)
+	backendRefs := httpRoute.Spec.Rules[0].BackendRefs
+	require.Len(t, backendRefs, 2)
+	assert.Equal(t, v1beta1.ObjectName("podinfo-primary"), backendRefs[0].Name)
+	assert.Equal(t, int32(90), *backendRefs[0].Weight)
+	assert.Equal(t, v1beta1.ObjectName("podinfo-canary"), backendRefs[1].Name)
+	assert.Equal(t, int32(10), *backendRefs[1].Weight)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 90, p)
+	assert.Equal(t, 10, c)
+}
+
+func TestKongRouter_ABTest(t *testing.T) {
+	canary := newTestKongCanary()
+	canary.Spec.Analysis.Iterations = 10
+	canary.Spec.Analysis.Match = []istiov1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "insider"},
+			},
+		},
+	}
+	mocks := newFixture(canary)
+	factory := NewFactory(nil, mocks.kubeClient, mocks.flaggerClient, "", "", mocks.logger, mocks.meshClient, false)
+	router := factory.MeshRouter(flaggerv1.KongProvider, "")
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+	err = router.SetRoutes(canary, 0, 100, false)
+	require.NoError(t, err)
+
+	// the matching requests are routed to the canary by a separate rule
+	httpRoute, err := mocks.meshClient.GatewayapiV1beta1().HTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, httpRoute.Spec.Rules, 2)
+	require.Len(t, httpRoute.Spec.Rules[0].Matches, 1)
+	assert.Equal(t, v1beta1.HTTPHeaderName("x-canary"), httpRoute.Spec.Rules[0].Matches[0].Headers[0].Name)
+	assert.Equal(t, "insider", httpRoute.Spec.Rules[0].Matches[0].Headers[0].Value)
+	assert.Equal(t, int32(100), *httpRoute.Spec.Rules[0].BackendRefs[1].Weight)
+	assert.Equal(t, int32(100), *httpRoute.Spec.Rules[1].BackendRefs[0].Weight)
+}
+
+func newTestKongCanary() *flaggerv1.Canary {
+	ns := v1beta1.Namespace("kong")
+	cd := newTestGatewayAPICanary()
+	cd.Spec.Provider = flaggerv1.KongProvider
+	cd.Spec.Service.Hosts = []string{"app.example.com"}
+	cd.Spec.Service.GatewayRefs = []v1beta1.ParentReference{
+		{
+			Name:      "kong",
+			Namespace: &ns,
+		},
+	}
+	return cd
+}
@@ -41,7 +41,7 @@ func (kr *KumaRouter) Reconcile(canary *flaggerv1.Canary) error {
 		Destinations: []*kumav1alpha1.Selector{
 			{
 				Match: map[string]string{
-					"kuma.io/service": fmt.Sprintf("%s_%s_svc_%d", apexName, canary.Namespace, canary.Spec.Service.Port),
+					"kuma.io/service": kumaServiceName(apexName, canary.Namespace, canary.Spec.Service.Port),
 				},
 			},
 		},
@@ -50,13 +50,13 @@ func (kr *KumaRouter) Reconcile(canary *flaggerv1.Canary) error {
 				{
 					Weight: uint32(100),
 					Destination: map[string]string{
-						"kuma.io/service": fmt.Sprintf("%s_%s_svc_%d", primaryName, canary.Namespace, canary.Spec.Service.Port),
+						"kuma.io/service": kumaServiceName(primaryName, canary.Namespace, canary.Spec.Service.Port),
 					},
 				},
 				{
 					Weight: uint32(0),
 					Destination: map[string]string{
-						"kuma.io/service": fmt.Sprintf("%s_%s_svc_%d", canaryName, canary.Namespace, canary.Spec.Service.Port),
+						"kuma.io/service": kumaServiceName(canaryName, canary.Namespace, canary.Spec.Service.Port),
 					},
 				},
 			},
@@ -79,11 +79,6 @@ func (kr *KumaRouter) Reconcile(canary *flaggerv1.Canary) error {
 			metadata.Annotations[fmt.Sprintf("%d.service.kuma.io", canary.Spec.Service.Port)] = "http"
 		}
 
-		meshName, ok := canary.Annotations["kuma.io/mesh"]
-		if !ok {
-			meshName = "default"
-		}
-
 		// TrafficRoute is a cluster-scoped object, hence we don't set an owner reference.
 		t := &kumav1alpha1.TrafficRoute{
 			ObjectMeta: metav1.ObjectMeta{
@@ -91,7 +86,7 @@ func (kr *KumaRouter) Reconcile(canary *flaggerv1.Canary) error {
 				Annotations: filterMetadata(metadata.Annotations),
 			},
 			Spec: trSpec,
-			Mesh: meshName,
+			Mesh: kumaMeshName(canary),
 		}
 
 		_, err := kr.kumaClient.KumaV1alpha1().TrafficRoutes().Create(context.TODO(), t, metav1.CreateOptions{})
@@ -177,13 +172,13 @@ func (kr *KumaRouter) SetRoutes(
 			{
 				Weight: uint32(primaryWeight),
 				Destination: map[string]string{
-					"kuma.io/service": fmt.Sprintf("%s_%s_svc_%d", primaryName, canary.Namespace, canary.Spec.Service.Port),
+					"kuma.io/service": kumaServiceName(primaryName, canary.Namespace, canary.Spec.Service.Port),
 				},
 			},
 			{
 				Weight: uint32(canaryWeight),
 				Destination: map[string]string{
-					"kuma.io/service": fmt.Sprintf("%s_%s_svc_%d", canaryName, canary.Namespace, canary.Spec.Service.Port),
+					"kuma.io/service": kumaServiceName(canaryName, canary.Namespace, canary.Spec.Service.Port),
 				},
 			},
 		},
@@ -203,3 +198,7 @@ func (kr *KumaRouter) SetRoutes(
 func (kr *KumaRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (kr *KumaRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+	kumav1alpha1 "github.com/fluxcd/flagger/pkg/apis/kuma/v1alpha1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+// KumaMeshHTTPRouteRouter is managing targetRef based MeshHTTPRoute policies
+type KumaMeshHTTPRouteRouter struct {
+	kubeClient   kubernetes.Interface
+	kumaClient   clientset.Interface
+	logger       *zap.SugaredLogger
+	setOwnerRefs bool
+}
+
+// Reconcile creates or updates the Kuma MeshHTTPRoute and
+// removes the legacy TrafficRoute generated for the same canary
+func (kr *KumaMeshHTTPRouteRouter) Reconcile(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	route, err := kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+
+	// create MeshHTTPRoute
+	if errors.IsNotFound(err) {
+		// carry over the weights of the legacy TrafficRoute if a canary is in progress
+		primaryWeight, canaryWeight := int(initialPrimaryWeight), int(initialCanaryWeight)
+		if tr, err := kr.kumaClient.KumaV1alpha1().TrafficRoutes().Get(context.TODO(), apexName, metav1.GetOptions{}); err == nil {
+			primaryWeight, canaryWeight = kumaTrafficRouteWeights(canary, tr)
+		}
+
+		spec, err := kr.makeSpec(canary, primaryWeight, canaryWeight)
+		if err != nil {
+			return err
+		}
+
+		metadata := canary.Spec.Service.Apex
+		if metadata == nil {
+			metadata = &flaggerv1.CustomMetadata{}
+		}
+		if metadata.Annotations == nil {
+			metadata.Annotations = make(map[string]string)
+		}
+		labels := make(map[string]string)
+		for k, v := range metadata.Labels {
+			labels[k] = v
+		}
+		labels["kuma.io/mesh"] = kumaMeshName(canary)
+
+		route = &kumav1alpha1.MeshHTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        apexName,
+				Namespace:   canary.Namespace,
+				Labels:      labels,
+				Annotations: filterMetadata(metadata.Annotations),
+			},
+			Spec: spec,
+		}
+		if kr.setOwnerRefs {
+			route.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(canary, schema.GroupVersionKind{
+					Group:   flaggerv1.SchemeGroupVersion.Group,
+					Version: flaggerv1.SchemeGroupVersion.Version,
+					Kind:    flaggerv1.CanaryKind,
+				}),
+			}
+		}
+
+		_, err = kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("MeshHTTPRoute %s.%s create error: %w", apexName, canary.Namespace, err)
+		}
+
+		kr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("MeshHTTPRoute %s.%s created", route.GetName(), canary.Namespace)
+		return kr.deleteTrafficRoute(canary)
+	} else if err != nil {
+		return fmt.Errorf("MeshHTTPRoute %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	// update MeshHTTPRoute but keep the current weights
+	primaryWeight, canaryWeight, err := kr.getWeights(canary, route)
+	if err != nil {
+		primaryWeight, canaryWeight = int(initialPrimaryWeight), int(initialCanaryWeight)
+	}
+	spec, err := kr.makeSpec(canary, primaryWeight, canaryWeight)
+	if err != nil {
+		return err
+	}
+
+	if diff := cmp.Diff(spec, route.Spec); diff != "" {
+		clone := route.DeepCopy()
+		clone.Spec = spec
+
+		_, err := kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("MeshHTTPRoute %s.%s update error: %w", apexName, canary.Namespace, err)
+		}
+
+		kr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("MeshHTTPRoute %s.%s updated", apexName, canary.Namespace)
+	}
+
+	return kr.deleteTrafficRoute(canary)
+}
+
+// GetRoutes returns the destinations weight for primary and canary
+func (kr *KumaMeshHTTPRouteRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	apexName, _, _ := canary.GetServiceNames()
+	route, err := kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("MeshHTTPRoute %s.%s get query error: %w", apexName, canary.Namespace, err)
+		return
+	}
+
+	primaryWeight, canaryWeight, err = kr.getWeights(canary, route)
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary
+func (kr *KumaMeshHTTPRouteRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	apexName, _, _ := canary.GetServiceNames()
+	route, err := kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("MeshHTTPRoute %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	spec, err := kr.makeSpec(canary, primaryWeight, canaryWeight)
+	if err != nil {
+		return err
+	}
+
+	clone := route.DeepCopy()
+	clone.Spec = spec
+
+	_, err = kr.kumaClient.KumaV1alpha1().MeshHTTPRoutes(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("MeshHTTPRoute %s.%s update error: %w", apexName, canary.Namespace, err)
+	}
+
+	return nil
+}
+
+func (kr *KumaMeshHTTPRouteRouter) Finalize(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+func (kr *KumaMeshHTTPRouteRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// getWeights returns the weights of the rule that routes to both primary and canary
+func (kr *KumaMeshHTTPRouteRouter) getWeights(canary *flaggerv1.Canary, route *kumav1alpha1.MeshHTTPRoute) (int, int, error) {
+	apexName, primaryName, canaryName := canary.GetServiceNames()
+	primaryService := kumaServiceName(primaryName, canary.Namespace, canary.Spec.Service.Port)
+	canaryService := kumaServiceName(canaryName, canary.Namespace, canary.Spec.Service.Port)
+
+	for _, to := range route.Spec.To {
+		for _, rule := range to.Rules {
+			// A/B testing: skip the rule that routes only to the primary
+			if len(rule.Default.BackendRefs) != 2 {
+				continue
+			}
+			primaryWeight, canaryWeight := -1, -1
+			for _, ref := range rule.Default.BackendRefs {
+				weight := 0
+				if ref.Weight != nil {
+					weight = int(*ref.Weight)
+				}
+				switch ref.Name {
+				case primaryService:
+					primaryWeight = weight
+				case canaryService:
+					canaryWeight = weight
+				}
+			}
+			if primaryWeight >= 0 && canaryWeight >= 0 {
+				return primaryWeight, canaryWeight, nil
+			}
+		}
+	}
+
+	return 0, 0, fmt.Errorf("MeshHTTPRoute %s.%s does not contain routes for %s and %s",
+		apexName, canary.Namespace, primaryName, canaryName)
+}
+
+// makeSpec returns a MeshHTTPRoute spec that targets the apex service with the given weights,
+// when the analysis has match conditions the weights are applied only to the matching requests
+func (kr *KumaMeshHTTPRouteRouter) makeSpec(canary *flaggerv1.Canary, primaryWeight int, canaryWeight int) (kumav1alpha1.MeshHTTPRouteSpec, error) {
+	apexName, primaryName, canaryName := canary.GetServiceNames()
+	port := canary.Spec.Service.Port
+
+	defaultMatches := []kumav1alpha1.MeshHTTPRouteMatch{
+		{
+			Path: &kumav1alpha1.PathMatch{
+				Type:  kumav1alpha1.PathMatchPathPrefix,
+				Value: "/",
+			},
+		},
+	}
+	rules := []kumav1alpha1.MeshHTTPRouteRule{
+		{
+			Matches: defaultMatches,
+			Default: kumav1alpha1.MeshHTTPRouteRuleConf{
+				BackendRefs: []kumav1alpha1.BackendRef{
+					kumaBackendRef(kumaServiceName(primaryName, canary.Namespace, port), primaryWeight),
+					kumaBackendRef(kumaServiceName(canaryName, canary.Namespace, port), canaryWeight),
+				},
+			},
+		},
+	}
+
+	// A/B testing
+	if len(canary.GetAnalysis().Match) > 0 {
+		matches, err := kr.mapRouteMatches(canary.GetAnalysis().Match)
+		if err != nil {
+			return kumav1alpha1.MeshHTTPRouteSpec{}, fmt.Errorf("invalid request matching selectors: %w", err)
+		}
+		rules[0].Matches = matches
+		rules = append(rules, kumav1alpha1.MeshHTTPRouteRule{
+			Matches: defaultMatches,
+			Default: kumav1alpha1.MeshHTTPRouteRuleConf{
+				BackendRefs: []kumav1alpha1.BackendRef{
+					kumaBackendRef(kumaServiceName(primaryName, canary.Namespace, port), int(initialPrimaryWeight)),
+				},
+			},
+		})
+	}
+
+	return kumav1alpha1.MeshHTTPRouteSpec{
+		TargetRef: kumav1alpha1.TargetRef{
+			Kind: kumav1alpha1.TargetRefKindMesh,
+		},
+		To: []kumav1alpha1.MeshHTTPRouteTo{
+			{
+				TargetRef: kumav1alpha1.TargetRef{
+					Kind: kumav1alpha1.TargetRefKindMeshService,
+					Name: kumaServiceName(apexName, canary.Namespace, port),
+				},
+				Rules: rules,
+			},
+		},
+	}, nil
+}
+
+func (kr *KumaMeshHTTPRouteRouter) mapRouteMatches(requestMatches []v1alpha3.HTTPMatchRequest) ([]kumav1alpha1.MeshHTTPRouteMatch, error) {
+	var matches []kumav1alpha1.MeshHTTPRouteMatch
+
+	for _, requestMatch := range requestMatches {
+		match := kumav1alpha1.MeshHTTPRouteMatch{}
+		if requestMatch.Uri != nil {
+			switch {
+			case requestMatch.Uri.Exact != "":
+				match.Path = &kumav1alpha1.PathMatch{Type: kumav1alpha1.PathMatchExact, Value: requestMatch.Uri.Exact}
+			case requestMatch.Uri.Prefix != "":
+				match.Path = &kumav1alpha1.PathMatch{Type: kumav1alpha1.PathMatchPathPrefix, Value: requestMatch.Uri.Prefix}
+			case requestMatch.Uri.Regex != "":
+				match.Path = &kumav1alpha1.PathMatch{Type: kumav1alpha1.PathMatchRegularExpression, Value: requestMatch.Uri.Regex}
+			default:
+				return nil, fmt.Errorf("Kuma doesn't support the specified path matching selector: %+v", requestMatch.Uri)
+			}
+		}
+
+		if requestMatch.Method != nil {
+			if requestMatch.Method.Exact == "" {
+				return nil, fmt.Errorf("Kuma doesn't support the specified method matching selector: %+v", requestMatch.Method)
+			}
+			method := strings.ToUpper(requestMatch.Method.Exact)
+			match.Method = &method
+		}
+
+		// sort the headers to generate a stable spec
+		headers := make([]string, 0, len(requestMatch.Headers))
+		for key := range requestMatch.Headers {
+			headers = append(headers, key)
+		}
+		sort.Strings(headers)
+		for _, key := range headers {
+			val := requestMatch.Headers[key]
+			var matchType kumav1alpha1.HeaderMatchType
+			var value string
+			switch {
+			case val.Exact != "":
+				matchType, value = kumav1alpha1.HeaderMatchExact, val.Exact
+			case val.Prefix != "":
+				matchType, value = kumav1alpha1.HeaderMatchPrefix, val.Prefix
+			case val.Regex != "":
+				matchType, value = kumav1alpha1.HeaderMatchRegularExpression, val.Regex
+			default:
+				return nil, fmt.Errorf("Kuma doesn't support the specified header matching selector: %+v", val)
+			}
+			match.Headers = append(match.Headers, kumav1alpha1.HeaderMatch{
+				Type:  &matchType,
+				Name:  key,
+				Value: value,
+			})
+		}
+
+		params := make([]string, 0, len(requestMatch.QueryParams))
+		for key := range requestMatch.QueryParams {
+			params = append(params, key)
+		}
+		sort.Strings(params)
+		for _, key := range params {
+			val := requestMatch.QueryParams[key]
+			queryMatch := kumav1alpha1.QueryParamsMatch{Name: key}
+			switch {
+			case val.Exact != "":
+				queryMatch.Type, queryMatch.Value = kumav1alpha1.QueryParamsMatchExact, val.Exact
+			case val.Regex != "":
+				queryMatch.Type, queryMatch.Value = kumav1alpha1.QueryParamsMatchRegularExpression, val.Regex
+			default:
+				return nil, fmt.Errorf("Kuma doesn't support the specified query matching selector: %+v", val)
+			}
+			match.QueryParams = append(match.QueryParams, queryMatch)
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// deleteTrafficRoute removes the legacy TrafficRoute after switching to MeshHTTPRoute
+func (kr *KumaMeshHTTPRouteRouter) deleteTrafficRoute(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	err := kr.kumaClient.KumaV1alpha1().TrafficRoutes().Delete(context.TODO(), apexName, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("TrafficRoute %s delete error: %w", apexName, err)
+	}
+
+	kr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+		Infof("TrafficRoute %s deleted, routing is managed by MeshHTTPRoute %s.%s", apexName, apexName, canary.Namespace)
+	return nil
+}
+
+// kumaTrafficRouteWeights returns the primary and canary weights of a legacy TrafficRoute
+func kumaTrafficRouteWeights(canary *flaggerv1.Canary, tr *kumav1alpha1.TrafficRoute) (int, int) {
+	_, primaryName, _ := canary.GetServiceNames()
+	if tr.Spec.Conf != nil {
+		for _, split := range tr.Spec.Conf.Split {
+			if strings.Split(split.Destination["kuma.io/service"], "_")[0] == primaryName {
+				return int(split.Weight), 100 - int(split.Weight)
+			}
+		}
+	}
+	return int(initialPrimaryWeight), int(initialCanaryWeight)
+}
+
+func kumaBackendRef(service string, weight int) kumav1alpha1.BackendRef {
+	w := uint(weight)
+	return kumav1alpha1.BackendRef{
+		TargetRef: kumav1alpha1.TargetRef{
+			Kind: kumav1alpha1.TargetRefKindMeshService,
+			Name: service,
+		},
+		Weight: &w,
+	}
+}
+
+// kumaServiceName returns the kuma.io/service tag value of a Kubernetes service
+func kumaServiceName(name string, namespace string, port int32) string {
+	return fmt.Sprintf("%s_%s_svc_%d", name, namespace, port)
+}
+
+func kumaMeshName(canary *flaggerv1.Canary) string {
+	if meshName, ok := canary.Annotations["kuma.io/mesh"]; ok {
+		return meshName
+	}
+	return "default"
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	istiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/istio/common/v1alpha1"
+	"github.com/fluxcd/flagger/pkg/apis/istio/v1alpha3"
+	kumav1alpha1 "github.com/fluxcd/flagger/pkg/apis/kuma/v1alpha1"
+)
+
+func TestKumaMeshHTTPRouteRouter_Reconcile(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+	router := &KumaMeshHTTPRouteRouter{
+		logger:     mocks.logger,
+		kumaClient: mocks.meshClient,
+		kubeClient: mocks.kubeClient,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	route, err := router.kumaClient.KumaV1alpha1().MeshHTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "default", route.Labels["kuma.io/mesh"])
+	assert.Equal(t, kumav1alpha1.TargetRefKindMesh, route.Spec.TargetRef.Kind)
+	require.Len(t, route.Spec.To, 1)
+	assert.Equal(t, "podinfo_default_svc_80", route.Spec.To[0].TargetRef.Name)
+
+	require.Len(t, route.Spec.To[0].Rules, 1)
+	refs := route.Spec.To[0].Rules[0].Default.BackendRefs
+	require.Len(t, refs, 2)
+	assert.Equal(t, "podinfo-primary_default_svc_80", refs[0].Name)
+	assert.Equal(t, uint(100), *refs[0].Weight)
+	assert.Equal(t, "podinfo-canary_default_svc_80", refs[1].Name)
+	assert.Equal(t, uint(0), *refs[1].Weight)
+
+	// weights are kept when the spec changes
+	err = router.SetRoutes(canary, 60, 40, false)
+	require.NoError(t, err)
+
+	canary.Spec.Analysis.Match = []v1alpha3.HTTPMatchRequest{
+		{
+			Headers: map[string]istiov1alpha1.StringMatch{
+				"x-canary": {Exact: "insider"},
+				"user":     {Prefix: "test"},
+			},
+		},
+	}
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	route, err = router.kumaClient.KumaV1alpha1().MeshHTTPRoutes("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	rules := route.Spec.To[0].Rules
+	require.Len(t, rules, 2)
+	require.Len(t, rules[0].Matches, 1)
+	headers := rules[0].Matches[0].Headers
+	require.Len(t, headers, 2)
+	assert.Equal(t, "user", headers[0].Name)
+	assert.Equal(t, kumav1alpha1.HeaderMatchPrefix, *headers[0].Type)
+	assert.Equal(t, "x-canary", headers[1].Name)
+	assert.Equal(t, "insider", headers[1].Value)
+	assert.Len(t, rules[1].Default.BackendRefs, 1)
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+}
+
+func TestKumaMeshHTTPRouteRouter_Routes(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+	router := &KumaMeshHTTPRouteRouter{
+		logger:     mocks.logger,
+		kumaClient: mocks.meshClient,
+		kubeClient: mocks.kubeClient,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(canary, 50, 50, false)
+	require.NoError(t, err)
+
+	p, c, m, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 50, p)
+	assert.Equal(t, 50, c)
+	assert.False(t, m)
+}
+
+func TestKumaMeshHTTPRouteRouter_MigrateTrafficRoute(t *testing.T) {
+	canary := newTestSMICanary()
+	mocks := newFixture(canary)
+
+	// create a TrafficRoute with the legacy router
+	legacy := &KumaRouter{
+		logger:        mocks.logger,
+		flaggerClient: mocks.flaggerClient,
+		kumaClient:    mocks.meshClient,
+		kubeClient:    mocks.kubeClient,
+	}
+	err := legacy.Reconcile(canary)
+	require.NoError(t, err)
+	err = legacy.SetRoutes(canary, 70, 30, false)
+	require.NoError(t, err)
+
+	router := &KumaMeshHTTPRouteRouter{
+		logger:     mocks.logger,
+		kumaClient: mocks.meshClient,
+		kubeClient: mocks.kubeClient,
+	}
+	err = router.Reconcile(canary)
+	require.NoError(t, err)
+
+	// weights are carried over
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 70, p)
+	assert.Equal(t, 30, c)
+
+	// TrafficRoute is removed
+	_, err = router.kumaClient.KumaV1alpha1().TrafficRoutes().Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// MultiRouter shifts the traffic on several providers at once,
+// e.g. an ingress controller for the edge traffic and a service mesh for the east-west traffic
+type MultiRouter struct {
+	logger  *zap.SugaredLogger
+	routers []providerRouter
+}
+
+type providerRouter struct {
+	provider string
+	router   Interface
+}
+
+// Reconcile creates or updates the routing objects of each provider
+func (mr *MultiRouter) Reconcile(canary *flaggerv1.Canary) error {
+	for _, pr := range mr.routers {
+		if err := pr.router.Reconcile(canary); err != nil {
+			return fmt.Errorf("%s router: %w", pr.provider, err)
+		}
+	}
+	return nil
+}
+
+// GetRoutes returns the destinations weight for primary and canary,
+// if the providers are out of sync the lowest canary weight is returned
+// so that the next SetRoutes call brings all of them to the same weight
+func (mr *MultiRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	var weights []string
+	inSync := true
+	for n, pr := range mr.routers {
+		p, c, m, errRoutes := pr.router.GetRoutes(canary)
+		if errRoutes != nil {
+			err = fmt.Errorf("%s router: %w", pr.provider, errRoutes)
+			return
+		}
+		weights = append(weights, fmt.Sprintf("%s %d/%d", pr.provider, p, c))
+
+		if n == 0 {
+			primaryWeight, canaryWeight, mirrored = p, c, m
+			continue
+		}
+		if p != primaryWeight || c != canaryWeight || m != mirrored {
+			inSync = false
+		}
+		if c < canaryWeight {
+			primaryWeight, canaryWeight = p, c
+		}
+		mirrored = mirrored && m
+	}
+
+	if !inSync {
+		mr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Warnf("Routes are out of sync (%s), using primary %d canary %d",
+				strings.Join(weights, ", "), primaryWeight, canaryWeight)
+	}
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary on each provider
+func (mr *MultiRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+) error {
+	// set the routes on every provider even if some of them fail,
+	// e.g. a route that is not ready must not prevent the rollback of the others
+	var errs []error
+	for _, pr := range mr.routers {
+		if err := pr.router.SetRoutes(canary, primaryWeight, canaryWeight, mirrored); err != nil {
+			errs = append(errs, fmt.Errorf("%s router: %w", pr.provider, err))
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// Finalize reverts the routing objects of each provider,
+// all the providers are finalized even if some of them fail
+func (mr *MultiRouter) Finalize(canary *flaggerv1.Canary) error {
+	var errs []error
+	for _, pr := range mr.routers {
+		if err := pr.router.Finalize(canary); err != nil {
+			errs = append(errs, fmt.Errorf("%s router: %w", pr.provider, err))
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// IsRouteReady returns an error if the routes of any provider are not ready
+func (mr *MultiRouter) IsRouteReady(canary *flaggerv1.Canary) error {
+	for _, pr := range mr.routers {
+		if err := pr.router.IsRouteReady(canary); err != nil {
+			return fmt.Errorf("%s router: %w", pr.provider, err)
+		}
+	}
+	return nil
+}
+
+// joinedErrors is an error wrapping the errors returned by several providers
+type joinedErrors []error
+
+// joinErrors returns an error wrapping the given errors, or nil if there are none
+func joinErrors(errs ...error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return joinedErrors(errs)
+}
+
+func (e joinedErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the joined errors matches the target
+func (e joinedErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the joined errors
+func (e joinedErrors) Unwrap() []error {
+	return e
+}
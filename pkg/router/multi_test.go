@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+type fakeWeightRouter struct {
+	NopRouter
+	primaryWeight int
+	canaryWeight  int
+	finalizeErr   error
+	finalized     bool
+}
+
+func (r *fakeWeightRouter) GetRoutes(_ *flaggerv1.Canary) (int, int, bool, error) {
+	return r.primaryWeight, r.canaryWeight, false, nil
+}
+
+func (r *fakeWeightRouter) SetRoutes(_ *flaggerv1.Canary, primaryWeight int, canaryWeight int, _ bool) error {
+	r.primaryWeight, r.canaryWeight = primaryWeight, canaryWeight
+	return nil
+}
+
+func (r *fakeWeightRouter) Finalize(_ *flaggerv1.Canary) error {
+	r.finalized = true
+	return r.finalizeErr
+}
+
+func TestMultiRouter_Routes(t *testing.T) {
+	mocks := newFixture(nil)
+	nginx := &fakeWeightRouter{primaryWeight: 100}
+	linkerd := &fakeWeightRouter{primaryWeight: 100}
+	router := &MultiRouter{
+		logger: mocks.logger,
+		routers: []providerRouter{
+			{provider: "nginx", router: nginx},
+			{provider: "linkerd", router: linkerd},
+		},
+	}
+
+	err := router.SetRoutes(mocks.canary, 70, 30, false)
+	require.NoError(t, err)
+	assert.Equal(t, 30, nginx.canaryWeight)
+	assert.Equal(t, 30, linkerd.canaryWeight)
+
+	p, c, _, err := router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 70, p)
+	assert.Equal(t, 30, c)
+
+	// the lowest canary weight is returned when the providers are out of sync
+	linkerd.primaryWeight, linkerd.canaryWeight = 90, 10
+	p, c, _, err = router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 90, p)
+	assert.Equal(t, 10, c)
+}
+
+func TestMultiRouter_Finalize(t *testing.T) {
+	mocks := newFixture(nil)
+	nginx := &fakeWeightRouter{finalizeErr: fmt.Errorf("ingress not found")}
+	linkerd := &fakeWeightRouter{}
+	router := &MultiRouter{
+		logger: mocks.logger,
+		routers: []providerRouter{
+			{provider: "nginx", router: nginx},
+			{provider: "linkerd", router: linkerd},
+		},
+	}
+
+	err := router.Finalize(mocks.canary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nginx router: ingress not found")
+	assert.True(t, linkerd.finalized)
+}
+
+func TestFactory_MeshRouters(t *testing.T) {
+	mocks := newFixture(nil)
+	factory := NewFactory(nil, mocks.kubeClient, mocks.flaggerClient, "", "", mocks.logger, mocks.meshClient, false)
+
+	_, ok := factory.MeshRouters([]string{flaggerv1.NGINXProvider}, "app").(*IngressRouter)
+	assert.True(t, ok)
+
+	router, ok := factory.MeshRouters([]string{flaggerv1.NGINXProvider, flaggerv1.LinkerdProvider}, "app").(*MultiRouter)
+	require.True(t, ok)
+	require.Len(t, router.routers, 2)
+	assert.IsType(t, &IngressRouter{}, router.routers[0].router)
+	assert.IsType(t, &SmiRouter{}, router.routers[1].router)
+}
+
+type notReadyRouter struct {
+	NopRouter
+	err error
+}
+
+func (r *notReadyRouter) SetRoutes(_ *flaggerv1.Canary, _ int, _ int, _ bool) error {
+	return r.err
+}
+
+func TestMultiRouter_SetRoutesNotReady(t *testing.T) {
+	mocks := newFixture(nil)
+	gateway := &notReadyRouter{err: fmt.Errorf("HTTPRoute not accepted: %w", ErrRouteNotReady)}
+	linkerd := &fakeWeightRouter{primaryWeight: 50, canaryWeight: 50}
+	router := &MultiRouter{
+		logger: mocks.logger,
+		routers: []providerRouter{
+			{provider: "gatewayapi", router: gateway},
+			{provider: "linkerd", router: linkerd},
+		},
+	}
+
+	// the routers after a not ready route are set
+	err := router.SetRoutes(mocks.canary, 100, 0, false)
+	require.ErrorIs(t, err, ErrRouteNotReady)
+	assert.Equal(t, 100, linkerd.primaryWeight)
+	assert.Equal(t, 0, linkerd.canaryWeight)
+
+	// other errors are not masked by a not ready route
+	linkerd2 := &notReadyRouter{err: fmt.Errorf("update failed")}
+	router.routers = append(router.routers, providerRouter{provider: "linkerd2", router: linkerd2})
+	err = router.SetRoutes(mocks.canary, 100, 0, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// NodeRouter rolls out a DaemonSet canary to a subset of nodes by labeling them,
+// the canary weight is mapped to the percentage of the selected nodes running the canary
+// while the primary pods are evicted from the labeled nodes
+type NodeRouter struct {
+	kubeClient kubernetes.Interface
+	logger     *zap.SugaredLogger
+}
+
+// Reconcile validates the node rollout settings
+func (nr *NodeRouter) Reconcile(canary *flaggerv1.Canary) error {
+	label := canary.GetNodeRolloutLabel()
+	if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+		return fmt.Errorf("node rollout label %s is invalid: %s", label, strings.Join(errs, ", "))
+	}
+
+	rollout := canary.GetAnalysis().NodeRollout
+	if rollout != nil && len(rollout.NodePools) > 0 && rollout.PoolLabel == "" {
+		return fmt.Errorf("node rollout of %s.%s must set a pool label to select the node pools",
+			canary.Name, canary.Namespace)
+	}
+	return nil
+}
+
+// GetRoutes returns the canary weight recorded on the nodes running the canary
+func (nr *NodeRouter) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	label := canary.GetNodeRolloutLabel()
+	nodes, err := nr.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: label})
+	if err != nil {
+		err = fmt.Errorf("nodes list query error: %w", err)
+		return
+	}
+
+	for _, node := range nodes.Items {
+		if w, errConv := strconv.Atoi(node.Annotations[label]); errConv == nil && w > canaryWeight {
+			canaryWeight = w
+		}
+	}
+	primaryWeight = 100 - canaryWeight
+	return
+}
+
+// SetRoutes labels the share of the selected nodes given by the canary weight
+// and removes the label from the other nodes
+func (nr *NodeRouter) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	_ bool,
+) error {
+	label := canary.GetNodeRolloutLabel()
+	candidates, err := nr.selectNodes(canary)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	if total := primaryWeight + canaryWeight; total > 0 && canaryWeight > 0 {
+		count = int(math.Ceil(float64(len(candidates)*canaryWeight) / float64(total)))
+		if count > len(candidates) {
+			count = len(candidates)
+		}
+	}
+	if canaryWeight > 0 && count == 0 {
+		return fmt.Errorf("no nodes match the node rollout selection of %s.%s", canary.Name, canary.Namespace)
+	}
+
+	selected := make(map[string]bool, count)
+	weight := strconv.Itoa(canaryWeight)
+	for _, node := range candidates[:count] {
+		selected[node.Name] = true
+		if node.Labels[label] == "true" && node.Annotations[label] == weight {
+			continue
+		}
+		if err := nr.patchNode(node.Name, label, weight); err != nil {
+			return err
+		}
+	}
+
+	labeled, err := nr.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: label})
+	if err != nil {
+		return fmt.Errorf("nodes list query error: %w", err)
+	}
+	for _, node := range labeled.Items {
+		if selected[node.Name] {
+			continue
+		}
+		if err := nr.patchNode(node.Name, label, ""); err != nil {
+			return err
+		}
+	}
+
+	nr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+		Debugf("Canary rolled out to %d of %d nodes", count, len(candidates))
+	return nil
+}
+
+// Finalize removes the node rollout label from all nodes
+func (nr *NodeRouter) Finalize(canary *flaggerv1.Canary) error {
+	label := canary.GetNodeRolloutLabel()
+	nodes, err := nr.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: label})
+	if err != nil {
+		return fmt.Errorf("nodes list query error: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		if err := nr.patchNode(node.Name, label, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRouteReady returns nil since the node labels are applied synchronously
+func (nr *NodeRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// selectNodes returns the nodes matching the node rollout selection,
+// ordered by node pool and name so that the rollout order is stable
+func (nr *NodeRouter) selectNodes(canary *flaggerv1.Canary) ([]corev1.Node, error) {
+	rollout := canary.GetAnalysis().NodeRollout
+	if rollout == nil {
+		return nil, nil
+	}
+
+	nodes, err := nr.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(rollout.NodeSelector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nodes list query error: %w", err)
+	}
+
+	pools := make(map[string]int, len(rollout.NodePools))
+	for i, pool := range rollout.NodePools {
+		pools[pool] = i
+	}
+
+	var candidates []corev1.Node
+	for _, node := range nodes.Items {
+		if len(pools) > 0 {
+			if _, ok := pools[node.Labels[rollout.PoolLabel]]; !ok {
+				continue
+			}
+		}
+		candidates = append(candidates, node)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := pools[candidates[i].Labels[rollout.PoolLabel]], pools[candidates[j].Labels[rollout.PoolLabel]]
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates, nil
+}
+
+// patchNode sets the node rollout label and the canary weight annotation,
+// an empty weight removes both from the node
+func (nr *NodeRouter) patchNode(name string, label string, weight string) error {
+	var labelValue, annotationValue interface{}
+	if weight != "" {
+		labelValue, annotationValue = "true", weight
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{label: labelValue},
+			"annotations": map[string]interface{}{label: annotationValue},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("node %s patch marshal error: %w", name, err)
+	}
+
+	_, err = nr.kubeClient.CoreV1().Nodes().Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("node %s patch error: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestNodeRouter_SetRoutes(t *testing.T) {
+	canary := newTestNodeRolloutCanary()
+	mocks := newFixture(canary)
+	createTestNodes(t, mocks.kubeClient)
+	router := &NodeRouter{
+		kubeClient: mocks.kubeClient,
+		logger:     mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.NoError(t, err)
+
+	err = router.SetRoutes(canary, 75, 25, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a1"}, canaryNodes(t, mocks.kubeClient, canary))
+
+	p, c, _, err := router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 75, p)
+	assert.Equal(t, 25, c)
+
+	// the nodes of the first pool are rolled out first
+	err = router.SetRoutes(canary, 50, 50, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a1", "node-a2"}, canaryNodes(t, mocks.kubeClient, canary))
+
+	err = router.SetRoutes(canary, 40, 60, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a1", "node-a2", "node-b1"}, canaryNodes(t, mocks.kubeClient, canary))
+
+	p, c, _, err = router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 40, p)
+	assert.Equal(t, 60, c)
+
+	// the nodes are handed back to the primary on rollback
+	err = router.SetRoutes(canary, 100, 0, false)
+	require.NoError(t, err)
+	assert.Empty(t, canaryNodes(t, mocks.kubeClient, canary))
+
+	p, c, _, err = router.GetRoutes(canary)
+	require.NoError(t, err)
+	assert.Equal(t, 100, p)
+	assert.Equal(t, 0, c)
+}
+
+func TestNodeRouter_NodeSelector(t *testing.T) {
+	canary := newTestNodeRolloutCanary()
+	canary.Spec.Analysis.NodeRollout = &flaggerv1.NodeRollout{
+		NodeSelector: map[string]string{"pool": "b"},
+	}
+	mocks := newFixture(canary)
+	createTestNodes(t, mocks.kubeClient)
+	router := &NodeRouter{
+		kubeClient: mocks.kubeClient,
+		logger:     mocks.logger,
+	}
+
+	err := router.SetRoutes(canary, 90, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-b1"}, canaryNodes(t, mocks.kubeClient, canary))
+
+	err = router.Finalize(canary)
+	require.NoError(t, err)
+	assert.Empty(t, canaryNodes(t, mocks.kubeClient, canary))
+
+	// no node matches the selection
+	canary.Spec.Analysis.NodeRollout.NodeSelector = map[string]string{"pool": "d"}
+	err = router.SetRoutes(canary, 90, 10, false)
+	require.Error(t, err)
+}
+
+func TestNodeRouter_Reconcile(t *testing.T) {
+	canary := newTestNodeRolloutCanary()
+	canary.Spec.Analysis.NodeRollout.PoolLabel = ""
+	mocks := newFixture(canary)
+	router := &NodeRouter{
+		kubeClient: mocks.kubeClient,
+		logger:     mocks.logger,
+	}
+
+	err := router.Reconcile(canary)
+	require.Error(t, err)
+}
+
+func TestFactory_CanaryRouters(t *testing.T) {
+	mocks := newFixture(nil)
+	factory := NewFactory(nil, mocks.kubeClient, mocks.flaggerClient, "", "", mocks.logger, mocks.meshClient, false)
+	canary := newTestNodeRolloutCanary()
+
+	_, ok := factory.CanaryRouters(canary, []string{flaggerv1.KubernetesProvider}, "app").(*NodeRouter)
+	assert.True(t, ok)
+
+	router, ok := factory.CanaryRouters(canary, []string{flaggerv1.LinkerdProvider}, "app").(*MultiRouter)
+	require.True(t, ok)
+	require.Len(t, router.routers, 2)
+	assert.IsType(t, &NodeRouter{}, router.routers[0].router)
+	assert.IsType(t, &SmiRouter{}, router.routers[1].router)
+
+	canary.Spec.Analysis.NodeRollout = nil
+	_, ok = factory.CanaryRouters(canary, []string{flaggerv1.KubernetesProvider}, "app").(*NopRouter)
+	assert.True(t, ok)
+}
+
+func newTestNodeRolloutCanary() *flaggerv1.Canary {
+	canary := newTestSMICanary()
+	canary.Spec.TargetRef.Kind = "DaemonSet"
+	canary.Spec.Analysis.NodeRollout = &flaggerv1.NodeRollout{
+		PoolLabel: "pool",
+		NodePools: []string{"a", "b"},
+	}
+	return canary
+}
+
+func createTestNodes(t *testing.T, kubeClient kubernetes.Interface) {
+	nodes := map[string]string{
+		"node-b1": "b",
+		"node-a2": "a",
+		"node-a1": "a",
+		"node-b2": "b",
+		"node-c1": "c",
+	}
+	for name, pool := range nodes {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"pool": pool},
+			},
+		}
+		_, err := kubeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+}
+
+func canaryNodes(t *testing.T, kubeClient kubernetes.Interface, canary *flaggerv1.Canary) []string {
+	nodes, err := kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: canary.GetNodeRolloutLabel(),
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+	return names
+}
@@ -42,3 +42,7 @@ func (*NopRouter) GetRoutes(canary *flaggerv1.Canary) (primaryWeight int, canary
 func (c *NopRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (c *NopRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
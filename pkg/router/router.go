@@ -16,14 +16,26 @@ limitations under the License.
 
 package router
 
-import flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+import (
+	"errors"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
 
 const configAnnotation = "flagger.kubernetes.io/original-configuration"
 const kubectlAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
 
+// ErrRouteNotReady is returned when the routing objects were updated
+// but haven't been accepted by the ingress controller or service mesh,
+// the operation can be retried once the routes become ready
+var ErrRouteNotReady = errors.New("route not ready")
+
 type Interface interface {
 	Reconcile(canary *flaggerv1.Canary) error
 	SetRoutes(canary *flaggerv1.Canary, primaryWeight int, canaryWeight int, mirrored bool) error
 	GetRoutes(canary *flaggerv1.Canary) (primaryWeight int, canaryWeight int, mirrored bool, err error)
 	Finalize(canary *flaggerv1.Canary) error
+	// IsRouteReady returns an error wrapping ErrRouteNotReady if the routing objects
+	// were rejected or not yet accepted by the ingress controller or service mesh
+	IsRouteReady(canary *flaggerv1.Canary) error
 }
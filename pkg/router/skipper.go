@@ -225,6 +225,10 @@ func (skp *SkipperRouter) Finalize(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+func (skp *SkipperRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
 func (skp *SkipperRouter) makeAnnotations(annotations map[string]string, backendWeights map[string]int) map[string]string {
 	b, err := json.Marshal(backendWeights)
 	if err != nil {
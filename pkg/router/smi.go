@@ -250,3 +250,7 @@ func (sr *SmiRouter) getWithConvert(canary *flaggerv1.Canary, host string) (*smi
 func (sr *SmiRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (sr *SmiRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
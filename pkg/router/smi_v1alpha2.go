@@ -199,3 +199,7 @@ func (sr *Smiv1alpha2Router) makeAnnotations(gateways []string) map[string]strin
 func (sr *Smiv1alpha2Router) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (sr *Smiv1alpha2Router) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
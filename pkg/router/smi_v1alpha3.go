@@ -199,3 +199,7 @@ func (sr *Smiv1alpha3Router) makeAnnotations(gateways []string) map[string]strin
 func (sr *Smiv1alpha3Router) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (sr *Smiv1alpha3Router) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
@@ -211,3 +211,7 @@ func (tr *TraefikRouter) SetRoutes(
 func (tr *TraefikRouter) Finalize(_ *flaggerv1.Canary) error {
 	return nil
 }
+
+func (tr *TraefikRouter) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	traefikiov1alpha1 "github.com/fluxcd/flagger/pkg/apis/traefikio/v1alpha1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+// TraefikV3Router is managing Traefik v3 services (traefik.io API group)
+type TraefikV3Router struct {
+	traefikClient clientset.Interface
+	logger        *zap.SugaredLogger
+	setOwnerRefs  bool
+}
+
+// Reconcile creates or updates the Traefik service
+func (tr *TraefikV3Router) Reconcile(canary *flaggerv1.Canary) error {
+	apexName, _, _ := canary.GetServiceNames()
+
+	newSpec := tr.makeWeightedSpec(canary, 100, 0)
+
+	traefikService, err := tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		tsMetadata := canary.Spec.Service.Apex
+		if tsMetadata == nil {
+			tsMetadata = &flaggerv1.CustomMetadata{}
+		}
+		if tsMetadata.Labels == nil {
+			tsMetadata.Labels = make(map[string]string)
+		}
+		if tsMetadata.Annotations == nil {
+			tsMetadata.Annotations = make(map[string]string)
+		}
+
+		traefikService = &traefikiov1alpha1.TraefikService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        apexName,
+				Namespace:   canary.Namespace,
+				Labels:      tsMetadata.Labels,
+				Annotations: filterMetadata(tsMetadata.Annotations),
+			},
+			Spec: newSpec,
+		}
+		if tr.setOwnerRefs {
+			traefikService.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(canary, schema.GroupVersionKind{
+					Group:   flaggerv1.SchemeGroupVersion.Group,
+					Version: flaggerv1.SchemeGroupVersion.Version,
+					Kind:    flaggerv1.CanaryKind,
+				}),
+			}
+		}
+
+		_, err = tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Create(context.TODO(), traefikService, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("TraefikService %s.%s create error: %w", apexName, canary.Namespace, err)
+		}
+		tr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TraefikService %s.%s created", traefikService.GetName(), canary.Namespace)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("TraefikService %s.%s get query error: %w", apexName, canary.Namespace, err)
+	}
+
+	// the mirroring spec is managed by SetRoutes during the analysis
+	if traefikService.Spec.Mirroring != nil {
+		return nil
+	}
+
+	// update TraefikService but keep the original service weights
+	if traefikService.Spec.Weighted != nil && len(traefikService.Spec.Weighted.Services) == 2 {
+		newSpec = tr.makeWeightedSpec(canary, 100, 100)
+	}
+
+	if diff := cmp.Diff(
+		newSpec,
+		traefikService.Spec,
+		cmpopts.IgnoreFields(traefikiov1alpha1.Service{}, "Weight"),
+	); diff != "" {
+		clone := traefikService.DeepCopy()
+		clone.Spec = newSpec
+		if traefikService.Spec.Weighted != nil {
+			for i := range clone.Spec.Weighted.Services {
+				if i < len(traefikService.Spec.Weighted.Services) {
+					clone.Spec.Weighted.Services[i].Weight = traefikService.Spec.Weighted.Services[i].Weight
+				}
+			}
+		}
+
+		_, err = tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Update(context.TODO(), clone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("TraefikService %s.%s update error: %w", apexName, canary.Namespace, err)
+		}
+		tr.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).
+			Infof("TraefikService %s.%s updated", traefikService.GetName(), canary.Namespace)
+	}
+
+	return nil
+}
+
+// GetRoutes returns the destinations weight for primary and canary
+func (tr *TraefikV3Router) GetRoutes(canary *flaggerv1.Canary) (
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+	err error,
+) {
+	apexName, primaryName, _ := canary.GetServiceNames()
+
+	traefikService, err := tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("TraefikService %s.%s query error: %w", apexName, canary.Namespace, err)
+		return
+	}
+
+	// all traffic goes to the primary while it's mirrored to the canary
+	if traefikService.Spec.Mirroring != nil {
+		return 100, 0, true, nil
+	}
+
+	if traefikService.Spec.Weighted == nil || len(traefikService.Spec.Weighted.Services) < 1 {
+		err = fmt.Errorf("TraefikService %s.%s services not found", apexName, canary.Namespace)
+		return
+	}
+
+	for _, s := range traefikService.Spec.Weighted.Services {
+		if s.Name == primaryName && s.Weight != nil {
+			primaryWeight = *s.Weight
+			canaryWeight = 100 - primaryWeight
+			return
+		}
+	}
+
+	return
+}
+
+// SetRoutes updates the destinations weight for primary and canary,
+// when mirrored is true the primary traffic is mirrored to the canary
+func (tr *TraefikV3Router) SetRoutes(
+	canary *flaggerv1.Canary,
+	primaryWeight int,
+	canaryWeight int,
+	mirrored bool,
+) error {
+	apexName, primaryName, canaryName := canary.GetServiceNames()
+
+	if primaryWeight == 0 && canaryWeight == 0 {
+		return fmt.Errorf("TraefikService %s.%s update failed: no valid weights", apexName, canary.Namespace)
+	}
+	traefikService, err := tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("TraefikService %s.%s query error: %w", apexName, canary.Namespace, err)
+	}
+
+	if mirrored {
+		percent := 100
+		if mw := canary.GetAnalysis().MirrorWeight; mw > 0 {
+			percent = mw
+		}
+		traefikService.Spec = traefikiov1alpha1.ServiceSpec{
+			Mirroring: &traefikiov1alpha1.Mirroring{
+				Name:      primaryName,
+				Namespace: canary.Namespace,
+				Port:      canary.Spec.Service.Port,
+				Mirrors: []traefikiov1alpha1.Mirror{
+					{
+						Name:      canaryName,
+						Namespace: canary.Namespace,
+						Port:      canary.Spec.Service.Port,
+						Percent:   percent,
+					},
+				},
+			},
+		}
+	} else {
+		traefikService.Spec = tr.makeWeightedSpec(canary, primaryWeight, canaryWeight)
+	}
+
+	_, err = tr.traefikClient.TraefikIOV1alpha1().TraefikServices(canary.Namespace).Update(context.TODO(), traefikService, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("TraefikService %s.%s update error: %w", apexName, canary.Namespace, err)
+	}
+	return nil
+}
+
+func (tr *TraefikV3Router) Finalize(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+func (tr *TraefikV3Router) IsRouteReady(_ *flaggerv1.Canary) error {
+	return nil
+}
+
+// makeWeightedSpec returns a weighted round robin spec, the canary service is included
+// only if it has a weight and the sticky cookie is set if session affinity is enabled
+func (tr *TraefikV3Router) makeWeightedSpec(canary *flaggerv1.Canary, primaryWeight int, canaryWeight int) traefikiov1alpha1.ServiceSpec {
+	_, primaryName, canaryName := canary.GetServiceNames()
+
+	weighted := &traefikiov1alpha1.WeightedRoundRobin{
+		Services: []traefikiov1alpha1.Service{
+			{
+				Name:      primaryName,
+				Namespace: canary.Namespace,
+				Port:      canary.Spec.Service.Port,
+				Weight:    &primaryWeight,
+			},
+		},
+	}
+	if canaryWeight > 0 {
+		weighted.Services = append(weighted.Services, traefikiov1alpha1.Service{
+			Name:      canaryName,
+			Namespace: canary.Namespace,
+			Port:      canary.Spec.Service.Port,
+			Weight:    &canaryWeight,
+		})
+	}
+
+	if sa := canary.GetAnalysis().SessionAffinity; sa != nil {
+		weighted.Sticky = &traefikiov1alpha1.Sticky{
+			Cookie: &traefikiov1alpha1.Cookie{
+				Name:     sa.CookieName,
+				HTTPOnly: true,
+				MaxAge:   sa.GetMaxAge(),
+			},
+		}
+	}
+
+	return traefikiov1alpha1.ServiceSpec{Weighted: weighted}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestTraefikV3Router_Reconcile(t *testing.T) {
+	mocks := newFixture(nil)
+	router := &TraefikV3Router{
+		traefikClient: mocks.meshClient,
+		logger:        mocks.logger,
+	}
+
+	require.NoError(t, router.Reconcile(mocks.canary))
+	ts, err := router.traefikClient.TraefikIOV1alpha1().TraefikServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	services := ts.Spec.Weighted.Services
+	require.Len(t, services, 1)
+	assert.Equal(t, "podinfo-primary", services[0].Name)
+	assert.Equal(t, 100, *services[0].Weight)
+	assert.Nil(t, ts.Spec.Weighted.Sticky)
+
+	// weights are kept when the canary is progressing
+	require.NoError(t, router.SetRoutes(mocks.canary, 60, 40, false))
+	require.NoError(t, router.Reconcile(mocks.canary))
+
+	p, c, m, err := router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+	assert.False(t, m)
+}
+
+func TestTraefikV3Router_Routes(t *testing.T) {
+	mocks := newFixture(nil)
+	router := &TraefikV3Router{
+		traefikClient: mocks.meshClient,
+		logger:        mocks.logger,
+	}
+
+	require.NoError(t, router.Reconcile(mocks.canary))
+
+	require.NoError(t, router.SetRoutes(mocks.canary, 50, 50, false))
+	ts, err := router.traefikClient.TraefikIOV1alpha1().TraefikServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, ts.Spec.Weighted.Services, 2)
+	assert.Equal(t, "podinfo-canary", ts.Spec.Weighted.Services[1].Name)
+	assert.Equal(t, 50, *ts.Spec.Weighted.Services[1].Weight)
+
+	// mirror traffic to the canary
+	mocks.canary.Spec.Analysis.MirrorWeight = 20
+	require.NoError(t, router.SetRoutes(mocks.canary, 100, 0, true))
+	ts, err = router.traefikClient.TraefikIOV1alpha1().TraefikServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, ts.Spec.Weighted)
+	require.NotNil(t, ts.Spec.Mirroring)
+	assert.Equal(t, "podinfo-primary", ts.Spec.Mirroring.Name)
+	require.Len(t, ts.Spec.Mirroring.Mirrors, 1)
+	assert.Equal(t, "podinfo-canary", ts.Spec.Mirroring.Mirrors[0].Name)
+	assert.Equal(t, 20, ts.Spec.Mirroring.Mirrors[0].Percent)
+
+	p, c, m, err := router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 100, p)
+	assert.Equal(t, 0, c)
+	assert.True(t, m)
+
+	// switch back to weighted routing
+	require.NoError(t, router.SetRoutes(mocks.canary, 100, 0, false))
+	p, c, m, err = router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 100, p)
+	assert.Equal(t, 0, c)
+	assert.False(t, m)
+}
+
+func TestTraefikV3Router_SessionAffinity(t *testing.T) {
+	mocks := newFixture(nil)
+	mocks.canary.Spec.Analysis.SessionAffinity = &flaggerv1.SessionAffinity{
+		CookieName: "flagger-cookie",
+		MaxAge:     3600,
+	}
+	router := &TraefikV3Router{
+		traefikClient: mocks.meshClient,
+		logger:        mocks.logger,
+	}
+
+	require.NoError(t, router.Reconcile(mocks.canary))
+	require.NoError(t, router.SetRoutes(mocks.canary, 90, 10, false))
+
+	ts, err := router.traefikClient.TraefikIOV1alpha1().TraefikServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, ts.Spec.Weighted.Sticky)
+	require.NotNil(t, ts.Spec.Weighted.Sticky.Cookie)
+	assert.Equal(t, "flagger-cookie", ts.Spec.Weighted.Sticky.Cookie.Name)
+	assert.Equal(t, 3600, ts.Spec.Weighted.Sticky.Cookie.MaxAge)
+	assert.True(t, ts.Spec.Weighted.Sticky.Cookie.HTTPOnly)
+}